@@ -0,0 +1,165 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	kamv1 "github.com/kappnav/operator/pkg/apis/actions/v1"
+	kamv2 "github.com/kappnav/operator/pkg/apis/actions/v2"
+	"github.com/kappnav/operator/pkg/kam"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/yaml"
+)
+
+const kamUsage = `Usage: %s kam explain [flags] APIVERSION KIND NAME
+
+Explains which action config maps the KindActionMappings map a resource to,
+in the order in which they are used, and which of them exist.
+
+Flags:
+`
+
+// runKAMCommand runs the kam subcommand with the arguments that follow it and
+// returns the exit code.
+func runKAMCommand(args []string) int {
+	flags := pflag.NewFlagSet("kam explain", pflag.ContinueOnError)
+	namespace := flags.StringP("namespace", "n", "default", "namespace of the resource")
+	kamNamespace := flags.String("kam-namespace", "", "namespace of the KindActionMappings, all namespaces if empty")
+	kappnavNamespace := flags.String("kappnav-namespace", "kappnav", "namespace of kAppNav, searched for action config maps after the namespace of the resource")
+	kamFiles := flags.StringSlice("kam-file", nil, "read the KindActionMappings from these files instead of the cluster")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, kamUsage, os.Args[0])
+		flags.PrintDefaults()
+	}
+
+	if len(args) == 0 || args[0] != "explain" {
+		flags.Usage()
+		return 2
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if flags.NArg() != 3 {
+		flags.Usage()
+		return 2
+	}
+	apiVersion, kind, name := flags.Arg(0), flags.Arg(1), flags.Arg(2)
+
+	if err := explainKAM(apiVersion, kind, *namespace, name, *kamNamespace, *kappnavNamespace, *kamFiles, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func explainKAM(apiVersion string, kind string, namespace string, name string, kamNamespace string,
+	kappnavNamespace string, kamFiles []string, out io.Writer) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return err
+	}
+	if err := kamv1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		return err
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	resource, err := kam.GetResource(c, apiVersion, kind, namespace, name)
+	if err != nil {
+		return err
+	}
+	var kams []kamv2.KindActionMapping
+	if len(kamFiles) > 0 {
+		for _, file := range kamFiles {
+			fileKAMs, err := readKAMFile(file)
+			if err != nil {
+				return fmt.Errorf("%s: %v", file, err)
+			}
+			kams = append(kams, fileKAMs...)
+		}
+	} else if kams, err = kam.ListKindActionMappings(c, kamNamespace); err != nil {
+		return err
+	}
+
+	namespaces := []string{namespace}
+	if len(kappnavNamespace) > 0 && kappnavNamespace != namespace {
+		namespaces = append(namespaces, kappnavNamespace)
+	}
+	explanation, err := kam.Explain(resource, kams, namespaces, kam.ConfigMapLookupFor(c))
+	if err != nil {
+		return err
+	}
+	return explanation.Write(out)
+}
+
+// readKAMFile reads the v1 and v2 KindActionMappings in a YAML or JSON file,
+// which may contain several documents.
+func readKAMFile(fileName string) ([]kamv2.KindActionMapping, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var kams []kamv2.KindActionMapping
+	decoder := k8syaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		raw := runtime.RawExtension{}
+		if err := decoder.Decode(&raw); err == io.EOF {
+			return kams, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(raw.Raw) == 0 {
+			continue
+		}
+		v1 := &kamv1.KindActionMapping{}
+		if err := yaml.Unmarshal(raw.Raw, v1); err != nil {
+			return nil, err
+		}
+		if v1.Kind != "KindActionMapping" {
+			return nil, fmt.Errorf("%s %s is not a KindActionMapping", v1.APIVersion, v1.Kind)
+		}
+		switch v1.APIVersion {
+		case kamv1.SchemeGroupVersion.String():
+			v2 := kamv2.KindActionMapping{}
+			if err := kamv2.ConvertFromV1(v1, &v2); err != nil {
+				return nil, err
+			}
+			kams = append(kams, v2)
+		case kamv2.SchemeGroupVersion.String():
+			v2 := kamv2.KindActionMapping{}
+			if err := yaml.Unmarshal(raw.Raw, &v2); err != nil {
+				return nil, err
+			}
+			kams = append(kams, v2)
+		default:
+			return nil, fmt.Errorf("unsupported KindActionMapping version %s", v1.APIVersion)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	"github.com/kappnav/operator/pkg/lint"
+	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const lintUsage = `Usage: %s lint [flags] [FILE|DIR]...
+
+Checks action, section and status config map files, by default the maps
+shipped in maps/action, maps/sections and maps/status. The files are rendered
+as templates against a Kappnav CR with the default values, as the operator
+does, before they are checked. Exits with 1 if errors are found.
+
+Flags:
+`
+
+// runLintCommand runs the lint subcommand with the arguments that follow it and
+// returns the exit code.
+func runLintCommand(args []string) int {
+	flags := pflag.NewFlagSet("lint", pflag.ContinueOnError)
+	kubeEnv := flags.String("kubeenv", "", "kubeEnv of the Kappnav CR the files are rendered against, the default value if empty")
+	warnings := flags.Bool("warnings", true, "report warnings as well as errors")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, lintUsage, os.Args[0])
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"maps/action", "maps/sections", "maps/status"}
+	}
+
+	instance := &kappnavv1.Kappnav{}
+	if err := kappnavutils.SetKappnavDefaults(instance); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(*kubeEnv) > 0 {
+		if instance.Spec.Env == nil {
+			instance.Spec.Env = &kappnavv1.Environment{}
+		}
+		instance.Spec.Env.KubeEnv = *kubeEnv
+	}
+
+	hasErrors, err := lintFiles(paths, instance, *warnings, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if hasErrors {
+		return 1
+	}
+	return 0
+}
+
+// lintFiles checks the config map files at paths, expanding directories to the
+// YAML files they contain, and returns whether any errors were found.
+func lintFiles(paths []string, instance *kappnavv1.Kappnav, warnings bool, out io.Writer) (bool, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return false, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && (strings.HasSuffix(entry.Name(), ".yaml") || strings.HasSuffix(entry.Name(), ".yml")) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	hasErrors := false
+	for _, file := range files {
+		configMap, err := renderConfigMapFile(file, instance)
+		if err != nil {
+			return false, fmt.Errorf("%s: %v", file, err)
+		}
+		issues := lint.ConfigMap(configMap)
+		hasErrors = hasErrors || lint.HasErrors(issues)
+		for _, issue := range issues {
+			if warnings || issue.Severity == lint.SeverityError {
+				fmt.Fprintf(out, "%s: %s\n", file, issue)
+			}
+		}
+	}
+	return hasErrors, nil
+}
+
+// renderConfigMapFile renders a config map file as a template against the
+// Kappnav CR and the data of the extensions, in the same way as the operator
+// does when it creates the maps.
+func renderConfigMapFile(fileName string, instance *kappnavv1.Kappnav) (*corev1.ConfigMap, error) {
+	fData, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New(fileName).Parse(string(fData))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, kappnavutils.GetMapTemplateData(instance)); err != nil {
+		return nil, err
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := yaml.Unmarshal(buf.Bytes(), configMap); err != nil {
+		return nil, err
+	}
+	return configMap, nil
+}
@@ -17,7 +17,9 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
 	"runtime"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -26,17 +28,20 @@ import (
 
 	"github.com/kappnav/operator/pkg/apis"
 	"github.com/kappnav/operator/pkg/controller"
+	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	"github.com/kappnav/operator/pkg/webhook"
+	"github.com/kappnav/operator/version"
 
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	kubemetrics "github.com/operator-framework/operator-sdk/pkg/kube-metrics"
 	"github.com/operator-framework/operator-sdk/pkg/leader"
-	"github.com/operator-framework/operator-sdk/pkg/log/zap"
 	"github.com/operator-framework/operator-sdk/pkg/metrics"
 	"github.com/operator-framework/operator-sdk/pkg/restmapper"
 	sdkVersion "github.com/operator-framework/operator-sdk/version"
 	"github.com/spf13/pflag"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
@@ -48,6 +53,9 @@ var (
 	metricsHost               = "0.0.0.0"
 	metricsPort         int32 = 8383
 	operatorMetricsPort int32 = 8686
+	healthProbePort     int32 = 8081
+	webhookPort         int32 = 9443
+	webhookCertDir            = "/etc/webhook/certs"
 )
 var log = logf.Log.WithName("cmd")
 
@@ -58,9 +66,17 @@ func printVersion() {
 }
 
 func main() {
-	// Add the zap logger flag set to the CLI. The flag set must
-	// be added before calling pflag.Parse().
-	pflag.CommandLine.AddFlagSet(zap.FlagSet())
+	// The kam, lint and status-test subcommands explain KindActionMappings, check
+	// config maps and test status algorithms instead of running the operator.
+	if len(os.Args) > 1 && os.Args[1] == "kam" {
+		os.Exit(runKAMCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		os.Exit(runLintCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "status-test" {
+		os.Exit(runStatusTestCommand(os.Args[2:]))
+	}
 
 	// Add flags registered by imported packages (e.g. glog and
 	// controller-runtime)
@@ -68,23 +84,35 @@ func main() {
 
 	pflag.Parse()
 
-	// Use a zap logr.Logger implementation. If none of the zap
-	// flags are configured (or if the zap flag set is not being
-	// used), this defaults to a production zap logger.
+	// Use a zap logr.Logger implementation writing JSON. Its level follows the
+	// process-wide level of the kappnav loggers, which is set from the
+	// logging.operator field of the Kappnav CR.
 	//
-	// The logger instantiated here can be changed to any logger
-	// implementing the logr.Logger interface. This logger will
-	// be propagated through the whole operator, generating
+	// This logger will be propagated through the whole operator, generating
 	// uniform and structured logs.
-	logf.SetLogger(zap.Logger())
+	logf.SetLogger(kappnavutils.NewLogSink(os.Stderr))
 
 	printVersion()
 
+	// Trace the reconciles when an exporter is set in the environment
+	shutdownTracing, err := kappnavutils.InitTracing(context.Background(), "kappnav-operator", version.Version)
+	if err != nil {
+		log.Error(err, "Failed to initialize tracing, reconciles are not traced")
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Error(err, "Failed to flush the spans")
+		}
+	}()
+
 	namespace, err := k8sutil.GetWatchNamespace()
 	if err != nil {
 		log.Error(err, "Failed to get watch namespace")
 		os.Exit(1)
 	}
+	// WATCH_NAMESPACE may list several namespaces separated by commas
+	watchNamespaces := kappnavutils.ParseWatchNamespaces(namespace)
+	kappnavutils.SetWatchNamespaces(watchNamespaces)
 
 	// Get a config to talk to the apiserver
 	cfg, err := config.GetConfig()
@@ -93,6 +121,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Serve the health probes. The lock of the leader election is held for the life
+	// of the leader, so a pod waiting for it is ready for a rolling update to replace
+	// the leader. The leader is not ready until the CRDs are established, see the
+	// readiness check added by the kappnav controller.
+	serveHealthProbes()
+
+	// Serve the CRD conversion webhook on every instance, as the API server may call
+	// any pod behind the webhook Service. The CRDs only use the webhook when it is served.
+	kappnavutils.SetConversionWebhookServed(serveConversionWebhook())
+
 	ctx := context.TODO()
 	// Become the leader before proceeding
 	err = leader.Become(ctx, "kappnav-operator-lock")
@@ -102,11 +140,32 @@ func main() {
 	}
 
 	// Create a new Cmd to provide shared dependencies and start components
-	mgr, err := manager.New(cfg, manager.Options{
+	options := manager.Options{
 		Namespace:          namespace,
 		MapperProvider:     restmapper.NewDynamicRESTMapper,
 		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
-	})
+	}
+	// Cache the objects of each watched namespace. Cluster scoped objects are
+	// read from the API server, so that the operator does not list and watch
+	// them in the whole cluster.
+	newCache := cache.New
+	if len(watchNamespaces) > 0 {
+		log.Info("Watching namespaces", "namespaces", watchNamespaces)
+		options.Namespace = ""
+		newCache = kappnavutils.NewMultiNamespaceCacheFunc(watchNamespaces)
+	}
+	// Only cache the config maps, secrets, deployments, etc. created by the
+	// operator rather than all of those in the watched namespaces
+	options.NewCache = kappnavutils.NewLabelFilteredCacheFunc(newCache, watchNamespaces,
+		kappnavutils.GetManagedBySelector(), kappnavutils.GetLabelFilteredTypes())
+	if len(watchNamespaces) > 1 {
+		// The service monitors are created next to the metrics Service of the operator
+		if namespace, err = k8sutil.GetOperatorNamespace(); err != nil {
+			log.Error(err, "Failed to get the operator namespace")
+			os.Exit(1)
+		}
+	}
+	mgr, err := manager.New(cfg, options)
 	if err != nil {
 		log.Error(err, "")
 		os.Exit(1)
@@ -159,10 +218,44 @@ func main() {
 	// Start the Cmd
 	if err := mgr.Start(signals.SetupSignalHandler()); err != nil {
 		log.Error(err, "Manager exited non-zero")
+		shutdownTracing(context.Background())
 		os.Exit(1)
 	}
 }
 
+// serveHealthProbes serves the liveness and readiness probes on "http://metricsHost:healthProbePort".
+func serveHealthProbes() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", kappnavutils.HealthHandler)
+	mux.HandleFunc("/readyz", kappnavutils.ReadinessHandler)
+	go func() {
+		if err := http.ListenAndServe(fmt.Sprintf("%s:%d", metricsHost, healthProbePort), mux); err != nil {
+			log.Error(err, "Health probe server exited")
+		}
+	}()
+}
+
+// serveConversionWebhook serves the CRD conversion webhook on "https://metricsHost:webhookPort"
+// using the certificate mounted in webhookCertDir. It returns false when there is no
+// certificate, in which case the webhook is not served and the CRDs are applied
+// without it, serving only their storage version.
+func serveConversionWebhook() bool {
+	certFile := filepath.Join(webhookCertDir, "tls.crt")
+	keyFile := filepath.Join(webhookCertDir, "tls.key")
+	if _, err := os.Stat(certFile); err != nil {
+		log.Info("Conversion webhook certificate not found, the conversion webhook is disabled", "certFile", certFile)
+		return false
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(webhook.ConvertPath, webhook.ConversionHandler)
+	go func() {
+		if err := http.ListenAndServeTLS(fmt.Sprintf("%s:%d", metricsHost, webhookPort), certFile, keyFile, mux); err != nil {
+			log.Error(err, "Conversion webhook server exited")
+		}
+	}()
+	return true
+}
+
 // serveCRMetrics gets the Operator/CustomResource GVKs and generates metrics based on those types.
 // It serves those metrics on "http://metricsHost:operatorMetricsPort".
 func serveCRMetrics(cfg *rest.Config) error {
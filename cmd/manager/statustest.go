@@ -0,0 +1,116 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kappnav/operator/pkg/statusmap"
+	"github.com/spf13/pflag"
+)
+
+const statusTestUsage = `Usage: %s status-test [flags] FILE|DIR...
+
+Runs the getStatus algorithms of status config maps against the cases of
+fixture files, and the fixture files in directories. Exits with 1 if any
+case fails.
+
+Flags:
+`
+
+// runStatusTestCommand runs the status-test subcommand with the arguments that
+// follow it and returns the exit code.
+func runStatusTestCommand(args []string) int {
+	flags := pflag.NewFlagSet("status-test", pflag.ContinueOnError)
+	verbose := flags.BoolP("verbose", "v", false, "print the cases that pass as well as those that fail")
+	timeout := flags.Duration("timeout", statusmap.DefaultTimeout, "how long an algorithm may run for a single case")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, statusTestUsage, os.Args[0])
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	passed, err := runStatusFixtures(flags.Args(), *timeout, *verbose, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if !passed {
+		return 1
+	}
+	return 0
+}
+
+// runStatusFixtures runs the fixtures at paths, expanding directories to the
+// YAML and JSON files they contain, and returns whether all cases passed.
+func runStatusFixtures(paths []string, timeout time.Duration, verbose bool, out io.Writer) (bool, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return false, err
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !entry.IsDir() && (strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".json")) {
+				files = append(files, filepath.Join(path, name))
+			}
+		}
+	}
+
+	passed, failed := 0, 0
+	for _, file := range files {
+		fixture, err := statusmap.LoadFixture(file)
+		if err != nil {
+			return false, err
+		}
+		algorithm, err := statusmap.LoadFile(fixture.MapFile())
+		if err != nil {
+			return false, fmt.Errorf("%s: %v", file, err)
+		}
+		algorithm.Timeout = timeout
+		for _, result := range fixture.RunAlgorithm(algorithm) {
+			if result.Passed() {
+				passed++
+			} else {
+				failed++
+			}
+			if verbose || !result.Passed() {
+				fmt.Fprintln(out, result.String())
+			}
+		}
+	}
+	fmt.Fprintf(out, "%d passed, %d failed\n", passed, failed)
+	return failed == 0, nil
+}
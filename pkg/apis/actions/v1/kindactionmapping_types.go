@@ -10,23 +10,34 @@ import (
 // KindActionMappingSpec defines the desired state of KindActionMapping
 // +k8s:openapi-gen=true
 type KindActionMappingSpec struct {
-	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
-	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
-	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
-	Precedence int                    `json:"precedence,omitempty"`
-	Mappings   []MappingConfiguration `json:"mappings,omitempty"`
+	// Precedence orders KindActionMappings, the mappings with the highest precedence are used first
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=9
+	Precedence int `json:"precedence,omitempty"`
+	// Mappings map resources to the names of their action config maps
+	Mappings []MappingConfiguration `json:"mappings,omitempty"`
 }
 
 // MappingConfiguration defines resource constraints for Mapping configuration
+// +k8s:openapi-gen=true
 type MappingConfiguration struct {
-	APIVersion string `json:"apiVersion,omitempty"`
-	Owner      string `json:"owner,omitempty"`
-	OwnerUID   string `json:"ownerUID,omitempty"`
-	OwnerAPI   string `json:"ownerAPI,omitempty"`
-	Kind       string `json:"kind,omitempty"`
-	Subkind    string `json:"subkind,omitempty"`
-	Name       string `json:"name,omitempty"`
-	Mapname    string `json:"mapname,omitempty"`
+	// APIVersion is the group/version of the resources the mapping applies to, '*' matches any
+	APIVersion string `json:"apiVersion"`
+	// Owner is the kind of the owner of the resources the mapping applies to
+	Owner string `json:"owner,omitempty"`
+	// OwnerUID is the UID of the owner of the resources the mapping applies to
+	OwnerUID string `json:"ownerUID,omitempty"`
+	// OwnerAPI is the group/version of the owner of the resources the mapping applies to
+	OwnerAPI string `json:"ownerAPI,omitempty"`
+	// Kind is the kind of the resources the mapping applies to, '*' matches any
+	Kind string `json:"kind"`
+	// Subkind is the kappnav.subkind annotation of the resources the mapping applies to, '*' matches any
+	Subkind string `json:"subkind,omitempty"`
+	// Name is the name of the resource the mapping applies to, '*' matches any
+	Name string `json:"name,omitempty"`
+	// Mapname is the name of the action config map, which may use ${namespace}, ${kind}, ${subkind} and ${name}
+	// +kubebuilder:validation:MinLength=1
+	Mapname string `json:"mapname"`
 }
 
 // KindActionMappingStatus defines the observed state of KindActionMapping
@@ -41,7 +52,11 @@ type KindActionMappingStatus struct {
 
 // KindActionMapping is the Schema for the kindactionmappings API
 // +k8s:openapi-gen=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=kam;kams
+// +kubebuilder:printcolumn:name="Precedence",type="integer",JSONPath=".spec.precedence",description="Precedence of the mappings"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type KindActionMapping struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
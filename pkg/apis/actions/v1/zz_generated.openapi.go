@@ -14,6 +14,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"./pkg/apis/actions/v1.KindActionMapping":       schema_pkg_apis_actions_v1_KindActionMapping(ref),
 		"./pkg/apis/actions/v1.KindActionMappingSpec":   schema_pkg_apis_actions_v1_KindActionMappingSpec(ref),
 		"./pkg/apis/actions/v1.KindActionMappingStatus": schema_pkg_apis_actions_v1_KindActionMappingStatus(ref),
+		"./pkg/apis/actions/v1.MappingConfiguration":    schema_pkg_apis_actions_v1_MappingConfiguration(ref),
 	}
 }
 
@@ -68,14 +69,15 @@ func schema_pkg_apis_actions_v1_KindActionMappingSpec(ref common.ReferenceCallba
 				Properties: map[string]spec.Schema{
 					"precedence": {
 						SchemaProps: spec.SchemaProps{
-							Description: "INSERT ADDITIONAL SPEC FIELDS - desired state of cluster Important: Run \"operator-sdk generate k8s\" to regenerate code after modifying this file Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html",
+							Description: "Precedence orders KindActionMappings, the mappings with the highest precedence are used first",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
 					"mappings": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "Mappings map resources to the names of their action config maps",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
@@ -104,3 +106,73 @@ func schema_pkg_apis_actions_v1_KindActionMappingStatus(ref common.ReferenceCall
 		Dependencies: []string{},
 	}
 }
+
+func schema_pkg_apis_actions_v1_MappingConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MappingConfiguration defines resource constraints for Mapping configuration",
+				Properties: map[string]spec.Schema{
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion is the group/version of the resources the mapping applies to, '*' matches any",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"owner": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Owner is the kind of the owner of the resources the mapping applies to",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ownerUID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OwnerUID is the UID of the owner of the resources the mapping applies to",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ownerAPI": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OwnerAPI is the group/version of the owner of the resources the mapping applies to",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is the kind of the resources the mapping applies to, '*' matches any",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"subkind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Subkind is the kappnav.subkind annotation of the resources the mapping applies to, '*' matches any",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the resource the mapping applies to, '*' matches any",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"mapname": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mapname is the name of the action config map, which may use ${namespace}, ${kind}, ${subkind} and ${name}",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"apiVersion", "kind", "mapname"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
\ No newline at end of file
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"encoding/json"
+
+	kamv1 "github.com/kappnav/operator/pkg/apis/actions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// V2PreservedAnnotation holds the v2 mapping fields that have no v1 representation,
+	// so that a v2 object stored as v1 and read back as v2 is unchanged.
+	V2PreservedAnnotation string = "actions.kappnav.io/v2-preserved"
+
+	// RestrictedKind is the v1 kind of the mappings whose selectors or name regular
+	// expression cannot be expressed in v1. It is not a valid kind, so v1 readers
+	// apply these mappings to no resource rather than to every resource of their
+	// kind. The kind is kept in the V2PreservedAnnotation.
+	RestrictedKind string = "kappnav.io/v2-restricted"
+)
+
+// v2Preserved holds the v2 fields of a mapping that have no v1 representation.
+// The preserved mappings are in the same order as the mappings of the spec.
+type v2Preserved struct {
+	Kind               string                `json:"kind,omitempty"`
+	NameRegex          string                `json:"nameRegex,omitempty"`
+	Selector           *metav1.LabelSelector `json:"selector,omitempty"`
+	AnnotationSelector *metav1.LabelSelector `json:"annotationSelector,omitempty"`
+	NamespaceSelector  *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	Priority           int32                 `json:"priority,omitempty"`
+}
+
+func (p *v2Preserved) isEmpty() bool {
+	return !p.isRestricted() && p.Priority == 0
+}
+
+// isRestricted returns true if the mapping only applies to some of the resources
+// matching its v1 fields.
+func (p *v2Preserved) isRestricted() bool {
+	return len(p.NameRegex) > 0 || p.Selector != nil || p.AnnotationSelector != nil || p.NamespaceSelector != nil
+}
+
+// ConvertFromV1 converts a v1 KindActionMapping to v2. The v1 '*' wildcards and
+// exact values are valid v2 globs with the same meaning.
+func ConvertFromV1(src *kamv1.KindActionMapping, dst *KindActionMapping) error {
+	dst.TypeMeta = src.TypeMeta
+	dst.TypeMeta.APIVersion = SchemeGroupVersion.String()
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	var preserved []v2Preserved
+	if data, ok := dst.Annotations[V2PreservedAnnotation]; ok {
+		if err := json.Unmarshal([]byte(data), &preserved); err != nil {
+			return err
+		}
+		delete(dst.Annotations, V2PreservedAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+
+	dst.Spec = KindActionMappingSpec{Precedence: src.Spec.Precedence}
+	for i, in := range src.Spec.Mappings {
+		out := MappingConfiguration{
+			APIVersion: in.APIVersion,
+			Kind:       in.Kind,
+			Subkind:    in.Subkind,
+			Name:       in.Name,
+			Owner:      in.Owner,
+			OwnerUID:   in.OwnerUID,
+			OwnerAPI:   in.OwnerAPI,
+			Mapname:    in.Mapname,
+		}
+		if i < len(preserved) {
+			p := preserved[i]
+			if in.Kind == RestrictedKind {
+				out.Kind = p.Kind
+			}
+			out.NameRegex = p.NameRegex
+			out.Selector = p.Selector
+			out.AnnotationSelector = p.AnnotationSelector
+			out.NamespaceSelector = p.NamespaceSelector
+			out.Priority = p.Priority
+		}
+		dst.Spec.Mappings = append(dst.Spec.Mappings, out)
+	}
+	return nil
+}
+
+// ConvertToV1 converts a v2 KindActionMapping to v1. The globs are kept as they are;
+// the selectors, name regular expressions and priorities are kept in the
+// V2PreservedAnnotation. The mappings with selectors or a name regular expression
+// get the RestrictedKind, so that v1 readers, which ignore the annotation, do not
+// apply them to more resources than v2 readers.
+func ConvertToV1(src *KindActionMapping, dst *kamv1.KindActionMapping) error {
+	dst.TypeMeta = src.TypeMeta
+	dst.TypeMeta.APIVersion = kamv1.SchemeGroupVersion.String()
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	in := src.Spec.DeepCopy()
+	dst.Spec = kamv1.KindActionMappingSpec{Precedence: in.Precedence}
+	preserved := make([]v2Preserved, len(in.Mappings))
+	hasPreserved := false
+	for i, mapping := range in.Mappings {
+		dst.Spec.Mappings = append(dst.Spec.Mappings, kamv1.MappingConfiguration{
+			APIVersion: mapping.APIVersion,
+			Kind:       mapping.Kind,
+			Subkind:    mapping.Subkind,
+			Name:       mapping.Name,
+			Owner:      mapping.Owner,
+			OwnerUID:   mapping.OwnerUID,
+			OwnerAPI:   mapping.OwnerAPI,
+			Mapname:    mapping.Mapname,
+		})
+		preserved[i] = v2Preserved{
+			NameRegex:          mapping.NameRegex,
+			Selector:           mapping.Selector,
+			AnnotationSelector: mapping.AnnotationSelector,
+			NamespaceSelector:  mapping.NamespaceSelector,
+			Priority:           mapping.Priority,
+		}
+		if preserved[i].isRestricted() {
+			preserved[i].Kind = mapping.Kind
+			dst.Spec.Mappings[i].Kind = RestrictedKind
+		}
+		if !preserved[i].isEmpty() {
+			hasPreserved = true
+		}
+	}
+
+	if hasPreserved {
+		data, err := json.Marshal(preserved)
+		if err != nil {
+			return err
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = make(map[string]string)
+		}
+		dst.Annotations[V2PreservedAnnotation] = string(data)
+	} else if _, ok := dst.Annotations[V2PreservedAnnotation]; ok {
+		delete(dst.Annotations, V2PreservedAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+	return nil
+}
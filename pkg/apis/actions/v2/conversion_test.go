@@ -0,0 +1,100 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"reflect"
+	"testing"
+
+	kamv1 "github.com/kappnav/operator/pkg/apis/actions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConvertToV1RestrictedMappings(t *testing.T) {
+	original := &KindActionMapping{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "kappnav"},
+		Spec: KindActionMappingSpec{
+			Precedence: 5,
+			Mappings: []MappingConfiguration{
+				{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Mapname:    "payments.actions.deployment",
+					Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+				},
+				{
+					APIVersion: "v1",
+					Kind:       "Service",
+					Name:       "payments-*",
+					Mapname:    "payments.actions.service",
+					Priority:   10,
+				},
+				{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					NameRegex:  "^payments-[0-9]+$",
+					Mapname:    "payments.actions.configmap",
+				},
+			},
+		},
+	}
+
+	converted := &kamv1.KindActionMapping{}
+	if err := ConvertToV1(original.DeepCopy(), converted); err != nil {
+		t.Fatalf("ConvertToV1 failed: %s", err)
+	}
+	wantKinds := []string{RestrictedKind, "Service", RestrictedKind}
+	for i, mapping := range converted.Spec.Mappings {
+		if mapping.Kind != wantKinds[i] {
+			t.Errorf("mapping %d: got kind %s, want %s", i, mapping.Kind, wantKinds[i])
+		}
+	}
+
+	roundTrip := &KindActionMapping{}
+	if err := ConvertFromV1(converted, roundTrip); err != nil {
+		t.Fatalf("ConvertFromV1 failed: %s", err)
+	}
+	if !reflect.DeepEqual(original.ObjectMeta, roundTrip.ObjectMeta) {
+		t.Errorf("metadata changed: got %+v, want %+v", roundTrip.ObjectMeta, original.ObjectMeta)
+	}
+	if !reflect.DeepEqual(original.Spec, roundTrip.Spec) {
+		t.Errorf("spec changed: got %+v, want %+v", roundTrip.Spec, original.Spec)
+	}
+}
+
+func TestConvertFromV1KeepsEditedKind(t *testing.T) {
+	v2 := &KindActionMapping{
+		Spec: KindActionMappingSpec{
+			Mappings: []MappingConfiguration{{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Mapname:    "payments.actions.deployment",
+				Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+			}},
+		},
+	}
+	v1 := &kamv1.KindActionMapping{}
+	if err := ConvertToV1(v2, v1); err != nil {
+		t.Fatalf("ConvertToV1 failed: %s", err)
+	}
+	// A v1 client replaces the kind of the mapping
+	v1.Spec.Mappings[0].Kind = "StatefulSet"
+	converted := &KindActionMapping{}
+	if err := ConvertFromV1(v1, converted); err != nil {
+		t.Fatalf("ConvertFromV1 failed: %s", err)
+	}
+	if kind := converted.Spec.Mappings[0].Kind; kind != "StatefulSet" {
+		t.Errorf("got kind %s, want StatefulSet", kind)
+	}
+}
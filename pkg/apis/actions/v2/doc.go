@@ -0,0 +1,4 @@
+// Package v2 contains API Schema definitions for the actions v2 API group
+// +k8s:deepcopy-gen=package,register
+// +groupName=actions.kappnav.io
+package v2
@@ -0,0 +1,85 @@
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KindActionMappingSpec defines the desired state of KindActionMapping
+// +k8s:openapi-gen=true
+type KindActionMappingSpec struct {
+	// Precedence orders KindActionMappings, the mappings with the highest precedence are used first
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=9
+	Precedence int `json:"precedence,omitempty"`
+	// Mappings map resources to the names of their action config maps
+	Mappings []MappingConfiguration `json:"mappings,omitempty"`
+}
+
+// MappingConfiguration selects the resources that a mapping applies to. A resource
+// must match all of the fields that are set. apiVersion, kind, subkind and name are
+// glob patterns in which '*' matches any sequence of characters except '/'.
+// +k8s:openapi-gen=true
+type MappingConfiguration struct {
+	// APIVersion is a glob of the group/version of the resources, e.g. apps/* or */*; '*' matches core resources
+	APIVersion string `json:"apiVersion"`
+	// Kind is a glob of the kind of the resources
+	Kind string `json:"kind"`
+	// Subkind is a glob of the kappnav.subkind annotation of the resources
+	Subkind string `json:"subkind,omitempty"`
+	// Name is a glob of the name of the resources
+	Name string `json:"name,omitempty"`
+	// NameRegex is a regular expression that must match the whole name of the resources
+	NameRegex string `json:"nameRegex,omitempty"`
+	// Owner is the kind of the owner of the resources
+	Owner string `json:"owner,omitempty"`
+	// OwnerUID is the UID of the owner of the resources
+	OwnerUID string `json:"ownerUID,omitempty"`
+	// OwnerAPI is the group/version of the owner of the resources
+	OwnerAPI string `json:"ownerAPI,omitempty"`
+	// Selector selects the resources by label
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// AnnotationSelector selects the resources by annotation, with the semantics of a label selector
+	AnnotationSelector *metav1.LabelSelector `json:"annotationSelector,omitempty"`
+	// NamespaceSelector selects the resources by the labels of their namespace
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Priority orders the mappings of all KindActionMappings; mappings with a higher
+	// priority are used before more specific mappings with a lower priority
+	Priority int32 `json:"priority,omitempty"`
+	// Mapname is the name of the action config map, which may use ${namespace}, ${kind}, ${subkind} and ${name}
+	// +kubebuilder:validation:MinLength=1
+	Mapname string `json:"mapname"`
+}
+
+// KindActionMappingStatus defines the observed state of KindActionMapping
+// +k8s:openapi-gen=true
+type KindActionMappingStatus struct {
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KindActionMapping is the Schema for the kindactionmappings API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=kam;kams
+// +kubebuilder:printcolumn:name="Precedence",type="integer",JSONPath=".spec.precedence",description="Precedence of the mappings"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type KindActionMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KindActionMappingSpec   `json:"spec,omitempty"`
+	Status KindActionMappingStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KindActionMappingList contains a list of KindActionMapping
+type KindActionMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KindActionMapping `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KindActionMapping{}, &KindActionMappingList{})
+}
@@ -0,0 +1,211 @@
+// +build !ignore_autogenerated
+
+// This file was autogenerated by openapi-gen. Do not edit it manually!
+
+package v2
+
+import (
+	spec "github.com/go-openapi/spec"
+	common "k8s.io/kube-openapi/pkg/common"
+)
+
+func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+	return map[string]common.OpenAPIDefinition{
+		"./pkg/apis/actions/v2.KindActionMapping":       schema_pkg_apis_actions_v2_KindActionMapping(ref),
+		"./pkg/apis/actions/v2.KindActionMappingSpec":   schema_pkg_apis_actions_v2_KindActionMappingSpec(ref),
+		"./pkg/apis/actions/v2.KindActionMappingStatus": schema_pkg_apis_actions_v2_KindActionMappingStatus(ref),
+		"./pkg/apis/actions/v2.MappingConfiguration":    schema_pkg_apis_actions_v2_MappingConfiguration(ref),
+	}
+}
+
+func schema_pkg_apis_actions_v2_KindActionMapping(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KindActionMapping is the Schema for the kindactionmappings API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/actions/v2.KindActionMappingSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/actions/v2.KindActionMappingStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/actions/v2.KindActionMappingSpec", "./pkg/apis/actions/v2.KindActionMappingStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_actions_v2_KindActionMappingSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KindActionMappingSpec defines the desired state of KindActionMapping",
+				Properties: map[string]spec.Schema{
+					"precedence": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Precedence orders KindActionMappings, the mappings with the highest precedence are used first",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"mappings": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mappings map resources to the names of their action config maps",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/actions/v2.MappingConfiguration"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/actions/v2.MappingConfiguration"},
+	}
+}
+
+func schema_pkg_apis_actions_v2_KindActionMappingStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KindActionMappingStatus defines the observed state of KindActionMapping",
+				Properties:  map[string]spec.Schema{},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_actions_v2_MappingConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MappingConfiguration selects the resources that a mapping applies to. A resource must match all of the fields that are set. apiVersion, kind, subkind and name are glob patterns in which '*' matches any sequence of characters except '/'.",
+				Properties: map[string]spec.Schema{
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion is a glob of the group/version of the resources, e.g. apps/* or */*; '*' matches core resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a glob of the kind of the resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"subkind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Subkind is a glob of the kappnav.subkind annotation of the resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is a glob of the name of the resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"nameRegex": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NameRegex is a regular expression that must match the whole name of the resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"owner": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Owner is the kind of the owner of the resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ownerUID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OwnerUID is the UID of the owner of the resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ownerAPI": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OwnerAPI is the group/version of the owner of the resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"selector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Selector selects the resources by label",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"annotationSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AnnotationSelector selects the resources by annotation, with the semantics of a label selector",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"namespaceSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NamespaceSelector selects the resources by the labels of their namespace",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"priority": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Priority orders the mappings of all KindActionMappings; mappings with a higher priority are used before more specific mappings with a lower priority",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"mapname": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mapname is the name of the action config map, which may use ${namespace}, ${kind}, ${subkind} and ${name}",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"apiVersion", "kind", "mapname"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
@@ -24,32 +24,206 @@ import (
 // KappnavSpec defines the desired state of Kappnav
 // +k8s:openapi-gen=true
 type KappnavSpec struct {
-	AppNavAPI           *KappnavContainerConfiguration            `json:"appNavAPI,omitempty"`
-	AppNavController    *KappnavContainerConfiguration            `json:"appNavController,omitempty"`
-	AppNavUI            *KappnavContainerConfiguration            `json:"appNavUI,omitempty"`
+	// AppNavAPI configures the kAppNav REST API container
+	AppNavAPI *KappnavContainerConfiguration `json:"appNavAPI,omitempty"`
+	// AppNavController configures the kAppNav controller container
+	AppNavController *KappnavContainerConfiguration `json:"appNavController,omitempty"`
+	// AppNavUI configures the kAppNav UI container
+	AppNavUI *KappnavContainerConfiguration `json:"appNavUI,omitempty"`
+	// ExtensionContainers configures additional containers such as the oauth-proxy, keyed by container
 	ExtensionContainers map[string]*KappnavContainerConfiguration `json:"extensionContainers,omitempty"`
-	Image               *KappnavImageConfiguration                `json:"image,omitempty"`
-	Env                 *Environment                              `json:"env,omitempty"`
-	Logging             map[string]string                         `json:"logging,omitempty"`
+	// Image configures the pull policy, pull secrets and registry of all images
+	Image *KappnavImageConfiguration `json:"image,omitempty"`
+	// Env describes the environment kAppNav is installed in
+	Env *Environment `json:"env,omitempty"`
+	// Logging maps a component (operator, apis, ui, controller) to its log level
+	Logging map[string]LogLevel `json:"logging,omitempty"`
+	// LoggingOptions configures how the log levels reach the kAppNav containers,
+	// the log levels of packages and temporary debug logging
+	LoggingOptions *KappnavLoggingOptions `json:"loggingOptions,omitempty"`
+	// TrustedCA configures the CA bundle mounted into the kAppNav containers
+	TrustedCA *KappnavTrustedCAConfiguration `json:"trustedCA,omitempty"`
+	// Proxy configures the HTTP proxy settings passed to the kAppNav containers
+	Proxy *KappnavProxyConfiguration `json:"proxy,omitempty"`
+	// Ingress configures the UI Ingress created on Kubernetes
+	Ingress *KappnavIngressConfiguration `json:"ingress,omitempty"`
+	// Route configures the UI Route created on OpenShift
+	Route *KappnavRouteConfiguration `json:"route,omitempty"`
+	// Monitoring configures the ServiceMonitors and PrometheusRule of the kAppNav components
+	Monitoring *KappnavMonitoringConfiguration `json:"monitoring,omitempty"`
+	// Isolation selects the names of the resources of the CR that are shared by
+	// the kAppNav installation of a namespace, Shared by default
+	Isolation IsolationMode `json:"isolation,omitempty"`
+	// Builtin configures how the console URLs and dashboard names of the builtin
+	// config map are kept up to date
+	Builtin *KappnavBuiltinConfiguration `json:"builtin,omitempty"`
+}
+
+// IsolationMode selects how the resources of a CR are named
+// +kubebuilder:validation:Enum=Shared;Instance
+type IsolationMode string
+
+const (
+	// IsolationModeShared uses the well-known names of the kappnav Application, the
+	// builtin and kappnav-config config maps, the action, section and status
+	// config maps and the default KindActionMapping. Only one CR in a namespace
+	// may use this mode.
+	IsolationModeShared IsolationMode = "Shared"
+	// IsolationModeInstance prefixes these names with the name of the CR, so that
+	// any number of CRs can run side by side
+	IsolationModeInstance IsolationMode = "Instance"
+)
+
+// LogLevel ...
+// +kubebuilder:validation:Enum=none;error;warning;info;debug;entry;all
+type LogLevel string
+
+// LoggingPropagation is how the log levels reach the kAppNav containers
+// +kubebuilder:validation:Enum=ConfigMap;Environment
+type LoggingPropagation string
+
+const (
+	// LoggingPropagationConfigMap writes the log levels to a ConfigMap mounted into
+	// the containers, which pick up changes without a restart
+	LoggingPropagationConfigMap LoggingPropagation = "ConfigMap"
+	// LoggingPropagationEnvironment passes the log levels as environment variables,
+	// so that a change rolls the Deployments
+	LoggingPropagationEnvironment LoggingPropagation = "Environment"
+)
+
+// KappnavLoggingOptions defines how the log levels reach the kAppNav containers,
+// the log levels of packages and temporary debug logging.
+// +k8s:openapi-gen=true
+type KappnavLoggingOptions struct {
+	// Propagation is how the log levels reach the containers, ConfigMap by default
+	Propagation LoggingPropagation `json:"propagation,omitempty"`
+	// Packages maps a component (apis, ui, controller) to the log levels of its
+	// packages, which override the log level of the component
+	Packages map[string]map[string]LogLevel `json:"packages,omitempty"`
+	// Debug raises the log level of components for a limited time
+	Debug *KappnavDebugLogging `json:"debug,omitempty"`
+}
+
+// KappnavDebugLogging raises the log level of components for a limited time, after
+// which the levels of the logging field apply again. Changing any field starts a
+// new period.
+// +k8s:openapi-gen=true
+type KappnavDebugLogging struct {
+	// Components are the components to debug (operator, apis, ui, controller), all when empty
+	Components []string `json:"components,omitempty"`
+	// Level is the log level of the components while debugging, debug by default
+	Level LogLevel `json:"level,omitempty"`
+	// Duration is how long the components are debugged for, e.g. 30m
+	Duration metav1.Duration `json:"duration"`
 }
 
 // KappnavContainerConfiguration defines the configuration for a Kappnav container
+// +k8s:openapi-gen=true
 type KappnavContainerConfiguration struct {
-	Repository Repository                  `json:"repository,omitempty"`
-	Tag        Tag                         `json:"tag,omitempty"`
-	Resources  *KappnavResourceConstraints `json:"resources,omitempty"`
+	// Repository is the image repository of the container
+	Repository Repository `json:"repository,omitempty"`
+	// Tag is the image tag of the container
+	Tag Tag `json:"tag,omitempty"`
+	// Digest is the image digest of the container, which takes precedence over the tag
+	Digest Digest `json:"digest,omitempty"`
+	// Resources are the resource requests and limits of the container
+	Resources *KappnavResourceConstraints `json:"resources,omitempty"`
+	// Target is where an extension container runs: in the UI pod, in the
+	// controller pod or in a Deployment of its own. Extension containers without
+	// a target are not deployed. Ignored for the kAppNav containers.
+	Target ExtensionContainerTarget `json:"target,omitempty"`
+	// Ports are the ports of an extension container
+	Ports []KappnavContainerPort `json:"ports,omitempty"`
+	// Service exposes the ports of an extension container
+	Service *KappnavContainerService `json:"service,omitempty"`
+	// ReadinessProbe is the readiness probe of an extension container
+	ReadinessProbe *KappnavContainerProbe `json:"readinessProbe,omitempty"`
+	// LivenessProbe is the liveness probe of an extension container
+	LivenessProbe *KappnavContainerProbe `json:"livenessProbe,omitempty"`
+}
+
+// ExtensionContainerTarget is where an extension container runs
+// +kubebuilder:validation:Enum=ui;controller;deployment
+type ExtensionContainerTarget string
+
+const (
+	// ExtensionContainerTargetUI runs the container in the UI pod
+	ExtensionContainerTargetUI ExtensionContainerTarget = "ui"
+	// ExtensionContainerTargetController runs the container in the controller pod
+	ExtensionContainerTargetController ExtensionContainerTarget = "controller"
+	// ExtensionContainerTargetDeployment runs the container in a Deployment of its own
+	ExtensionContainerTargetDeployment ExtensionContainerTarget = "deployment"
+)
+
+// KappnavContainerPort is a port of an extension container
+// +k8s:openapi-gen=true
+type KappnavContainerPort struct {
+	// Name is the name of the port
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Port is the number of the port
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// Protocol is the protocol of the port, TCP by default
+	// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+}
+
+// KappnavContainerService exposes the ports of an extension container in a
+// Service named after the container
+// +k8s:openapi-gen=true
+type KappnavContainerService struct {
+	// Enabled creates the Service
+	Enabled bool `json:"enabled,omitempty"`
+	// Type is the type of the Service, ClusterIP by default
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	Type corev1.ServiceType `json:"type,omitempty"`
+	// Annotations are added to the Service
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// KappnavContainerProbe is an HTTP GET probe of an extension container, or a TCP
+// probe when no path is set
+// +k8s:openapi-gen=true
+type KappnavContainerProbe struct {
+	// Port is the port probed
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// Path is the path of the HTTP GET request
+	Path string `json:"path,omitempty"`
+	// Scheme is the scheme of the HTTP GET request, HTTP by default
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	Scheme corev1.URIScheme `json:"scheme,omitempty"`
+	// InitialDelaySeconds is the time before the first probe
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+	// PeriodSeconds is the time between probes
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+	// FailureThreshold is the number of failed probes before the container is
+	// considered unready or is restarted
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
 }
 
 // KappnavResourceConstraints defines resource constraints for a Kappnav container
+// +k8s:openapi-gen=true
 type KappnavResourceConstraints struct {
-	Enabled  bool       `json:"enabled,omitempty"`
+	// Enabled applies the requests and limits to the container
+	Enabled bool `json:"enabled,omitempty"`
+	// Requests are the resources requested by the container
 	Requests *Resources `json:"requests,omitempty"`
-	Limits   *Resources `json:"limits,omitempty"`
+	// Limits are the maximum resources used by the container
+	Limits *Resources `json:"limits,omitempty"`
 }
 
 // Resources ...
+// +k8s:openapi-gen=true
 type Resources struct {
-	CPU    string `json:"cpu,omitempty"`
+	// CPU is a quantity of CPU such as 500m
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	CPU string `json:"cpu,omitempty"`
+	// Memory is a quantity of memory such as 512Mi
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
 	Memory string `json:"memory,omitempty"`
 }
 
@@ -59,24 +233,243 @@ type Repository string
 // Tag ...
 type Tag string
 
+// Digest is an image digest such as sha256:... which takes precedence over the Tag
+// +kubebuilder:validation:Pattern=`^[A-Za-z][A-Za-z0-9]*([+._-][A-Za-z][A-Za-z0-9]*)*:[0-9A-Fa-f]{32,}$`
+type Digest string
+
 // KappnavImageConfiguration ...
+// +k8s:openapi-gen=true
 type KappnavImageConfiguration struct {
-	PullPolicy  corev1.PullPolicy `json:"pullPolicy,omitempty"`
-	PullSecrets []string          `json:"pullSecrets,omitempty"`
+	// PullPolicy is the image pull policy of all containers
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	PullPolicy corev1.PullPolicy `json:"pullPolicy,omitempty"`
+	// PullSecrets are the names of the image pull secrets of all containers
+	PullSecrets []string `json:"pullSecrets,omitempty"`
+	// Registry replaces the registry host of every image repository, e.g. to pull from a mirror
+	Registry string `json:"registry,omitempty"`
+}
+
+// KappnavTrustedCAConfiguration defines the source of the CA bundle that is
+// mounted into the kappnav containers. Either ConfigMapName references an
+// existing ConfigMap holding the bundle, or InjectOpenShiftBundle requests the
+// operator to create a ConfigMap that OpenShift fills with the cluster bundle.
+// +k8s:openapi-gen=true
+type KappnavTrustedCAConfiguration struct {
+	// ConfigMapName is the name of an existing ConfigMap holding the CA bundle. The
+	// bundle replaces the system trust store of the containers, so it must be a full
+	// bundle holding the public CAs as well as the private ones.
+	ConfigMapName string `json:"configMapName,omitempty"`
+	// Key is the key of the CA bundle in the ConfigMap, ca-bundle.crt by default
+	Key string `json:"key,omitempty"`
+	// InjectOpenShiftBundle requests a ConfigMap that OpenShift fills with the cluster CA bundle
+	InjectOpenShiftBundle bool `json:"injectOpenShiftBundle,omitempty"`
+}
+
+// KappnavProxyConfiguration defines the HTTP proxy settings passed to the kappnav
+// containers. Values not set in the CR are taken from the OpenShift cluster Proxy
+// resource and then from the operator's own environment.
+// +k8s:openapi-gen=true
+type KappnavProxyConfiguration struct {
+	// HTTPProxy is the proxy URL for HTTP requests
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// HTTPSProxy is the proxy URL for HTTPS requests
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	// NoProxy is a comma separated list of hosts and domains that bypass the proxy
+	NoProxy string `json:"noProxy,omitempty"`
 }
 
+// KappnavMonitoringConfiguration defines the Prometheus monitoring of the kAppNav
+// components. The resources are only created when the monitoring.coreos.com API
+// of the Prometheus operator is available.
+// +k8s:openapi-gen=true
+type KappnavMonitoringConfiguration struct {
+	// Enabled creates ServiceMonitors for the API, UI and controller
+	Enabled bool `json:"enabled,omitempty"`
+	// Labels are added to the ServiceMonitors and the PrometheusRule, e.g. to match
+	// the selectors of a Prometheus
+	Labels map[string]string `json:"labels,omitempty"`
+	// Interval is how often the components are scraped, e.g. 30s, the interval of
+	// the Prometheus when empty
+	Interval string `json:"interval,omitempty"`
+	// Alerts creates a PrometheusRule with the default alerts, true by default
+	Alerts *bool `json:"alerts,omitempty"`
+	// CertificateExpiryDays is how many days before the UI certificate expires
+	// the certificate alert fires, 30 by default
+	// +kubebuilder:validation:Minimum=1
+	CertificateExpiryDays int32 `json:"certificateExpiryDays,omitempty"`
+}
+
+// KappnavBuiltinConfiguration defines how the operator maintains the builtin config
+// map. The console URLs are refreshed when the console configuration changes and
+// every resync period.
+// +k8s:openapi-gen=true
+type KappnavBuiltinConfiguration struct {
+	// ResyncPeriod is how often the builtin config map is refreshed, e.g. 30m,
+	// only when the CR or the console configuration changes when empty
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+	// Policies maps keys of the builtin config map, e.g. openshift-console-url, to
+	// the policy used to maintain them, FillIfEmpty when not listed
+	Policies map[string]BuiltinKeyPolicy `json:"policies,omitempty"`
+}
+
+// BuiltinKeyPolicy selects how the operator maintains a key of the builtin config
+// map
+// +kubebuilder:validation:Enum=FillIfEmpty;AlwaysSync
+type BuiltinKeyPolicy string
+
+const (
+	// BuiltinKeyPolicyFillIfEmpty only sets the key when it is missing or empty, so
+	// that values set by users are kept
+	BuiltinKeyPolicyFillIfEmpty BuiltinKeyPolicy = "FillIfEmpty"
+	// BuiltinKeyPolicyAlwaysSync overwrites the key with the value the operator
+	// computes, e.g. the current console URL
+	BuiltinKeyPolicyAlwaysSync BuiltinKeyPolicy = "AlwaysSync"
+)
+
+// KappnavIngressConfiguration defines the host and annotations of the UI Ingress.
+// +k8s:openapi-gen=true
+type KappnavIngressConfiguration struct {
+	// Host is the host name of the Ingress rule, any host when empty
+	Host string `json:"host,omitempty"`
+	// Annotations are added to the Ingress, e.g. to select an ingress controller
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// KappnavRouteConfiguration defines the host and TLS termination of the UI Route.
+// +k8s:openapi-gen=true
+type KappnavRouteConfiguration struct {
+	// Host is the host name of the Route, generated by OpenShift when empty
+	Host string `json:"host,omitempty"`
+	// Termination is the TLS termination of the Route, reencrypt by default
+	Termination RouteTermination `json:"termination,omitempty"`
+}
+
+// RouteTermination ...
+// +kubebuilder:validation:Enum=edge;reencrypt;passthrough
+type RouteTermination string
+
 // Environment variables.
+// +k8s:openapi-gen=true
 type Environment struct {
+	// KubeEnv is the type of Kubernetes cluster kAppNav is installed in
+	// +kubebuilder:validation:Enum=k8s;minikube;minishift;okd;ocp
 	KubeEnv string `json:"kubeEnv,omitempty"`
 }
 
 // KappnavStatus defines the observed state of Kappnav
 // +k8s:openapi-gen=true
 type KappnavStatus struct {
+	// Conditions report the result of the last reconcile
 	Conditions []StatusCondition `json:"conditions,omitempty"`
+	// Components report the image and rollout progress of each kAppNav container
+	Components []ComponentStatus `json:"components,omitempty"`
+	// Version is the kAppNav version installed by the operator
+	Version string `json:"version,omitempty"`
+	// Upgrade reports the progress of an upgrade
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+	// URL is the URL of the kAppNav UI
+	URL string `json:"url,omitempty"`
+	// MapIssues are the errors found in the action, section and status config maps
+	MapIssues []MapIssue `json:"mapIssues,omitempty"`
+	// DebugLogging reports the temporary debug logging requested in the spec
+	DebugLogging *DebugLoggingStatus `json:"debugLogging,omitempty"`
 }
 
+// DebugLoggingStatus reports the period of temporary debug logging
+// +k8s:openapi-gen=true
+type DebugLoggingStatus struct {
+	// Components are the components being debugged, all when empty
+	Components []string    `json:"components,omitempty"`
+	Level      LogLevel    `json:"level"`
+	StartTime  metav1.Time `json:"startTime"`
+	ExpiryTime metav1.Time `json:"expiryTime"`
+	// Active is false once the period has expired
+	Active bool `json:"active"`
+}
+
+// MapIssue is an error found in a kAppNav config map
+// +k8s:openapi-gen=true
+type MapIssue struct {
+	// Map is the name of the config map
+	Map string `json:"map"`
+	// Location is the key of the config map and the path in its JSON document
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+// UpgradeStatus reports the progress of an upgrade from the installed kAppNav
+// version to the version of the operator.
+// +k8s:openapi-gen=true
+type UpgradeStatus struct {
+	FromVersion string `json:"fromVersion,omitempty"`
+	ToVersion   string `json:"toVersion"`
+	// +kubebuilder:validation:Enum=PreUpgrade;RollDeployments;PostUpgrade;RollingBack;Completed;Failed
+	Phase UpgradePhase `json:"phase"`
+	// Deployment is the Deployment currently being rolled
+	Deployment string `json:"deployment,omitempty"`
+	Message    string `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the CR the upgrade was last attempted with
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"`
+	StartTime          metav1.Time `json:"startTime,omitempty"`
+	PhaseStartTime     metav1.Time `json:"phaseStartTime,omitempty"`
+	// PreviousImages maps deployment/container to the image used before the upgrade
+	PreviousImages map[string]string `json:"previousImages,omitempty"`
+}
+
+// UpgradePhase ...
+type UpgradePhase string
+
+const (
+	// UpgradePhasePreUpgrade data, KindActionMappings and CRDs are being migrated
+	UpgradePhasePreUpgrade UpgradePhase = "PreUpgrade"
+	// UpgradePhaseRollDeployments the Deployments are rolled one at a time
+	UpgradePhaseRollDeployments UpgradePhase = "RollDeployments"
+	// UpgradePhasePostUpgrade the health of the upgraded components is verified
+	UpgradePhasePostUpgrade UpgradePhase = "PostUpgrade"
+	// UpgradePhaseRollingBack the Deployments are being restored to their previous images
+	UpgradePhaseRollingBack UpgradePhase = "RollingBack"
+	// UpgradePhaseCompleted ...
+	UpgradePhaseCompleted UpgradePhase = "Completed"
+	// UpgradePhaseFailed the upgrade failed and was rolled back
+	UpgradePhaseFailed UpgradePhase = "Failed"
+)
+
+// ComponentStatus reports the image and rollout progress of a kappnav container
+// within one of the Deployments managed by the operator.
+// +k8s:openapi-gen=true
+type ComponentStatus struct {
+	Name       string `json:"name"`
+	Deployment string `json:"deployment"`
+	// DesiredImage is the image reference set on the Deployment by the operator
+	DesiredImage string `json:"desiredImage,omitempty"`
+	// ImageIDs are the distinct image IDs reported by the running pods
+	ImageIDs []string `json:"imageIDs,omitempty"`
+	// UpToDatePods is the number of pods of the current revision of the Deployment
+	UpToDatePods    int32 `json:"upToDatePods"`
+	Replicas        int32 `json:"replicas"`
+	ReadyReplicas   int32 `json:"readyReplicas"`
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+	// +kubebuilder:validation:Enum=Progressing;Complete;Failed;Unknown
+	RolloutState   RolloutState `json:"rolloutState,omitempty"`
+	LastUpdateTime metav1.Time  `json:"lastUpdateTime,omitempty"`
+}
+
+// RolloutState ...
+type RolloutState string
+
+const (
+	// RolloutStateProgressing the Deployment is rolling out a new revision
+	RolloutStateProgressing RolloutState = "Progressing"
+	// RolloutStateComplete all replicas run the desired revision and are available
+	RolloutStateComplete RolloutState = "Complete"
+	// RolloutStateFailed the rollout exceeded its progress deadline
+	RolloutStateFailed RolloutState = "Failed"
+	// RolloutStateUnknown the Deployment could not be read
+	RolloutStateUnknown RolloutState = "Unknown"
+)
+
 // StatusCondition ...
+// +k8s:openapi-gen=true
 type StatusCondition struct {
 	LastTransitionTime *metav1.Time           `json:"lastTransitionTime,omitempty"`
 	LastUpdateTime     metav1.Time            `json:"lastUpdateTime,omitempty"`
@@ -92,13 +485,20 @@ type StatusConditionType string
 const (
 	// StatusConditionTypeReconciled ...
 	StatusConditionTypeReconciled StatusConditionType = "Reconciled"
+	// StatusConditionTypeMapsValid the action, section and status config maps have no errors
+	StatusConditionTypeMapsValid StatusConditionType = "MapsValid"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // Kappnav is the Schema for the kappnavs API
 // +k8s:openapi-gen=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Reconciled",type="string",JSONPath=".status.conditions[?(@.type=='Reconciled')].status",description="Status of the last reconcile"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url",description="URL of the kAppNav UI"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="Installed kAppNav version"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type Kappnav struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -5,9 +5,55 @@
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+	if in.ImageIDs != nil {
+		in, out := &in.ImageIDs, &out.ImageIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DebugLoggingStatus) DeepCopyInto(out *DebugLoggingStatus) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.ExpiryTime.DeepCopyInto(&out.ExpiryTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DebugLoggingStatus.
+func (in *DebugLoggingStatus) DeepCopy() *DebugLoggingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DebugLoggingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Environment) DeepCopyInto(out *Environment) {
 	*out = *in
@@ -52,6 +98,34 @@ func (in *Kappnav) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KappnavBuiltinConfiguration) DeepCopyInto(out *KappnavBuiltinConfiguration) {
+	*out = *in
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make(map[string]BuiltinKeyPolicy, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KappnavBuiltinConfiguration.
+func (in *KappnavBuiltinConfiguration) DeepCopy() *KappnavBuiltinConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KappnavBuiltinConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KappnavContainerConfiguration) DeepCopyInto(out *KappnavContainerConfiguration) {
 	*out = *in
@@ -60,6 +134,26 @@ func (in *KappnavContainerConfiguration) DeepCopyInto(out *KappnavContainerConfi
 		*out = new(KappnavResourceConstraints)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]KappnavContainerPort, len(*in))
+		copy(*out, *in)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(KappnavContainerService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(KappnavContainerProbe)
+		**out = **in
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(KappnavContainerProbe)
+		**out = **in
+	}
 	return
 }
 
@@ -73,6 +167,83 @@ func (in *KappnavContainerConfiguration) DeepCopy() *KappnavContainerConfigurati
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KappnavContainerPort) DeepCopyInto(out *KappnavContainerPort) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KappnavContainerPort.
+func (in *KappnavContainerPort) DeepCopy() *KappnavContainerPort {
+	if in == nil {
+		return nil
+	}
+	out := new(KappnavContainerPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KappnavContainerProbe) DeepCopyInto(out *KappnavContainerProbe) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KappnavContainerProbe.
+func (in *KappnavContainerProbe) DeepCopy() *KappnavContainerProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(KappnavContainerProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KappnavContainerService) DeepCopyInto(out *KappnavContainerService) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KappnavContainerService.
+func (in *KappnavContainerService) DeepCopy() *KappnavContainerService {
+	if in == nil {
+		return nil
+	}
+	out := new(KappnavContainerService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KappnavDebugLogging) DeepCopyInto(out *KappnavDebugLogging) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KappnavDebugLogging.
+func (in *KappnavDebugLogging) DeepCopy() *KappnavDebugLogging {
+	if in == nil {
+		return nil
+	}
+	out := new(KappnavDebugLogging)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KappnavImageConfiguration) DeepCopyInto(out *KappnavImageConfiguration) {
 	*out = *in
@@ -94,6 +265,29 @@ func (in *KappnavImageConfiguration) DeepCopy() *KappnavImageConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KappnavIngressConfiguration) DeepCopyInto(out *KappnavIngressConfiguration) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KappnavIngressConfiguration.
+func (in *KappnavIngressConfiguration) DeepCopy() *KappnavIngressConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KappnavIngressConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KappnavList) DeepCopyInto(out *KappnavList) {
 	*out = *in
@@ -127,6 +321,88 @@ func (in *KappnavList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KappnavLoggingOptions) DeepCopyInto(out *KappnavLoggingOptions) {
+	*out = *in
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = make(map[string]map[string]LogLevel, len(*in))
+		for key, val := range *in {
+			var outVal map[string]LogLevel
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]LogLevel, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Debug != nil {
+		in, out := &in.Debug, &out.Debug
+		*out = new(KappnavDebugLogging)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KappnavLoggingOptions.
+func (in *KappnavLoggingOptions) DeepCopy() *KappnavLoggingOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(KappnavLoggingOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KappnavMonitoringConfiguration) DeepCopyInto(out *KappnavMonitoringConfiguration) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KappnavMonitoringConfiguration.
+func (in *KappnavMonitoringConfiguration) DeepCopy() *KappnavMonitoringConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KappnavMonitoringConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KappnavProxyConfiguration) DeepCopyInto(out *KappnavProxyConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KappnavProxyConfiguration.
+func (in *KappnavProxyConfiguration) DeepCopy() *KappnavProxyConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KappnavProxyConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KappnavResourceConstraints) DeepCopyInto(out *KappnavResourceConstraints) {
 	*out = *in
@@ -153,6 +429,22 @@ func (in *KappnavResourceConstraints) DeepCopy() *KappnavResourceConstraints {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KappnavRouteConfiguration) DeepCopyInto(out *KappnavRouteConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KappnavRouteConfiguration.
+func (in *KappnavRouteConfiguration) DeepCopy() *KappnavRouteConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KappnavRouteConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KappnavSpec) DeepCopyInto(out *KappnavSpec) {
 	*out = *in
@@ -198,11 +490,46 @@ func (in *KappnavSpec) DeepCopyInto(out *KappnavSpec) {
 	}
 	if in.Logging != nil {
 		in, out := &in.Logging, &out.Logging
-		*out = make(map[string]string, len(*in))
+		*out = make(map[string]LogLevel, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
+	if in.LoggingOptions != nil {
+		in, out := &in.LoggingOptions, &out.LoggingOptions
+		*out = new(KappnavLoggingOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrustedCA != nil {
+		in, out := &in.TrustedCA, &out.TrustedCA
+		*out = new(KappnavTrustedCAConfiguration)
+		**out = **in
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(KappnavProxyConfiguration)
+		**out = **in
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(KappnavIngressConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = new(KappnavRouteConfiguration)
+		**out = **in
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(KappnavMonitoringConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Builtin != nil {
+		in, out := &in.Builtin, &out.Builtin
+		*out = new(KappnavBuiltinConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -226,6 +553,28 @@ func (in *KappnavStatus) DeepCopyInto(out *KappnavStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MapIssues != nil {
+		in, out := &in.MapIssues, &out.MapIssues
+		*out = make([]MapIssue, len(*in))
+		copy(*out, *in)
+	}
+	if in.DebugLogging != nil {
+		in, out := &in.DebugLogging, &out.DebugLogging
+		*out = new(DebugLoggingStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -239,6 +588,38 @@ func (in *KappnavStatus) DeepCopy() *KappnavStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KappnavTrustedCAConfiguration) DeepCopyInto(out *KappnavTrustedCAConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KappnavTrustedCAConfiguration.
+func (in *KappnavTrustedCAConfiguration) DeepCopy() *KappnavTrustedCAConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KappnavTrustedCAConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MapIssue) DeepCopyInto(out *MapIssue) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MapIssue.
+func (in *MapIssue) DeepCopy() *MapIssue {
+	if in == nil {
+		return nil
+	}
+	out := new(MapIssue)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Resources) DeepCopyInto(out *Resources) {
 	*out = *in
@@ -275,3 +656,28 @@ func (in *StatusCondition) DeepCopy() *StatusCondition {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.PhaseStartTime.DeepCopyInto(&out.PhaseStartTime)
+	if in.PreviousImages != nil {
+		in, out := &in.PreviousImages, &out.PreviousImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStatus.
+func (in *UpgradeStatus) DeepCopy() *UpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -11,9 +11,186 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"./pkg/apis/kappnav/v1.Kappnav":       schema_pkg_apis_kappnav_v1_Kappnav(ref),
-		"./pkg/apis/kappnav/v1.KappnavSpec":   schema_pkg_apis_kappnav_v1_KappnavSpec(ref),
-		"./pkg/apis/kappnav/v1.KappnavStatus": schema_pkg_apis_kappnav_v1_KappnavStatus(ref),
+		"./pkg/apis/kappnav/v1.ComponentStatus":                schema_pkg_apis_kappnav_v1_ComponentStatus(ref),
+		"./pkg/apis/kappnav/v1.DebugLoggingStatus":             schema_pkg_apis_kappnav_v1_DebugLoggingStatus(ref),
+		"./pkg/apis/kappnav/v1.Environment":                    schema_pkg_apis_kappnav_v1_Environment(ref),
+		"./pkg/apis/kappnav/v1.Kappnav":                        schema_pkg_apis_kappnav_v1_Kappnav(ref),
+		"./pkg/apis/kappnav/v1.KappnavBuiltinConfiguration":    schema_pkg_apis_kappnav_v1_KappnavBuiltinConfiguration(ref),
+		"./pkg/apis/kappnav/v1.KappnavContainerConfiguration":  schema_pkg_apis_kappnav_v1_KappnavContainerConfiguration(ref),
+		"./pkg/apis/kappnav/v1.KappnavContainerPort":           schema_pkg_apis_kappnav_v1_KappnavContainerPort(ref),
+		"./pkg/apis/kappnav/v1.KappnavContainerProbe":          schema_pkg_apis_kappnav_v1_KappnavContainerProbe(ref),
+		"./pkg/apis/kappnav/v1.KappnavContainerService":        schema_pkg_apis_kappnav_v1_KappnavContainerService(ref),
+		"./pkg/apis/kappnav/v1.KappnavDebugLogging":            schema_pkg_apis_kappnav_v1_KappnavDebugLogging(ref),
+		"./pkg/apis/kappnav/v1.KappnavImageConfiguration":      schema_pkg_apis_kappnav_v1_KappnavImageConfiguration(ref),
+		"./pkg/apis/kappnav/v1.KappnavIngressConfiguration":    schema_pkg_apis_kappnav_v1_KappnavIngressConfiguration(ref),
+		"./pkg/apis/kappnav/v1.KappnavLoggingOptions":          schema_pkg_apis_kappnav_v1_KappnavLoggingOptions(ref),
+		"./pkg/apis/kappnav/v1.KappnavMonitoringConfiguration": schema_pkg_apis_kappnav_v1_KappnavMonitoringConfiguration(ref),
+		"./pkg/apis/kappnav/v1.KappnavProxyConfiguration":      schema_pkg_apis_kappnav_v1_KappnavProxyConfiguration(ref),
+		"./pkg/apis/kappnav/v1.KappnavResourceConstraints":     schema_pkg_apis_kappnav_v1_KappnavResourceConstraints(ref),
+		"./pkg/apis/kappnav/v1.KappnavRouteConfiguration":      schema_pkg_apis_kappnav_v1_KappnavRouteConfiguration(ref),
+		"./pkg/apis/kappnav/v1.KappnavSpec":                    schema_pkg_apis_kappnav_v1_KappnavSpec(ref),
+		"./pkg/apis/kappnav/v1.KappnavStatus":                  schema_pkg_apis_kappnav_v1_KappnavStatus(ref),
+		"./pkg/apis/kappnav/v1.KappnavTrustedCAConfiguration":  schema_pkg_apis_kappnav_v1_KappnavTrustedCAConfiguration(ref),
+		"./pkg/apis/kappnav/v1.MapIssue":                       schema_pkg_apis_kappnav_v1_MapIssue(ref),
+		"./pkg/apis/kappnav/v1.Resources":                      schema_pkg_apis_kappnav_v1_Resources(ref),
+		"./pkg/apis/kappnav/v1.StatusCondition":                schema_pkg_apis_kappnav_v1_StatusCondition(ref),
+		"./pkg/apis/kappnav/v1.UpgradeStatus":                  schema_pkg_apis_kappnav_v1_UpgradeStatus(ref),
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_ComponentStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ComponentStatus reports the image and rollout progress of a kappnav container within one of the Deployments managed by the operator.",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"deployment": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"desiredImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DesiredImage is the image reference set on the Deployment by the operator",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imageIDs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImageIDs are the distinct image IDs reported by the running pods",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"upToDatePods": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UpToDatePods is the number of pods of the current revision of the Deployment",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"replicas": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"readyReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"updatedReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"rolloutState": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"lastUpdateTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"name", "deployment", "upToDatePods", "replicas", "readyReplicas", "updatedReplicas"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_DebugLoggingStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DebugLoggingStatus reports the period of temporary debug logging",
+				Properties: map[string]spec.Schema{
+					"components": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Components are the components being debugged, all when empty",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"level": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"expiryTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"active": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Active is false once the period has expired",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"level", "startTime", "expiryTime", "active"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_Environment(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Environment variables.",
+				Properties: map[string]spec.Schema{
+					"kubeEnv": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KubeEnv is the type of Kubernetes cluster kAppNav is installed in",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
 	}
 }
 
@@ -21,42 +198,601 @@ func schema_pkg_apis_kappnav_v1_Kappnav(ref common.ReferenceCallback) common.Ope
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Kappnav is the Schema for the kappnavs API",
+				Description: "Kappnav is the Schema for the kappnavs API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/kappnav/v1.KappnavSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/kappnav/v1.KappnavStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/kappnav/v1.KappnavSpec", "./pkg/apis/kappnav/v1.KappnavStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavBuiltinConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavBuiltinConfiguration defines how the operator maintains the builtin config map. The console URLs are refreshed when the console configuration changes and every resync period.",
+				Properties: map[string]spec.Schema{
+					"resyncPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResyncPeriod is how often the builtin config map is refreshed, e.g. 30m, only when the CR or the console configuration changes when empty",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"policies": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Policies maps keys of the builtin config map, e.g. openshift-console-url, to the policy used to maintain them, FillIfEmpty when not listed",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavContainerConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavContainerConfiguration defines the configuration for a Kappnav container",
+				Properties: map[string]spec.Schema{
+					"repository": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Repository is the image repository of the container",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tag": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tag is the image tag of the container",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"digest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Digest is the image digest of the container, which takes precedence over the tag",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources are the resource requests and limits of the container",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavResourceConstraints"),
+						},
+					},
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Target is where an extension container runs: in the UI pod, in the controller pod or in a Deployment of its own. Extension containers without a target are not deployed. Ignored for the kAppNav containers.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ports": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ports are the ports of an extension container",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/kappnav/v1.KappnavContainerPort"),
+									},
+								},
+							},
+						},
+					},
+					"service": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Service exposes the ports of an extension container",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavContainerService"),
+						},
+					},
+					"readinessProbe": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadinessProbe is the readiness probe of an extension container",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavContainerProbe"),
+						},
+					},
+					"livenessProbe": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LivenessProbe is the liveness probe of an extension container",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavContainerProbe"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/kappnav/v1.KappnavContainerPort", "./pkg/apis/kappnav/v1.KappnavContainerProbe", "./pkg/apis/kappnav/v1.KappnavContainerService", "./pkg/apis/kappnav/v1.KappnavResourceConstraints"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavContainerPort(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavContainerPort is a port of an extension container",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the port",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"port": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Port is the number of the port",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"protocol": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Protocol is the protocol of the port, TCP by default",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "port"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavContainerProbe(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavContainerProbe is an HTTP GET probe of an extension container, or a TCP probe when no path is set",
+				Properties: map[string]spec.Schema{
+					"port": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Port is the port probed",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the path of the HTTP GET request",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"scheme": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Scheme is the scheme of the HTTP GET request, HTTP by default",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"initialDelaySeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InitialDelaySeconds is the time before the first probe",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"periodSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PeriodSeconds is the time between probes",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"failureThreshold": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailureThreshold is the number of failed probes before the container is considered unready or is restarted",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"port"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavContainerService(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavContainerService exposes the ports of an extension container in a Service named after the container",
+				Properties: map[string]spec.Schema{
+					"enabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled creates the Service",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type is the type of the Service, ClusterIP by default",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"annotations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Annotations are added to the Service",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavDebugLogging(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavDebugLogging raises the log level of components for a limited time, after which the levels of the logging field apply again. Changing any field starts a new period.",
+				Properties: map[string]spec.Schema{
+					"components": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Components are the components to debug (operator, apis, ui, controller), all when empty",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"level": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Level is the log level of the components while debugging, debug by default",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"duration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Duration is how long the components are debugged for, e.g. 30m",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"duration"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavImageConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavImageConfiguration ...",
+				Properties: map[string]spec.Schema{
+					"pullPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PullPolicy is the image pull policy of all containers",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pullSecrets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PullSecrets are the names of the image pull secrets of all containers",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"registry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Registry replaces the registry host of every image repository, e.g. to pull from a mirror",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavIngressConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavIngressConfiguration defines the host and annotations of the UI Ingress.",
+				Properties: map[string]spec.Schema{
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Host is the host name of the Ingress rule, any host when empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"annotations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Annotations are added to the Ingress, e.g. to select an ingress controller",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavLoggingOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavLoggingOptions defines how the log levels reach the kAppNav containers, the log levels of packages and temporary debug logging.",
+				Properties: map[string]spec.Schema{
+					"propagation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Propagation is how the log levels reach the containers, ConfigMap by default",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"packages": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Packages maps a component (apis, ui, controller) to the log levels of its packages, which override the log level of the component",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type: []string{"object"},
+										AdditionalProperties: &spec.SchemaOrBool{
+											Schema: &spec.Schema{
+												SchemaProps: spec.SchemaProps{
+													Type:   []string{"string"},
+													Format: "",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					"debug": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Debug raises the log level of components for a limited time",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavDebugLogging"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/kappnav/v1.KappnavDebugLogging"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavMonitoringConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavMonitoringConfiguration defines the Prometheus monitoring of the kAppNav components. The resources are only created when the monitoring.coreos.com API of the Prometheus operator is available.",
+				Properties: map[string]spec.Schema{
+					"enabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled creates ServiceMonitors for the API, UI and controller",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"labels": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Labels are added to the ServiceMonitors and the PrometheusRule, e.g. to match the selectors of a Prometheus",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"interval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Interval is how often the components are scraped, e.g. 30s, the interval of the Prometheus when empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"alerts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Alerts creates a PrometheusRule with the default alerts, true by default",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"certificateExpiryDays": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CertificateExpiryDays is how many days before the UI certificate expires the certificate alert fires, 30 by default",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavProxyConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavProxyConfiguration defines the HTTP proxy settings passed to the kappnav containers. Values not set in the CR are taken from the OpenShift cluster Proxy resource and then from the operator's own environment.",
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"httpProxy": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Description: "HTTPProxy is the proxy URL for HTTP requests",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"apiVersion": {
+					"httpsProxy": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Description: "HTTPSProxy is the proxy URL for HTTPS requests",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
+					"noProxy": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Description: "NoProxy is a comma separated list of hosts and domains that bypass the proxy",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"spec": {
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavResourceConstraints(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavResourceConstraints defines resource constraints for a Kappnav container",
+				Properties: map[string]spec.Schema{
+					"enabled": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/kappnav/v1.KappnavSpec"),
+							Description: "Enabled applies the requests and limits to the container",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"status": {
+					"requests": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/kappnav/v1.KappnavStatus"),
+							Description: "Requests are the resources requested by the container",
+							Ref:         ref("./pkg/apis/kappnav/v1.Resources"),
+						},
+					},
+					"limits": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Limits are the maximum resources used by the container",
+							Ref:         ref("./pkg/apis/kappnav/v1.Resources"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/kappnav/v1.KappnavSpec", "./pkg/apis/kappnav/v1.KappnavStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"./pkg/apis/kappnav/v1.Resources"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavRouteConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavRouteConfiguration defines the host and TLS termination of the UI Route.",
+				Properties: map[string]spec.Schema{
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Host is the host name of the Route, generated by OpenShift when empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"termination": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Termination is the TLS termination of the Route, reencrypt by default",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
 	}
 }
 
@@ -68,22 +804,26 @@ func schema_pkg_apis_kappnav_v1_KappnavSpec(ref common.ReferenceCallback) common
 				Properties: map[string]spec.Schema{
 					"appNavAPI": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/kappnav/v1.KappnavContainerConfiguration"),
+							Description: "AppNavAPI configures the kAppNav REST API container",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavContainerConfiguration"),
 						},
 					},
 					"appNavController": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/kappnav/v1.KappnavContainerConfiguration"),
+							Description: "AppNavController configures the kAppNav controller container",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavContainerConfiguration"),
 						},
 					},
 					"appNavUI": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/kappnav/v1.KappnavContainerConfiguration"),
+							Description: "AppNavUI configures the kAppNav UI container",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavContainerConfiguration"),
 						},
 					},
 					"extensionContainers": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"object"},
+							Description: "ExtensionContainers configures additional containers such as the oauth-proxy, keyed by container",
+							Type:        []string{"object"},
 							AdditionalProperties: &spec.SchemaOrBool{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
@@ -95,17 +835,20 @@ func schema_pkg_apis_kappnav_v1_KappnavSpec(ref common.ReferenceCallback) common
 					},
 					"image": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/kappnav/v1.KappnavImageConfiguration"),
+							Description: "Image configures the pull policy, pull secrets and registry of all images",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavImageConfiguration"),
 						},
 					},
 					"env": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/kappnav/v1.Environment"),
+							Description: "Env describes the environment kAppNav is installed in",
+							Ref:         ref("./pkg/apis/kappnav/v1.Environment"),
 						},
 					},
 					"logging": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"object"},
+							Description: "Logging maps a component (operator, apis, ui, controller) to its log level",
+							Type:        []string{"object"},
 							AdditionalProperties: &spec.SchemaOrBool{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
@@ -116,11 +859,60 @@ func schema_pkg_apis_kappnav_v1_KappnavSpec(ref common.ReferenceCallback) common
 							},
 						},
 					},
+					"loggingOptions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LoggingOptions configures how the log levels reach the kAppNav containers, the log levels of packages and temporary debug logging",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavLoggingOptions"),
+						},
+					},
+					"trustedCA": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TrustedCA configures the CA bundle mounted into the kAppNav containers",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavTrustedCAConfiguration"),
+						},
+					},
+					"proxy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Proxy configures the HTTP proxy settings passed to the kAppNav containers",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavProxyConfiguration"),
+						},
+					},
+					"ingress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ingress configures the UI Ingress created on Kubernetes",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavIngressConfiguration"),
+						},
+					},
+					"route": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Route configures the UI Route created on OpenShift",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavRouteConfiguration"),
+						},
+					},
+					"monitoring": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Monitoring configures the ServiceMonitors and PrometheusRule of the kAppNav components",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavMonitoringConfiguration"),
+						},
+					},
+					"isolation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Isolation selects the names of the resources of the CR that are shared by the kAppNav installation of a namespace, Shared by default",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"builtin": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Builtin configures how the console URLs and dashboard names of the builtin config map are kept up to date",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavBuiltinConfiguration"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/kappnav/v1.Environment", "./pkg/apis/kappnav/v1.KappnavContainerConfiguration", "./pkg/apis/kappnav/v1.KappnavImageConfiguration"},
+			"./pkg/apis/kappnav/v1.Environment", "./pkg/apis/kappnav/v1.KappnavBuiltinConfiguration", "./pkg/apis/kappnav/v1.KappnavContainerConfiguration", "./pkg/apis/kappnav/v1.KappnavImageConfiguration", "./pkg/apis/kappnav/v1.KappnavIngressConfiguration", "./pkg/apis/kappnav/v1.KappnavLoggingOptions", "./pkg/apis/kappnav/v1.KappnavMonitoringConfiguration", "./pkg/apis/kappnav/v1.KappnavProxyConfiguration", "./pkg/apis/kappnav/v1.KappnavRouteConfiguration", "./pkg/apis/kappnav/v1.KappnavTrustedCAConfiguration"},
 	}
 }
 
@@ -132,7 +924,8 @@ func schema_pkg_apis_kappnav_v1_KappnavStatus(ref common.ReferenceCallback) comm
 				Properties: map[string]spec.Schema{
 					"conditions": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "Conditions report the result of the last reconcile",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
@@ -142,10 +935,282 @@ func schema_pkg_apis_kappnav_v1_KappnavStatus(ref common.ReferenceCallback) comm
 							},
 						},
 					},
+					"components": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Components report the image and rollout progress of each kAppNav container",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/kappnav/v1.ComponentStatus"),
+									},
+								},
+							},
+						},
+					},
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version is the kAppNav version installed by the operator",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"upgrade": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Upgrade reports the progress of an upgrade",
+							Ref:         ref("./pkg/apis/kappnav/v1.UpgradeStatus"),
+						},
+					},
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL is the URL of the kAppNav UI",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"mapIssues": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MapIssues are the errors found in the action, section and status config maps",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/kappnav/v1.MapIssue"),
+									},
+								},
+							},
+						},
+					},
+					"debugLogging": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DebugLogging reports the temporary debug logging requested in the spec",
+							Ref:         ref("./pkg/apis/kappnav/v1.DebugLoggingStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/kappnav/v1.ComponentStatus", "./pkg/apis/kappnav/v1.DebugLoggingStatus", "./pkg/apis/kappnav/v1.MapIssue", "./pkg/apis/kappnav/v1.StatusCondition", "./pkg/apis/kappnav/v1.UpgradeStatus"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_KappnavTrustedCAConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavTrustedCAConfiguration defines the source of the CA bundle that is mounted into the kappnav containers. Either ConfigMapName references an existing ConfigMap holding the bundle, or InjectOpenShiftBundle requests the operator to create a ConfigMap that OpenShift fills with the cluster bundle.",
+				Properties: map[string]spec.Schema{
+					"configMapName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigMapName is the name of an existing ConfigMap holding the CA bundle. The bundle replaces the system trust store of the containers, so it must be a full bundle holding the public CAs as well as the private ones.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"key": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Key is the key of the CA bundle in the ConfigMap, ca-bundle.crt by default",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"injectOpenShiftBundle": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InjectOpenShiftBundle requests a ConfigMap that OpenShift fills with the cluster CA bundle",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_MapIssue(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MapIssue is an error found in a kAppNav config map",
+				Properties: map[string]spec.Schema{
+					"map": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Map is the name of the config map",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"location": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Location is the key of the config map and the path in its JSON document",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"map", "message"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_Resources(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Resources ...",
+				Properties: map[string]spec.Schema{
+					"cpu": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CPU is a quantity of CPU such as 500m",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"memory": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Memory is a quantity of memory such as 512Mi",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_StatusCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StatusCondition ...",
+				Properties: map[string]spec.Schema{
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastUpdateTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v1_UpgradeStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UpgradeStatus reports the progress of an upgrade from the installed kAppNav version to the version of the operator.",
+				Properties: map[string]spec.Schema{
+					"fromVersion": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"toVersion": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"deployment": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Deployment is the Deployment currently being rolled",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObservedGeneration is the generation of the CR the upgrade was last attempted with",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"phaseStartTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"previousImages": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PreviousImages maps deployment/container to the image used before the upgrade",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
 				},
+				Required: []string{"toVersion", "phase"},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/kappnav/v1.StatusCondition"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
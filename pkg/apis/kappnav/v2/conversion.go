@@ -0,0 +1,368 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"encoding/json"
+	"sort"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+)
+
+const (
+	// V1PreservedAnnotation holds the v1 values that have no v2 representation, so
+	// that a v1 object converted to v2 and back is unchanged.
+	V1PreservedAnnotation string = "kappnav.operator.kappnav.io/v1-preserved"
+	// V2PreservedAnnotation holds the v2 values that have no v1 representation, so
+	// that a v2 object stored as v1 and read back is unchanged.
+	V2PreservedAnnotation string = "kappnav.operator.kappnav.io/v2-preserved"
+
+	// oauthProxyKey is the v1 extension container key of the oauth-proxy container
+	oauthProxyKey = "oauthProxy"
+
+	v1LoggingOperator   = "operator"
+	v1LoggingAPI        = "apis"
+	v1LoggingUI         = "ui"
+	v1LoggingController = "controller"
+)
+
+// v1Preserved holds the v1 values that have no v2 representation.
+type v1Preserved struct {
+	KubeEnv string                        `json:"kubeEnv,omitempty"`
+	Logging map[string]kappnavv1.LogLevel `json:"logging,omitempty"`
+	// EmptyEnv is true when env is set without a kubeEnv
+	EmptyEnv bool `json:"emptyEnv,omitempty"`
+}
+
+// v2Preserved holds the v2 values that have no v1 representation.
+type v2Preserved struct {
+	// EmptyAuth is true when auth is set without an oauthProxy
+	EmptyAuth bool `json:"emptyAuth,omitempty"`
+}
+
+var kubeEnvToPlatform = map[string]Platform{
+	"k8s":       PlatformKubernetes,
+	"minikube":  PlatformMinikube,
+	"minishift": PlatformMinishift,
+	"okd":       PlatformOKD,
+	"ocp":       PlatformOpenShift,
+}
+
+// ConvertFromV1 converts a v1 Kappnav to v2.
+func ConvertFromV1(src *kappnavv1.Kappnav, dst *Kappnav) error {
+	dst.TypeMeta = src.TypeMeta
+	dst.TypeMeta.APIVersion = SchemeGroupVersion.String()
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	src.Status.DeepCopyInto(&dst.Status)
+
+	v2Values := v2Preserved{}
+	if err := takeAnnotation(&dst.ObjectMeta.Annotations, V2PreservedAnnotation, &v2Values); err != nil {
+		return err
+	}
+
+	in := src.Spec.DeepCopy()
+	out := &dst.Spec
+	*out = KappnavSpec{}
+	preserved := v1Preserved{}
+
+	if in.Env != nil {
+		if len(in.Env.KubeEnv) == 0 {
+			preserved.EmptyEnv = true
+		} else if platform, ok := kubeEnvToPlatform[in.Env.KubeEnv]; ok {
+			out.Platform = platform
+		} else {
+			preserved.KubeEnv = in.Env.KubeEnv
+		}
+	}
+	out.API = containerFromV1(in.AppNavAPI)
+	out.UI = containerFromV1(in.AppNavUI)
+	out.Controller = containerFromV1(in.AppNavController)
+
+	var names []string
+	for name := range in.ExtensionContainers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == oauthProxyKey {
+			out.Auth = &AuthConfiguration{OAuthProxy: containerFromV1(in.ExtensionContainers[name])}
+			continue
+		}
+		container := ExtensionContainer{Name: name}
+		if c := containerFromV1(in.ExtensionContainers[name]); c != nil {
+			container.ContainerConfiguration = *c
+		}
+		if c := in.ExtensionContainers[name]; c != nil {
+			container.Target = c.Target
+			container.Ports = c.Ports
+			container.Service = c.Service
+			container.ReadinessProbe = c.ReadinessProbe
+			container.LivenessProbe = c.LivenessProbe
+		}
+		out.ExtensionContainers = append(out.ExtensionContainers, container)
+	}
+	if out.Auth == nil && v2Values.EmptyAuth {
+		out.Auth = &AuthConfiguration{}
+	}
+
+	if in.Image != nil {
+		out.Image = &ImageConfiguration{
+			PullPolicy:  in.Image.PullPolicy,
+			PullSecrets: in.Image.PullSecrets,
+			Registry:    in.Image.Registry,
+		}
+	}
+	if in.Logging != nil {
+		out.Logging = &LoggingConfiguration{}
+		for key, level := range in.Logging {
+			switch key {
+			case v1LoggingOperator:
+				out.Logging.Operator = level
+			case v1LoggingAPI:
+				out.Logging.API = level
+			case v1LoggingUI:
+				out.Logging.UI = level
+			case v1LoggingController:
+				out.Logging.Controller = level
+			default:
+				if preserved.Logging == nil {
+					preserved.Logging = make(map[string]kappnavv1.LogLevel)
+				}
+				preserved.Logging[key] = level
+			}
+		}
+	}
+	if in.LoggingOptions != nil {
+		if out.Logging == nil {
+			out.Logging = &LoggingConfiguration{}
+		}
+		out.Logging.Propagation = in.LoggingOptions.Propagation
+		out.Logging.Packages = in.LoggingOptions.Packages
+		out.Logging.Debug = in.LoggingOptions.Debug
+	}
+	if in.Ingress != nil {
+		out.Ingress = &IngressConfiguration{Host: in.Ingress.Host, Annotations: in.Ingress.Annotations}
+	}
+	if in.Route != nil {
+		out.Route = &RouteConfiguration{Host: in.Route.Host, Termination: in.Route.Termination}
+	}
+	out.Monitoring = in.Monitoring
+	out.Isolation = in.Isolation
+	out.Builtin = in.Builtin
+	if in.TrustedCA != nil {
+		out.TrustedCA = &TrustedCAConfiguration{
+			ConfigMapName:         in.TrustedCA.ConfigMapName,
+			Key:                   in.TrustedCA.Key,
+			InjectOpenShiftBundle: in.TrustedCA.InjectOpenShiftBundle,
+		}
+	}
+	if in.Proxy != nil {
+		out.Proxy = &ProxyConfiguration{
+			HTTPProxy:  in.Proxy.HTTPProxy,
+			HTTPSProxy: in.Proxy.HTTPSProxy,
+			NoProxy:    in.Proxy.NoProxy,
+		}
+	}
+
+	if len(preserved.KubeEnv) > 0 || len(preserved.Logging) > 0 || preserved.EmptyEnv {
+		if err := setAnnotation(&dst.ObjectMeta.Annotations, V1PreservedAnnotation, preserved); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConvertToV1 converts a v2 Kappnav to v1.
+func ConvertToV1(src *Kappnav, dst *kappnavv1.Kappnav) error {
+	dst.TypeMeta = src.TypeMeta
+	dst.TypeMeta.APIVersion = kappnavv1.SchemeGroupVersion.String()
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	src.Status.DeepCopyInto(&dst.Status)
+
+	preserved := v1Preserved{}
+	if err := takeAnnotation(&dst.ObjectMeta.Annotations, V1PreservedAnnotation, &preserved); err != nil {
+		return err
+	}
+
+	in := src.Spec.DeepCopy()
+	out := &dst.Spec
+	*out = kappnavv1.KappnavSpec{}
+
+	if len(in.Platform) > 0 {
+		for kubeEnv, platform := range kubeEnvToPlatform {
+			if platform == in.Platform {
+				out.Env = &kappnavv1.Environment{KubeEnv: kubeEnv}
+			}
+		}
+	} else if len(preserved.KubeEnv) > 0 {
+		out.Env = &kappnavv1.Environment{KubeEnv: preserved.KubeEnv}
+	} else if preserved.EmptyEnv {
+		out.Env = &kappnavv1.Environment{}
+	}
+	out.AppNavAPI = containerToV1(in.API)
+	out.AppNavUI = containerToV1(in.UI)
+	out.AppNavController = containerToV1(in.Controller)
+
+	if len(in.ExtensionContainers) > 0 || (in.Auth != nil && in.Auth.OAuthProxy != nil) {
+		out.ExtensionContainers = make(map[string]*kappnavv1.KappnavContainerConfiguration)
+	}
+	for i := range in.ExtensionContainers {
+		extension := &in.ExtensionContainers[i]
+		container := containerToV1(&extension.ContainerConfiguration)
+		container.Target = extension.Target
+		container.Ports = extension.Ports
+		container.Service = extension.Service
+		container.ReadinessProbe = extension.ReadinessProbe
+		container.LivenessProbe = extension.LivenessProbe
+		out.ExtensionContainers[extension.Name] = container
+	}
+	if in.Auth != nil && in.Auth.OAuthProxy != nil {
+		out.ExtensionContainers[oauthProxyKey] = containerToV1(in.Auth.OAuthProxy)
+	}
+
+	if in.Image != nil {
+		out.Image = &kappnavv1.KappnavImageConfiguration{
+			PullPolicy:  in.Image.PullPolicy,
+			PullSecrets: in.Image.PullSecrets,
+			Registry:    in.Image.Registry,
+		}
+	}
+	if in.Logging != nil || len(preserved.Logging) > 0 {
+		out.Logging = make(map[string]kappnavv1.LogLevel)
+		for key, level := range preserved.Logging {
+			out.Logging[key] = level
+		}
+		if in.Logging != nil {
+			setV1LogLevel(out.Logging, v1LoggingOperator, in.Logging.Operator)
+			setV1LogLevel(out.Logging, v1LoggingAPI, in.Logging.API)
+			setV1LogLevel(out.Logging, v1LoggingUI, in.Logging.UI)
+			setV1LogLevel(out.Logging, v1LoggingController, in.Logging.Controller)
+		}
+	}
+	if in.Logging != nil && (len(in.Logging.Propagation) > 0 || in.Logging.Packages != nil || in.Logging.Debug != nil) {
+		out.LoggingOptions = &kappnavv1.KappnavLoggingOptions{
+			Propagation: in.Logging.Propagation,
+			Packages:    in.Logging.Packages,
+			Debug:       in.Logging.Debug,
+		}
+	}
+	if in.Ingress != nil {
+		out.Ingress = &kappnavv1.KappnavIngressConfiguration{Host: in.Ingress.Host, Annotations: in.Ingress.Annotations}
+	}
+	if in.Route != nil {
+		out.Route = &kappnavv1.KappnavRouteConfiguration{Host: in.Route.Host, Termination: in.Route.Termination}
+	}
+	out.Monitoring = in.Monitoring
+	out.Isolation = in.Isolation
+	out.Builtin = in.Builtin
+	if in.TrustedCA != nil {
+		out.TrustedCA = &kappnavv1.KappnavTrustedCAConfiguration{
+			ConfigMapName:         in.TrustedCA.ConfigMapName,
+			Key:                   in.TrustedCA.Key,
+			InjectOpenShiftBundle: in.TrustedCA.InjectOpenShiftBundle,
+		}
+	}
+	if in.Proxy != nil {
+		out.Proxy = &kappnavv1.KappnavProxyConfiguration{
+			HTTPProxy:  in.Proxy.HTTPProxy,
+			HTTPSProxy: in.Proxy.HTTPSProxy,
+			NoProxy:    in.Proxy.NoProxy,
+		}
+	}
+
+	if in.Auth != nil && in.Auth.OAuthProxy == nil {
+		if err := setAnnotation(&dst.ObjectMeta.Annotations, V2PreservedAnnotation, v2Preserved{EmptyAuth: true}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setAnnotation stores value as JSON in the annotation key.
+func setAnnotation(annotations *map[string]string, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if *annotations == nil {
+		*annotations = make(map[string]string)
+	}
+	(*annotations)[key] = string(data)
+	return nil
+}
+
+// takeAnnotation reads the JSON value of the annotation key, if it is set, and
+// removes the annotation.
+func takeAnnotation(annotations *map[string]string, key string, value interface{}) error {
+	data, ok := (*annotations)[key]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(data), value); err != nil {
+		return err
+	}
+	delete(*annotations, key)
+	if len(*annotations) == 0 {
+		*annotations = nil
+	}
+	return nil
+}
+
+func containerFromV1(in *kappnavv1.KappnavContainerConfiguration) *ContainerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := &ContainerConfiguration{
+		Repository: string(in.Repository),
+		Tag:        string(in.Tag),
+		Digest:     string(in.Digest),
+	}
+	if in.Resources != nil {
+		out.Resources = &ResourceConstraints{Enabled: in.Resources.Enabled}
+		if in.Resources.Requests != nil {
+			out.Resources.Requests = &Resources{CPU: in.Resources.Requests.CPU, Memory: in.Resources.Requests.Memory}
+		}
+		if in.Resources.Limits != nil {
+			out.Resources.Limits = &Resources{CPU: in.Resources.Limits.CPU, Memory: in.Resources.Limits.Memory}
+		}
+	}
+	return out
+}
+
+func containerToV1(in *ContainerConfiguration) *kappnavv1.KappnavContainerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := &kappnavv1.KappnavContainerConfiguration{
+		Repository: kappnavv1.Repository(in.Repository),
+		Tag:        kappnavv1.Tag(in.Tag),
+		Digest:     kappnavv1.Digest(in.Digest),
+	}
+	if in.Resources != nil {
+		out.Resources = &kappnavv1.KappnavResourceConstraints{Enabled: in.Resources.Enabled}
+		if in.Resources.Requests != nil {
+			out.Resources.Requests = &kappnavv1.Resources{CPU: in.Resources.Requests.CPU, Memory: in.Resources.Requests.Memory}
+		}
+		if in.Resources.Limits != nil {
+			out.Resources.Limits = &kappnavv1.Resources{CPU: in.Resources.Limits.CPU, Memory: in.Resources.Limits.Memory}
+		}
+	}
+	return out
+}
+
+func setV1LogLevel(logging map[string]kappnavv1.LogLevel, key string, level kappnavv1.LogLevel) {
+	if len(level) > 0 {
+		logging[key] = level
+	}
+}
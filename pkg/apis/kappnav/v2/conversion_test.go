@@ -0,0 +1,126 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"reflect"
+	"testing"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestV1RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		spec kappnavv1.KappnavSpec
+	}{
+		{name: "empty"},
+		{name: "empty env", spec: kappnavv1.KappnavSpec{Env: &kappnavv1.Environment{}}},
+		{name: "known kubeEnv", spec: kappnavv1.KappnavSpec{Env: &kappnavv1.Environment{KubeEnv: "ocp"}}},
+		{name: "unknown kubeEnv", spec: kappnavv1.KappnavSpec{Env: &kappnavv1.Environment{KubeEnv: "iks"}}},
+		{name: "logging", spec: kappnavv1.KappnavSpec{Logging: map[string]kappnavv1.LogLevel{
+			"operator": "debug",
+			"ui":       "warning",
+			"inv":      "error",
+		}}},
+		{name: "extension containers", spec: kappnavv1.KappnavSpec{
+			ExtensionContainers: map[string]*kappnavv1.KappnavContainerConfiguration{
+				"oauthProxy": {Repository: "quay.io/openshift/origin-oauth-proxy", Tag: "4.3.0"},
+				"appNavInv": {
+					Repository: "kappnav/inv",
+					Digest:     "sha256:0123",
+					Target:     kappnavv1.ExtensionContainerTargetDeployment,
+				},
+			},
+		}},
+		{name: "images", spec: kappnavv1.KappnavSpec{
+			AppNavAPI: &kappnavv1.KappnavContainerConfiguration{
+				Repository: "kappnav/apis",
+				Tag:        "0.1.0",
+				Resources: &kappnavv1.KappnavResourceConstraints{
+					Enabled:  true,
+					Requests: &kappnavv1.Resources{CPU: "100m", Memory: "64Mi"},
+				},
+			},
+			Image: &kappnavv1.KappnavImageConfiguration{Registry: "registry.example.com"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original := &kappnavv1.Kappnav{
+				ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "kappnav"},
+				Spec:       test.spec,
+			}
+			converted := &Kappnav{}
+			if err := ConvertFromV1(original.DeepCopy(), converted); err != nil {
+				t.Fatalf("ConvertFromV1 failed: %s", err)
+			}
+			roundTrip := &kappnavv1.Kappnav{}
+			if err := ConvertToV1(converted, roundTrip); err != nil {
+				t.Fatalf("ConvertToV1 failed: %s", err)
+			}
+			if !reflect.DeepEqual(original.ObjectMeta, roundTrip.ObjectMeta) {
+				t.Errorf("metadata changed: got %+v, want %+v", roundTrip.ObjectMeta, original.ObjectMeta)
+			}
+			if !reflect.DeepEqual(original.Spec, roundTrip.Spec) {
+				t.Errorf("spec changed: got %+v, want %+v", roundTrip.Spec, original.Spec)
+			}
+		})
+	}
+}
+
+func TestV2RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		spec KappnavSpec
+	}{
+		{name: "empty"},
+		{name: "platform", spec: KappnavSpec{Platform: PlatformOpenShift}},
+		{name: "auth without oauthProxy", spec: KappnavSpec{Auth: &AuthConfiguration{}}},
+		{name: "auth", spec: KappnavSpec{Auth: &AuthConfiguration{
+			OAuthProxy: &ContainerConfiguration{Repository: "quay.io/openshift/origin-oauth-proxy", Tag: "4.3.0"},
+		}}},
+		{name: "logging", spec: KappnavSpec{Logging: &LoggingConfiguration{Operator: "debug", Controller: "info"}}},
+		{name: "extension containers", spec: KappnavSpec{ExtensionContainers: []ExtensionContainer{
+			{
+				Name:                   "appNavInv",
+				ContainerConfiguration: ContainerConfiguration{Repository: "kappnav/inv", Tag: "0.1.0"},
+				Target:                 kappnavv1.ExtensionContainerTargetUI,
+			},
+		}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original := &Kappnav{
+				ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "kappnav"},
+				Spec:       test.spec,
+			}
+			converted := &kappnavv1.Kappnav{}
+			if err := ConvertToV1(original.DeepCopy(), converted); err != nil {
+				t.Fatalf("ConvertToV1 failed: %s", err)
+			}
+			roundTrip := &Kappnav{}
+			if err := ConvertFromV1(converted, roundTrip); err != nil {
+				t.Fatalf("ConvertFromV1 failed: %s", err)
+			}
+			if !reflect.DeepEqual(original.ObjectMeta, roundTrip.ObjectMeta) {
+				t.Errorf("metadata changed: got %+v, want %+v", roundTrip.ObjectMeta, original.ObjectMeta)
+			}
+			if !reflect.DeepEqual(original.Spec, roundTrip.Spec) {
+				t.Errorf("spec changed: got %+v, want %+v", roundTrip.Spec, original.Spec)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KappnavSpec defines the desired state of Kappnav
+// +k8s:openapi-gen=true
+type KappnavSpec struct {
+	// Platform is the type of Kubernetes cluster kAppNav is installed in
+	Platform Platform `json:"platform,omitempty"`
+	// API configures the kAppNav REST API container
+	API *ContainerConfiguration `json:"api,omitempty"`
+	// UI configures the kAppNav UI container
+	UI *ContainerConfiguration `json:"ui,omitempty"`
+	// Controller configures the kAppNav controller container
+	Controller *ContainerConfiguration `json:"controller,omitempty"`
+	// ExtensionContainers configures additional containers by name
+	// +listType=map
+	// +listMapKey=name
+	ExtensionContainers []ExtensionContainer `json:"extensionContainers,omitempty"`
+	// Image configures the pull policy, pull secrets and registry of all images
+	Image *ImageConfiguration `json:"image,omitempty"`
+	// Logging configures the log level of each component
+	Logging *LoggingConfiguration `json:"logging,omitempty"`
+	// Auth configures the authentication in front of the UI
+	Auth *AuthConfiguration `json:"auth,omitempty"`
+	// Ingress configures the UI Ingress created on Kubernetes
+	Ingress *IngressConfiguration `json:"ingress,omitempty"`
+	// Route configures the UI Route created on OpenShift
+	Route *RouteConfiguration `json:"route,omitempty"`
+	// TrustedCA configures the CA bundle mounted into the kAppNav containers
+	TrustedCA *TrustedCAConfiguration `json:"trustedCA,omitempty"`
+	// Proxy configures the HTTP proxy settings passed to the kAppNav containers
+	Proxy *ProxyConfiguration `json:"proxy,omitempty"`
+	// Monitoring configures the ServiceMonitors and PrometheusRule of the kAppNav components
+	Monitoring *kappnavv1.KappnavMonitoringConfiguration `json:"monitoring,omitempty"`
+	// Isolation selects the names of the resources of the CR that are shared by
+	// the kAppNav installation of a namespace, Shared by default
+	Isolation kappnavv1.IsolationMode `json:"isolation,omitempty"`
+	// Builtin configures how the console URLs and dashboard names of the builtin
+	// config map are kept up to date
+	Builtin *kappnavv1.KappnavBuiltinConfiguration `json:"builtin,omitempty"`
+}
+
+// Platform ...
+// +kubebuilder:validation:Enum=Kubernetes;Minikube;Minishift;OKD;OpenShift
+type Platform string
+
+const (
+	// PlatformKubernetes ...
+	PlatformKubernetes Platform = "Kubernetes"
+	// PlatformMinikube ...
+	PlatformMinikube Platform = "Minikube"
+	// PlatformMinishift ...
+	PlatformMinishift Platform = "Minishift"
+	// PlatformOKD ...
+	PlatformOKD Platform = "OKD"
+	// PlatformOpenShift is OpenShift Container Platform
+	PlatformOpenShift Platform = "OpenShift"
+)
+
+// ContainerConfiguration defines the image and resources of a kAppNav container
+// +k8s:openapi-gen=true
+type ContainerConfiguration struct {
+	// Repository is the image repository of the container
+	Repository string `json:"repository,omitempty"`
+	// Tag is the image tag of the container
+	Tag string `json:"tag,omitempty"`
+	// Digest is the image digest of the container, which takes precedence over the tag
+	// +kubebuilder:validation:Pattern=`^[A-Za-z][A-Za-z0-9]*([+._-][A-Za-z][A-Za-z0-9]*)*:[0-9A-Fa-f]{32,}$`
+	Digest string `json:"digest,omitempty"`
+	// Resources are the resource requests and limits of the container
+	Resources *ResourceConstraints `json:"resources,omitempty"`
+}
+
+// ExtensionContainer configures an additional container
+// +k8s:openapi-gen=true
+type ExtensionContainer struct {
+	// Name is the key of the container, e.g. appNavInv
+	// +kubebuilder:validation:MinLength=1
+	Name                   string `json:"name"`
+	ContainerConfiguration `json:",inline"`
+	// Target is where the container runs: in the UI pod, in the controller pod or
+	// in a Deployment of its own. Containers without a target are not deployed.
+	Target kappnavv1.ExtensionContainerTarget `json:"target,omitempty"`
+	// Ports are the ports of the container
+	// +listType=map
+	// +listMapKey=name
+	Ports []kappnavv1.KappnavContainerPort `json:"ports,omitempty"`
+	// Service exposes the ports of the container
+	Service *kappnavv1.KappnavContainerService `json:"service,omitempty"`
+	// ReadinessProbe is the readiness probe of the container
+	ReadinessProbe *kappnavv1.KappnavContainerProbe `json:"readinessProbe,omitempty"`
+	// LivenessProbe is the liveness probe of the container
+	LivenessProbe *kappnavv1.KappnavContainerProbe `json:"livenessProbe,omitempty"`
+}
+
+// ResourceConstraints defines resource constraints for a kAppNav container
+// +k8s:openapi-gen=true
+type ResourceConstraints struct {
+	// Enabled applies the requests and limits to the container
+	Enabled bool `json:"enabled,omitempty"`
+	// Requests are the resources requested by the container
+	Requests *Resources `json:"requests,omitempty"`
+	// Limits are the maximum resources used by the container
+	Limits *Resources `json:"limits,omitempty"`
+}
+
+// Resources ...
+// +k8s:openapi-gen=true
+type Resources struct {
+	// CPU is a quantity of CPU such as 500m
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	CPU string `json:"cpu,omitempty"`
+	// Memory is a quantity of memory such as 512Mi
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	Memory string `json:"memory,omitempty"`
+}
+
+// ImageConfiguration ...
+// +k8s:openapi-gen=true
+type ImageConfiguration struct {
+	// PullPolicy is the image pull policy of all containers
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	PullPolicy corev1.PullPolicy `json:"pullPolicy,omitempty"`
+	// PullSecrets are the names of the image pull secrets of all containers
+	PullSecrets []string `json:"pullSecrets,omitempty"`
+	// Registry replaces the registry host of every image repository, e.g. to pull from a mirror
+	Registry string `json:"registry,omitempty"`
+}
+
+// LoggingConfiguration defines the log level of each component
+// +k8s:openapi-gen=true
+type LoggingConfiguration struct {
+	Operator   kappnavv1.LogLevel `json:"operator,omitempty"`
+	API        kappnavv1.LogLevel `json:"api,omitempty"`
+	UI         kappnavv1.LogLevel `json:"ui,omitempty"`
+	Controller kappnavv1.LogLevel `json:"controller,omitempty"`
+	// Propagation is how the log levels reach the containers, ConfigMap by default
+	Propagation kappnavv1.LoggingPropagation `json:"propagation,omitempty"`
+	// Packages maps a component (apis, ui, controller) to the log levels of its
+	// packages, which override the log level of the component
+	Packages map[string]map[string]kappnavv1.LogLevel `json:"packages,omitempty"`
+	// Debug raises the log level of components for a limited time
+	Debug *kappnavv1.KappnavDebugLogging `json:"debug,omitempty"`
+}
+
+// AuthConfiguration defines the authentication in front of the UI. On OpenShift the
+// UI is protected by an oauth-proxy container.
+// +k8s:openapi-gen=true
+type AuthConfiguration struct {
+	// OAuthProxy configures the oauth-proxy container
+	OAuthProxy *ContainerConfiguration `json:"oauthProxy,omitempty"`
+}
+
+// IngressConfiguration defines the host and annotations of the UI Ingress.
+// +k8s:openapi-gen=true
+type IngressConfiguration struct {
+	// Host is the host name of the Ingress rule, any host when empty
+	Host string `json:"host,omitempty"`
+	// Annotations are added to the Ingress, e.g. to select an ingress controller
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// RouteConfiguration defines the host and TLS termination of the UI Route.
+// +k8s:openapi-gen=true
+type RouteConfiguration struct {
+	// Host is the host name of the Route, generated by OpenShift when empty
+	Host string `json:"host,omitempty"`
+	// Termination is the TLS termination of the Route, reencrypt by default
+	Termination kappnavv1.RouteTermination `json:"termination,omitempty"`
+}
+
+// TrustedCAConfiguration defines the source of the CA bundle that is mounted into
+// the kAppNav containers.
+// +k8s:openapi-gen=true
+type TrustedCAConfiguration struct {
+	// ConfigMapName is the name of an existing ConfigMap holding the CA bundle. The
+	// bundle replaces the system trust store of the containers, so it must be a full
+	// bundle holding the public CAs as well as the private ones.
+	ConfigMapName string `json:"configMapName,omitempty"`
+	// Key is the key of the CA bundle in the ConfigMap, ca-bundle.crt by default
+	Key string `json:"key,omitempty"`
+	// InjectOpenShiftBundle requests a ConfigMap that OpenShift fills with the cluster CA bundle
+	InjectOpenShiftBundle bool `json:"injectOpenShiftBundle,omitempty"`
+}
+
+// ProxyConfiguration defines the HTTP proxy settings passed to the kAppNav containers.
+// +k8s:openapi-gen=true
+type ProxyConfiguration struct {
+	// HTTPProxy is the proxy URL for HTTP requests
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// HTTPSProxy is the proxy URL for HTTPS requests
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	// NoProxy is a comma separated list of hosts and domains that bypass the proxy
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Kappnav is the Schema for the kappnavs API. The status is shared with v1.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Reconciled",type="string",JSONPath=".status.conditions[?(@.type=='Reconciled')].status",description="Status of the last reconcile"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url",description="URL of the kAppNav UI"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="Installed kAppNav version"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type Kappnav struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KappnavSpec             `json:"spec,omitempty"`
+	Status kappnavv1.KappnavStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KappnavList contains a list of Kappnav
+type KappnavList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Kappnav `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Kappnav{}, &KappnavList{})
+}
@@ -0,0 +1,634 @@
+// +build !ignore_autogenerated
+
+// This file was autogenerated by openapi-gen. Do not edit it manually!
+
+package v2
+
+import (
+	spec "github.com/go-openapi/spec"
+	common "k8s.io/kube-openapi/pkg/common"
+)
+
+func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+	return map[string]common.OpenAPIDefinition{
+		"./pkg/apis/kappnav/v2.AuthConfiguration":      schema_pkg_apis_kappnav_v2_AuthConfiguration(ref),
+		"./pkg/apis/kappnav/v2.ContainerConfiguration": schema_pkg_apis_kappnav_v2_ContainerConfiguration(ref),
+		"./pkg/apis/kappnav/v2.ExtensionContainer":     schema_pkg_apis_kappnav_v2_ExtensionContainer(ref),
+		"./pkg/apis/kappnav/v2.ImageConfiguration":     schema_pkg_apis_kappnav_v2_ImageConfiguration(ref),
+		"./pkg/apis/kappnav/v2.IngressConfiguration":   schema_pkg_apis_kappnav_v2_IngressConfiguration(ref),
+		"./pkg/apis/kappnav/v2.Kappnav":                schema_pkg_apis_kappnav_v2_Kappnav(ref),
+		"./pkg/apis/kappnav/v2.KappnavSpec":            schema_pkg_apis_kappnav_v2_KappnavSpec(ref),
+		"./pkg/apis/kappnav/v2.LoggingConfiguration":   schema_pkg_apis_kappnav_v2_LoggingConfiguration(ref),
+		"./pkg/apis/kappnav/v2.ProxyConfiguration":     schema_pkg_apis_kappnav_v2_ProxyConfiguration(ref),
+		"./pkg/apis/kappnav/v2.ResourceConstraints":    schema_pkg_apis_kappnav_v2_ResourceConstraints(ref),
+		"./pkg/apis/kappnav/v2.Resources":              schema_pkg_apis_kappnav_v2_Resources(ref),
+		"./pkg/apis/kappnav/v2.RouteConfiguration":     schema_pkg_apis_kappnav_v2_RouteConfiguration(ref),
+		"./pkg/apis/kappnav/v2.TrustedCAConfiguration": schema_pkg_apis_kappnav_v2_TrustedCAConfiguration(ref),
+	}
+}
+
+func schema_pkg_apis_kappnav_v2_AuthConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AuthConfiguration defines the authentication in front of the UI. On OpenShift the UI is protected by an oauth-proxy container.",
+				Properties: map[string]spec.Schema{
+					"oauthProxy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OAuthProxy configures the oauth-proxy container",
+							Ref:         ref("./pkg/apis/kappnav/v2.ContainerConfiguration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/kappnav/v2.ContainerConfiguration"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v2_ContainerConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ContainerConfiguration defines the image and resources of a kAppNav container",
+				Properties: map[string]spec.Schema{
+					"repository": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Repository is the image repository of the container",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tag": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tag is the image tag of the container",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"digest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Digest is the image digest of the container, which takes precedence over the tag",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources are the resource requests and limits of the container",
+							Ref:         ref("./pkg/apis/kappnav/v2.ResourceConstraints"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/kappnav/v2.ResourceConstraints"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v2_ExtensionContainer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ExtensionContainer configures an additional container",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the key of the container, e.g. appNavInv",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"repository": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Repository is the image repository of the container",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tag": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tag is the image tag of the container",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"digest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Digest is the image digest of the container, which takes precedence over the tag",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources are the resource requests and limits of the container",
+							Ref:         ref("./pkg/apis/kappnav/v2.ResourceConstraints"),
+						},
+					},
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Target is where the container runs: in the UI pod, in the controller pod or in a Deployment of its own. Containers without a target are not deployed.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ports": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ports are the ports of the container",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/kappnav/v1.KappnavContainerPort"),
+									},
+								},
+							},
+						},
+					},
+					"service": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Service exposes the ports of the container",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavContainerService"),
+						},
+					},
+					"readinessProbe": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadinessProbe is the readiness probe of the container",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavContainerProbe"),
+						},
+					},
+					"livenessProbe": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LivenessProbe is the liveness probe of the container",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavContainerProbe"),
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/kappnav/v1.KappnavContainerPort", "./pkg/apis/kappnav/v1.KappnavContainerProbe", "./pkg/apis/kappnav/v1.KappnavContainerService", "./pkg/apis/kappnav/v2.ResourceConstraints"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v2_ImageConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImageConfiguration ...",
+				Properties: map[string]spec.Schema{
+					"pullPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PullPolicy is the image pull policy of all containers",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pullSecrets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PullSecrets are the names of the image pull secrets of all containers",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"registry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Registry replaces the registry host of every image repository, e.g. to pull from a mirror",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v2_IngressConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IngressConfiguration defines the host and annotations of the UI Ingress.",
+				Properties: map[string]spec.Schema{
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Host is the host name of the Ingress rule, any host when empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"annotations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Annotations are added to the Ingress, e.g. to select an ingress controller",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v2_Kappnav(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Kappnav is the Schema for the kappnavs API. The status is shared with v1.",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/kappnav/v2.KappnavSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/kappnav/v1.KappnavStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/kappnav/v1.KappnavStatus", "./pkg/apis/kappnav/v2.KappnavSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v2_KappnavSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KappnavSpec defines the desired state of Kappnav",
+				Properties: map[string]spec.Schema{
+					"platform": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Platform is the type of Kubernetes cluster kAppNav is installed in",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"api": {
+						SchemaProps: spec.SchemaProps{
+							Description: "API configures the kAppNav REST API container",
+							Ref:         ref("./pkg/apis/kappnav/v2.ContainerConfiguration"),
+						},
+					},
+					"ui": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UI configures the kAppNav UI container",
+							Ref:         ref("./pkg/apis/kappnav/v2.ContainerConfiguration"),
+						},
+					},
+					"controller": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Controller configures the kAppNav controller container",
+							Ref:         ref("./pkg/apis/kappnav/v2.ContainerConfiguration"),
+						},
+					},
+					"extensionContainers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExtensionContainers configures additional containers by name",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/kappnav/v2.ExtensionContainer"),
+									},
+								},
+							},
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image configures the pull policy, pull secrets and registry of all images",
+							Ref:         ref("./pkg/apis/kappnav/v2.ImageConfiguration"),
+						},
+					},
+					"logging": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Logging configures the log level of each component",
+							Ref:         ref("./pkg/apis/kappnav/v2.LoggingConfiguration"),
+						},
+					},
+					"auth": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Auth configures the authentication in front of the UI",
+							Ref:         ref("./pkg/apis/kappnav/v2.AuthConfiguration"),
+						},
+					},
+					"ingress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ingress configures the UI Ingress created on Kubernetes",
+							Ref:         ref("./pkg/apis/kappnav/v2.IngressConfiguration"),
+						},
+					},
+					"route": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Route configures the UI Route created on OpenShift",
+							Ref:         ref("./pkg/apis/kappnav/v2.RouteConfiguration"),
+						},
+					},
+					"trustedCA": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TrustedCA configures the CA bundle mounted into the kAppNav containers",
+							Ref:         ref("./pkg/apis/kappnav/v2.TrustedCAConfiguration"),
+						},
+					},
+					"proxy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Proxy configures the HTTP proxy settings passed to the kAppNav containers",
+							Ref:         ref("./pkg/apis/kappnav/v2.ProxyConfiguration"),
+						},
+					},
+					"monitoring": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Monitoring configures the ServiceMonitors and PrometheusRule of the kAppNav components",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavMonitoringConfiguration"),
+						},
+					},
+					"isolation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Isolation selects the names of the resources of the CR that are shared by the kAppNav installation of a namespace, Shared by default",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"builtin": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Builtin configures how the console URLs and dashboard names of the builtin config map are kept up to date",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavBuiltinConfiguration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/kappnav/v1.KappnavBuiltinConfiguration", "./pkg/apis/kappnav/v1.KappnavMonitoringConfiguration", "./pkg/apis/kappnav/v2.AuthConfiguration", "./pkg/apis/kappnav/v2.ContainerConfiguration", "./pkg/apis/kappnav/v2.ExtensionContainer", "./pkg/apis/kappnav/v2.ImageConfiguration", "./pkg/apis/kappnav/v2.IngressConfiguration", "./pkg/apis/kappnav/v2.LoggingConfiguration", "./pkg/apis/kappnav/v2.ProxyConfiguration", "./pkg/apis/kappnav/v2.RouteConfiguration", "./pkg/apis/kappnav/v2.TrustedCAConfiguration"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v2_LoggingConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LoggingConfiguration defines the log level of each component",
+				Properties: map[string]spec.Schema{
+					"operator": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"api": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"ui": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"controller": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"propagation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Propagation is how the log levels reach the containers, ConfigMap by default",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"packages": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Packages maps a component (apis, ui, controller) to the log levels of its packages, which override the log level of the component",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type: []string{"object"},
+										AdditionalProperties: &spec.SchemaOrBool{
+											Schema: &spec.Schema{
+												SchemaProps: spec.SchemaProps{
+													Type:   []string{"string"},
+													Format: "",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					"debug": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Debug raises the log level of components for a limited time",
+							Ref:         ref("./pkg/apis/kappnav/v1.KappnavDebugLogging"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/kappnav/v1.KappnavDebugLogging"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v2_ProxyConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ProxyConfiguration defines the HTTP proxy settings passed to the kAppNav containers.",
+				Properties: map[string]spec.Schema{
+					"httpProxy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HTTPProxy is the proxy URL for HTTP requests",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"httpsProxy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HTTPSProxy is the proxy URL for HTTPS requests",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"noProxy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NoProxy is a comma separated list of hosts and domains that bypass the proxy",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v2_ResourceConstraints(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ResourceConstraints defines resource constraints for a kAppNav container",
+				Properties: map[string]spec.Schema{
+					"enabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled applies the requests and limits to the container",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"requests": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Requests are the resources requested by the container",
+							Ref:         ref("./pkg/apis/kappnav/v2.Resources"),
+						},
+					},
+					"limits": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Limits are the maximum resources used by the container",
+							Ref:         ref("./pkg/apis/kappnav/v2.Resources"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/kappnav/v2.Resources"},
+	}
+}
+
+func schema_pkg_apis_kappnav_v2_Resources(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Resources ...",
+				Properties: map[string]spec.Schema{
+					"cpu": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CPU is a quantity of CPU such as 500m",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"memory": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Memory is a quantity of memory such as 512Mi",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v2_RouteConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RouteConfiguration defines the host and TLS termination of the UI Route.",
+				Properties: map[string]spec.Schema{
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Host is the host name of the Route, generated by OpenShift when empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"termination": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Termination is the TLS termination of the Route, reencrypt by default",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_kappnav_v2_TrustedCAConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TrustedCAConfiguration defines the source of the CA bundle that is mounted into the kAppNav containers.",
+				Properties: map[string]spec.Schema{
+					"configMapName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigMapName is the name of an existing ConfigMap holding the CA bundle. The bundle replaces the system trust store of the containers, so it must be a full bundle holding the public CAs as well as the private ones.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"key": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Key is the key of the CA bundle in the ConfigMap, ca-bundle.crt by default",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"injectOpenShiftBundle": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InjectOpenShiftBundle requests a ConfigMap that OpenShift fills with the cluster CA bundle",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
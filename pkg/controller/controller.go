@@ -14,6 +14,7 @@ limitations under the License.
 package controller
 
 import (
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	appv1beta1 "github.com/kubernetes-sigs/application/pkg/apis/app/v1beta1"
 	routev1 "github.com/openshift/api/route/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
@@ -38,6 +39,10 @@ func AddToManager(m manager.Manager) error {
 		return err
 	}
 
+	if err := monitoringv1.AddToScheme(m.GetScheme()); err != nil {
+		return err
+	}
+
 	for _, f := range AddToManagerFuncs {
 		if err := f(m); err != nil {
 			return err
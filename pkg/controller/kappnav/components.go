@@ -0,0 +1,480 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kappnav
+
+import (
+	"fmt"
+	"time"
+
+	kamv1 "github.com/kappnav/operator/pkg/apis/actions/v1"
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	appv1beta1 "github.com/kubernetes-sigs/application/pkg/apis/app/v1beta1"
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// builtinComponents returns the components of kAppNav, followed by the components
+// registered by extensions
+func (r *ReconcileKappnav) builtinComponents() []kappnavutils.ComponentReconciler {
+	return []kappnavutils.ComponentReconciler{
+		kappnavutils.NewComponent(kappnavutils.ComponentRBAC, nil, r.reconcileRBAC),
+		kappnavutils.NewComponent(kappnavutils.ComponentNetworking, nil, r.reconcileNetworking),
+		// kappnav-config holds the URL of the UI
+		kappnavutils.NewComponent(kappnavutils.ComponentMaps, []string{kappnavutils.ComponentNetworking}, r.reconcileMaps),
+		kappnavutils.NewComponent(kappnavutils.ComponentKAM, nil, r.reconcileKAM),
+		// The pods run as the service account, serve with the certificate of the
+		// UI service and mount the config maps
+		kappnavutils.NewComponent(kappnavutils.ComponentUI, []string{kappnavutils.ComponentRBAC,
+			kappnavutils.ComponentNetworking, kappnavutils.ComponentMaps}, r.reconcileUI),
+		kappnavutils.NewComponent(kappnavutils.ComponentController, []string{kappnavutils.ComponentRBAC,
+			kappnavutils.ComponentMaps}, r.reconcileController),
+		kappnavutils.NewComponent(kappnavutils.ComponentMonitoring, nil, r.reconcileMonitoringComponent),
+		// The extension containers run as the service account, like the UI and
+		// controller pods
+		kappnavutils.NewComponent(kappnavutils.ComponentExtensionContainers, []string{kappnavutils.ComponentRBAC,
+			kappnavutils.ComponentMaps}, r.reconcileExtensionContainers),
+	}
+}
+
+// getUIServiceAndRouteName returns the name of the UI service and route
+func getUIServiceAndRouteName(instance *kappnavv1.Kappnav) *metav1.ObjectMeta {
+	return &metav1.ObjectMeta{
+		Name:      instance.GetName() + "-ui-service",
+		Namespace: instance.GetNamespace(),
+	}
+}
+
+// reconcileRBAC creates or updates the service account and cluster role binding
+func (r *ReconcileKappnav) reconcileRBAC(logger kappnavutils.Logger, ctx *kappnavutils.ComponentContext) (reconcile.Result, error) {
+	instance := ctx.Instance
+	var otherLogData = " in Request.Namespace: " + instance.GetNamespace() + ", Request.Name: " + instance.GetName()
+
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.GetName() + "-" + kappnavutils.ServiceAccountNameSuffix,
+			Namespace: instance.GetNamespace(),
+		},
+	}
+	// Create or update service account
+	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update service account"+otherLogData, logName)
+	}
+	stepStart := time.Now()
+	err := r.CreateOrUpdate(logger, serviceAccount, instance, func() error {
+		kappnavutils.CustomizeServiceAccount(logger, serviceAccount, getUIServiceAndRouteName(instance), instance)
+		return nil
+	})
+	kappnavutils.ObserveReconcileStep("serviceaccount", stepStart, err)
+	if err != nil {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the ServiceAccount"+otherLogData+", Error: %s ", err), logName)
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Create or update cluster role binding
+	crb := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ctx.Names.ClusterRoleBinding,
+			Namespace: instance.GetNamespace(),
+		},
+	}
+	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update cluster role binding"+otherLogData, logName)
+	}
+	stepStart = time.Now()
+	err = r.CreateOrUpdate(logger, crb, instance, func() error {
+		kappnavutils.CustomizeClusterRoleBinding(crb, serviceAccount, instance)
+		return nil
+	})
+	kappnavutils.ObserveReconcileStep("clusterrolebinding", stepStart, err)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the ClusterRoleBinding"+otherLogData+", Error: %s ", err), logName)
+		}
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// reconcileNetworking creates or updates the UI service and its ingress on
+// Minikube or its route elsewhere, and computes the URL of the UI from the route
+func (r *ReconcileKappnav) reconcileNetworking(logger kappnavutils.Logger, ctx *kappnavutils.ComponentContext) (reconcile.Result, error) {
+	instance := ctx.Instance
+	var otherLogData = " in Request.Namespace: " + instance.GetNamespace() + ", Request.Name: " + instance.GetName()
+	uiServiceAndRouteName := getUIServiceAndRouteName(instance)
+
+	// Dummy secret for Minikube support
+	dummySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.GetName() + "-" + kappnavutils.OAuthVolumeName,
+			Namespace: instance.GetNamespace(),
+		},
+	}
+
+	// The UI service
+	uiService := &corev1.Service{
+		ObjectMeta: *uiServiceAndRouteName,
+	}
+	uiServiceAnnotations := map[string]string{
+		"service.alpha.openshift.io/serving-cert-secret-name": dummySecret.Name,
+	}
+
+	var err error
+	isMinikube := kappnavutils.IsMinikubeEnv(instance.Spec.Env.KubeEnv)
+	if isMinikube {
+		// Create or update dummy secret
+		stepStart := time.Now()
+		err = r.CreateOrUpdate(logger, dummySecret, instance, func() error {
+			kappnavutils.CustomizeSecret(dummySecret, instance)
+			return nil
+		})
+		kappnavutils.ObserveReconcileStep("ui-secret", stepStart, err)
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update dummy secret"+otherLogData, logName)
+		}
+		if err != nil {
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the dummy secret"+otherLogData+", Error: %s ", err), logName)
+			}
+			return reconcile.Result{}, err
+		}
+		// Create or update the UI service
+		stepStart = time.Now()
+		err = r.CreateOrUpdate(logger, uiService, instance, func() error {
+			kappnavutils.CustomizeService(uiService, instance, uiServiceAnnotations)
+			kappnavutils.CustomizeUIServiceSpec(&uiService.Spec, instance)
+			return nil
+		})
+		kappnavutils.ObserveReconcileStep("ui-service", stepStart, err)
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update UI service"+otherLogData, logName)
+		}
+		if err != nil {
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the UI service"+otherLogData+", Error: %s ", err), logName)
+			}
+			return reconcile.Result{}, err
+		}
+		// Create or update UI ingress
+		uiIngress := &extensionsv1beta1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      instance.GetName() + "-ui-ingress",
+				Namespace: instance.GetNamespace(),
+			},
+		}
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update UI ingress"+otherLogData, logName)
+		}
+		stepStart = time.Now()
+		err = r.CreateOrUpdate(logger, uiIngress, instance, func() error {
+			kappnavutils.CustomizeIngress(uiIngress, instance)
+			kappnavutils.CustomizeUIIngressSpec(&uiIngress.Spec, uiService, instance)
+			return nil
+		})
+		kappnavutils.ObserveReconcileStep("ui-ingress", stepStart, err)
+		if err != nil {
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the UI ingress"+otherLogData+", Error: %s ", err), logName)
+			}
+			return reconcile.Result{}, err
+		}
+	} else {
+		// Create or update the UI service
+		stepStart := time.Now()
+		err = r.CreateOrUpdate(logger, uiService, instance, func() error {
+			kappnavutils.CustomizeService(uiService, instance, uiServiceAnnotations)
+			kappnavutils.CustomizeUIServiceSpec(&uiService.Spec, instance)
+			return nil
+		})
+		kappnavutils.ObserveReconcileStep("ui-service", stepStart, err)
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update UI service"+otherLogData, logName)
+		}
+		if err != nil {
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the UI Service"+otherLogData+", Error: %s ", err), logName)
+			}
+			return reconcile.Result{}, err
+		}
+		// Create or update UI route
+		uiRoute := &routev1.Route{
+			ObjectMeta: *uiServiceAndRouteName,
+		}
+		stepStart = time.Now()
+		err = r.CreateOrUpdate(logger, uiRoute, instance, func() error {
+			kappnavutils.CustomizeRoute(uiRoute, instance)
+			kappnavutils.CustomizeUIRouteSpec(&uiRoute.Spec, uiServiceAndRouteName, instance)
+			return nil
+		})
+		kappnavutils.ObserveReconcileStep("ui-route", stepStart, err)
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update UI route"+otherLogData, logName)
+		}
+		if err != nil {
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the UI route"+otherLogData+", Error: %s ", err), logName)
+			}
+			return reconcile.Result{}, err
+		}
+		// Compute Kappnav URL from route.
+		routeHost := uiRoute.Spec.Host
+		routePath := uiRoute.Spec.Path
+		if len(routeHost) > 0 && len(routePath) > 0 {
+			ctx.KappnavURL = "https://" + routeHost + routePath
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// reconcileMaps creates or updates the kappnav Application, the action, section
+// and status config maps, the builtin and kappnav-config config maps, and the
+// trusted CA and logging config maps
+func (r *ReconcileKappnav) reconcileMaps(logger kappnavutils.Logger, ctx *kappnavutils.ComponentContext) (reconcile.Result, error) {
+	instance := ctx.Instance
+	var otherLogData = " in Request.Namespace: " + instance.GetNamespace() + ", Request.Name: " + instance.GetName()
+
+	// The kappnav application
+	kappnavCR := &appv1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ctx.Names.Application,
+			Namespace: instance.GetNamespace(),
+		},
+	}
+	kappnavCRAnnotations := map[string]string{
+		"kappnav.application.hidden": "true",
+	}
+
+	// Create or update the kappnav Application
+	stepStart := time.Now()
+	err := r.CreateOrUpdate(logger, kappnavCR, instance, func() error {
+		kappnavutils.CustomizeApplication(kappnavCR, instance, kappnavCRAnnotations)
+		return nil
+	})
+	kappnavutils.ObserveReconcileStep("application", stepStart, err)
+	if err != nil {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the kappnav Application"+otherLogData+", Error: %s ", err), logName)
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Create or update action, section and status config maps.
+	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update action, section and status config maps"+otherLogData, logName)
+	}
+	stepStart = time.Now()
+	err = r.reconcileConfigMaps(logger, instance, false)
+	kappnavutils.ObserveReconcileStep("config-maps", stepStart, err)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Check the action, section and status config maps, including user maps.
+	// The errors found are recorded in the status.
+	r.lintConfigMaps(logger, instance)
+
+	// Create or update builtin config
+	builtinConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ctx.Names.BuiltinConfigMap,
+			Namespace: instance.GetNamespace(),
+		},
+	}
+	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update builtin config"+otherLogData, logName)
+	}
+	stepStart = time.Now()
+	err = r.CreateOrUpdate(logger, builtinConfig, instance, func() error {
+		kappnavutils.CustomizeConfigMap(builtinConfig, instance, "builtin")
+		kappnavutils.CustomizeBuiltinConfigMap(logger, builtinConfig, &r.ReconcilerBase, instance)
+		return nil
+	})
+	kappnavutils.ObserveReconcileStep("builtin-config", stepStart, err)
+	if err != nil {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the kappnav-config ConfigMap"+otherLogData+", Error: %s ", err), logName)
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Create or update kappnav-config
+	kappnavConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ctx.Names.KappnavConfigMap,
+			Namespace: instance.GetNamespace(),
+		},
+	}
+	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update kappnav-config"+otherLogData, logName)
+	}
+	stepStart = time.Now()
+	err = r.CreateOrUpdate(logger, kappnavConfig, instance, func() error {
+		kappnavutils.CustomizeConfigMap(kappnavConfig, instance, "builtin")
+		kappnavutils.CustomizeKappnavConfigMap(kappnavConfig, ctx.KappnavURL, instance)
+		return nil
+	})
+	kappnavutils.ObserveReconcileStep("kappnav-config", stepStart, err)
+	if err != nil {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the kappnav-config ConfigMap"+otherLogData+", Error: %s", err), logName)
+		}
+		return reconcile.Result{}, err
+	}
+	instance.Status.URL = kappnavConfig.Data["kappnav-url"]
+
+	// Create or update the trusted CA bundle config map if injection was requested
+	if kappnavutils.IsTrustedCAInjectionRequested(instance) {
+		trustedCAConfig := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kappnavutils.GetTrustedCAConfigMapName(instance),
+				Namespace: instance.GetNamespace(),
+			},
+		}
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update trusted CA config map"+otherLogData, logName)
+		}
+		stepStart = time.Now()
+		err = r.CreateOrUpdate(logger, trustedCAConfig, instance, func() error {
+			kappnavutils.CustomizeTrustedCAConfigMap(trustedCAConfig, instance)
+			return nil
+		})
+		kappnavutils.ObserveReconcileStep("trustedca-config", stepStart, err)
+		if err != nil {
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the trusted CA ConfigMap"+otherLogData+", Error: %s", err), logName)
+			}
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Create or update the config map holding the log levels of the components
+	loggingConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kappnavutils.GetLoggingConfigMapName(instance),
+			Namespace: instance.GetNamespace(),
+		},
+	}
+	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update logging config map"+otherLogData, logName)
+	}
+	stepStart = time.Now()
+	err = r.CreateOrUpdate(logger, loggingConfig, instance, func() error {
+		kappnavutils.CustomizeLoggingConfigMap(loggingConfig, instance)
+		return nil
+	})
+	kappnavutils.ObserveReconcileStep("logging-config", stepStart, err)
+	if err != nil {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the logging ConfigMap"+otherLogData+", Error: %s", err), logName)
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Delete the resources of the isolation mode the CR does not use
+	return reconcile.Result{}, r.deleteUnusedInstanceResources(instance)
+}
+
+// reconcileKAM creates or updates the default KindActionMapping
+func (r *ReconcileKappnav) reconcileKAM(logger kappnavutils.Logger, ctx *kappnavutils.ComponentContext) (reconcile.Result, error) {
+	instance := ctx.Instance
+	var otherLogData = " in Request.Namespace: " + instance.GetNamespace() + ", Request.Name: " + instance.GetName()
+
+	// Apply defaults to the KindActionMapping (kam) instance
+	default_kam := &kamv1.KindActionMapping{}
+	err := kappnavutils.SetKAMDefaults(default_kam)
+
+	// Set ObjectMeta of KindActionMapping
+	kamCR := &kamv1.KindActionMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ctx.Names.KAM,
+			Namespace: instance.GetNamespace(),
+		},
+	}
+
+	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update KindActionMapping"+otherLogData, logName)
+	}
+
+	// Create or update the KindActionMapping (kam)
+	stepStart := time.Now()
+	err = r.CreateOrUpdate(logger, kamCR, instance, func() error {
+		kappnavutils.CustomizeKAM(kamCR, default_kam, instance)
+		return nil
+	})
+	kappnavutils.ObserveReconcileStep("kam", stepStart, err)
+
+	if err != nil {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the KindActionMapping"+otherLogData+", Error: %s", err), logName)
+		}
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, r.deleteUnusedInstanceKAM(instance)
+}
+
+// reconcileUI creates or updates the UI deployment
+func (r *ReconcileKappnav) reconcileUI(logger kappnavutils.Logger, ctx *kappnavutils.ComponentContext) (reconcile.Result, error) {
+	instance := ctx.Instance
+	var otherLogData = " in Request.Namespace: " + instance.GetNamespace() + ", Request.Name: " + instance.GetName()
+	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update UI deployment"+otherLogData, logName)
+	}
+	stepStart := time.Now()
+	uiDeployment, err := r.reconcileUIDeployment(logger, instance)
+	kappnavutils.ObserveReconcileStep("ui-deployment", stepStart, err)
+	if err != nil {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the UI Deployment"+otherLogData+", Error: %s ", err), logName)
+		}
+		return reconcile.Result{}, err
+	}
+	ctx.Deployments = append(ctx.Deployments, uiDeployment)
+	return reconcile.Result{}, nil
+}
+
+// reconcileController creates or updates the controller deployment
+func (r *ReconcileKappnav) reconcileController(logger kappnavutils.Logger, ctx *kappnavutils.ComponentContext) (reconcile.Result, error) {
+	instance := ctx.Instance
+	var otherLogData = " in Request.Namespace: " + instance.GetNamespace() + ", Request.Name: " + instance.GetName()
+	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update controller deployment"+otherLogData, logName)
+	}
+	stepStart := time.Now()
+	controllerDeployment, err := r.reconcileControllerDeployment(logger, instance)
+	kappnavutils.ObserveReconcileStep("controller-deployment", stepStart, err)
+	if err != nil {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the Controller Deployment"+otherLogData+", Error: %s", err), logName)
+		}
+		return reconcile.Result{}, err
+	}
+	ctx.Deployments = append(ctx.Deployments, controllerDeployment)
+	return reconcile.Result{}, nil
+}
+
+// reconcileMonitoringComponent creates or updates the ServiceMonitors and
+// PrometheusRule if monitoring is enabled
+func (r *ReconcileKappnav) reconcileMonitoringComponent(logger kappnavutils.Logger, ctx *kappnavutils.ComponentContext) (reconcile.Result, error) {
+	stepStart := time.Now()
+	err := r.reconcileMonitoring(logger, ctx.Instance)
+	kappnavutils.ObserveReconcileStep("monitoring", stepStart, err)
+	return reconcile.Result{}, err
+}
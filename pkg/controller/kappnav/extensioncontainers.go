@@ -0,0 +1,156 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kappnav
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcileExtensionContainers creates or updates the Deployments of the
+// extension containers targeted at their own Deployment and the Services of the
+// extension containers exposing their ports, and deletes the Deployments and
+// Services of the extension containers removed from the CR. The extension
+// containers targeted at the UI and controller pods are added by the UI and
+// controller components.
+func (r *ReconcileKappnav) reconcileExtensionContainers(logger kappnavutils.Logger, ctx *kappnavutils.ComponentContext) (reconcile.Result, error) {
+	instance := ctx.Instance
+	var otherLogData = " in Request.Namespace: " + instance.GetNamespace() + ", Request.Name: " + instance.GetName()
+
+	wanted := make(map[string]bool)
+	for _, key := range kappnavutils.GetExtensionContainerKeys(instance, kappnavv1.ExtensionContainerTargetDeployment) {
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update "+key+" extension container deployment"+otherLogData, logName)
+		}
+		stepStart := time.Now()
+		deployment, err := r.reconcileExtensionContainerDeployment(logger, instance, key)
+		kappnavutils.ObserveReconcileStep("extension-deployment", stepStart, err)
+		if err != nil {
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the %s extension container Deployment"+otherLogData+", Error: %s", key, err), logName)
+			}
+			return reconcile.Result{}, err
+		}
+		wanted["Deployment/"+deployment.GetName()] = true
+		ctx.Deployments = append(ctx.Deployments, deployment)
+	}
+
+	var keys []string
+	for key := range instance.Spec.ExtensionContainers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == kappnavutils.OAuthProxyContainerConfigKey || !kappnavutils.IsExtensionContainerServiceEnabled(instance.Spec.ExtensionContainers[key]) {
+			continue
+		}
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kappnavutils.GetExtensionContainerResourceName(instance, key),
+				Namespace: instance.GetNamespace(),
+			},
+		}
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update "+key+" extension container service"+otherLogData, logName)
+		}
+		stepStart := time.Now()
+		err := r.CreateOrUpdate(logger, service, instance, func() error {
+			kappnavutils.CustomizeExtensionContainerService(service, instance, key)
+			return nil
+		})
+		kappnavutils.ObserveReconcileStep("extension-service", stepStart, err)
+		if err != nil {
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the %s extension container Service"+otherLogData+", Error: %s", key, err), logName)
+			}
+			return reconcile.Result{}, err
+		}
+		wanted["Service/"+service.GetName()] = true
+	}
+
+	unused, err := r.getUnusedExtensionContainerResources(instance, wanted)
+	if err != nil {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to list the extension container resources"+otherLogData+", Error: %s", err), logName)
+		}
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, r.DeleteResources(unused)
+}
+
+// reconcileExtensionContainerDeployment creates or updates the Deployment of an
+// extension container
+func (r *ReconcileKappnav) reconcileExtensionContainerDeployment(logger kappnavutils.Logger, instance *kappnavv1.Kappnav, key string) (*appsv1.Deployment, error) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kappnavutils.GetExtensionContainerResourceName(instance, key),
+			Namespace: instance.GetNamespace(),
+		},
+	}
+	err := r.CreateOrUpdate(logger, deployment, instance, func() error {
+		pts := &deployment.Spec.Template
+		kappnavutils.CustomizeExtensionContainerDeployment(deployment, instance, key)
+		kappnavutils.CustomizePodSpec(pts, &deployment.ObjectMeta,
+			kappnavutils.CreateExtensionContainerDeploymentContainers(pts.Spec.Containers, instance, key),
+			kappnavutils.CreateExtensionContainerVolumes(instance), instance)
+		return nil
+	})
+	return deployment, err
+}
+
+// getUnusedExtensionContainerResources returns the Deployments and Services of
+// the extension containers of the CR that are not wanted anymore
+func (r *ReconcileKappnav) getUnusedExtensionContainerResources(instance *kappnavv1.Kappnav, wanted map[string]bool) ([]runtime.Object, error) {
+	selector, err := kappnavutils.GetExtensionContainerSelector(instance)
+	if err != nil {
+		return nil, err
+	}
+	options := &client.ListOptions{
+		Namespace:     instance.GetNamespace(),
+		LabelSelector: selector,
+	}
+	var unused []runtime.Object
+	deployments := &appsv1.DeploymentList{}
+	if err = r.GetClient().List(context.TODO(), options, deployments); err != nil {
+		return nil, err
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if metav1.IsControlledBy(deployment, instance) && !wanted["Deployment/"+deployment.GetName()] {
+			unused = append(unused, deployment)
+		}
+	}
+	services := &corev1.ServiceList{}
+	if err = r.GetClient().List(context.TODO(), options, services); err != nil {
+		return nil, err
+	}
+	for i := range services.Items {
+		service := &services.Items[i]
+		if metav1.IsControlledBy(service, instance) && !wanted["Service/"+service.GetName()] {
+			unused = append(unused, service)
+		}
+	}
+	return unused, nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kappnav
+
+import (
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcileDeletion runs the deletion hooks of the extensions when the CR is being
+// deleted, and otherwise adds the finalizer that holds the deletion of the CR
+// until the hooks have run. Returns true if the reconcile is done.
+func (r *ReconcileKappnav) reconcileDeletion(logger kappnavutils.Logger, instance *kappnavv1.Kappnav) (bool, reconcile.Result, error) {
+	var otherLogData = " in Request.Namespace: " + instance.GetNamespace() + ", Request.Name: " + instance.GetName()
+	finalizers := instance.GetFinalizers()
+	hasFinalizer := kappnavutils.ContainString(finalizers, kappnavutils.ExtensionsFinalizer)
+
+	if instance.GetDeletionTimestamp() != nil {
+		if !hasFinalizer {
+			return true, reconcile.Result{}, nil
+		}
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Run the deletion hooks of the extensions"+otherLogData, logName)
+		}
+		if err := kappnavutils.RunDeletionExtensions(logger, &r.ReconcilerBase, instance); err != nil {
+			result, err := r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
+			return true, result, err
+		}
+		instance.SetFinalizers(kappnavutils.RemoveString(finalizers, kappnavutils.ExtensionsFinalizer))
+		return true, reconcile.Result{}, r.GetClient().Update(logger.Context(), instance)
+	}
+
+	if kappnavutils.HasDeletionExtensions() && !hasFinalizer {
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Add the extensions finalizer"+otherLogData, logName)
+		}
+		instance.SetFinalizers(append(finalizers, kappnavutils.ExtensionsFinalizer))
+		if err := r.GetClient().Update(logger.Context(), instance); err != nil {
+			return true, reconcile.Result{}, err
+		}
+	}
+	return false, reconcile.Result{}, nil
+}
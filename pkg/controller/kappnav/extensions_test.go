@@ -0,0 +1,160 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kappnav
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// deletionExtension is an extension with a deletion hook that fails while err is
+// set
+type deletionExtension struct {
+	deleted int
+	err     error
+}
+
+func (e *deletionExtension) ReconcileAdditionalResources(logger kappnavutils.Logger, request reconcile.Request,
+	r *kappnavutils.ReconcilerBase, instance *kappnavv1.Kappnav) (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+func (e *deletionExtension) Delete(logger kappnavutils.Logger, r *kappnavutils.ReconcilerBase, instance *kappnavv1.Kappnav) error {
+	e.deleted++
+	return e.err
+}
+
+func init() {
+	if err := kappnavv1.SchemeBuilder.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
+}
+
+// registerDeletionExtension registers an extension with a deletion hook for the
+// duration of a test
+func registerDeletionExtension(t *testing.T, err error) *deletionExtension {
+	extension := &deletionExtension{err: err}
+	kappnavutils.RegisterKappnavExtension("DeletionTest", extension)
+	t.Cleanup(func() {
+		kappnavutils.UnregisterKappnavExtension("DeletionTest")
+	})
+	return extension
+}
+
+func newDeletionTestReconciler(instance *kappnavv1.Kappnav) *ReconcileKappnav {
+	c := fake.NewFakeClient(instance)
+	return &ReconcileKappnav{ReconcilerBase: kappnavutils.NewReconcilerBase(c, scheme.Scheme, nil, record.NewFakeRecorder(10))}
+}
+
+func getStoredInstance(t *testing.T, r *ReconcileKappnav) *kappnavv1.Kappnav {
+	stored := &kappnavv1.Kappnav{}
+	if err := r.GetClient().Get(context.Background(), client.ObjectKey{Namespace: "kappnav", Name: "instance"}, stored); err != nil {
+		t.Fatalf("failed to get the CR: %s", err)
+	}
+	return stored
+}
+
+func TestReconcileDeletion(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	tests := []struct {
+		name          string
+		deleting      bool
+		finalizers    []string
+		hookErr       error
+		wantDone      bool
+		wantDeleted   int
+		wantFinalizer bool
+	}{
+		{
+			name:          "finalizer added",
+			wantFinalizer: true,
+		},
+		{
+			name:          "finalizer kept",
+			finalizers:    []string{kappnavutils.ExtensionsFinalizer},
+			wantFinalizer: true,
+		},
+		{
+			name:          "hooks run and finalizer removed",
+			deleting:      true,
+			finalizers:    []string{"other", kappnavutils.ExtensionsFinalizer},
+			wantDone:      true,
+			wantDeleted:   1,
+			wantFinalizer: false,
+		},
+		{
+			name:          "failed hook keeps the finalizer",
+			deleting:      true,
+			finalizers:    []string{kappnavutils.ExtensionsFinalizer},
+			hookErr:       errors.New("cluster role is still bound"),
+			wantDone:      true,
+			wantDeleted:   1,
+			wantFinalizer: true,
+		},
+		{
+			name:       "hooks already run",
+			deleting:   true,
+			finalizers: []string{"other"},
+			wantDone:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			extension := registerDeletionExtension(t, test.hookErr)
+			instance := &kappnavv1.Kappnav{
+				ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "kappnav", Finalizers: test.finalizers},
+			}
+			if test.deleting {
+				instance.SetDeletionTimestamp(&deletionTimestamp)
+			}
+			r := newDeletionTestReconciler(instance)
+
+			done, _, err := r.reconcileDeletion(kappnavutils.NewLogger(), instance)
+			if err != nil {
+				t.Fatalf("reconcileDeletion failed: %s", err)
+			}
+			if done != test.wantDone {
+				t.Errorf("got done %t, want %t", done, test.wantDone)
+			}
+			if extension.deleted != test.wantDeleted {
+				t.Errorf("the deletion hook ran %d times, want %d", extension.deleted, test.wantDeleted)
+			}
+
+			stored := getStoredInstance(t, r)
+			if hasFinalizer := kappnavutils.ContainString(stored.GetFinalizers(), kappnavutils.ExtensionsFinalizer); hasFinalizer != test.wantFinalizer {
+				t.Errorf("got finalizers %v, want the extensions finalizer %t", stored.GetFinalizers(), test.wantFinalizer)
+			}
+			if kappnavutils.ContainString(test.finalizers, "other") && !kappnavutils.ContainString(stored.GetFinalizers(), "other") {
+				t.Errorf("got finalizers %v, want the other finalizer kept", stored.GetFinalizers())
+			}
+			if test.hookErr != nil {
+				condition := kappnavutils.GetCondition(kappnavv1.StatusConditionTypeReconciled, &stored.Status)
+				if condition == nil || condition.Status != corev1.ConditionFalse {
+					t.Errorf("got Reconciled condition %+v, want False with the error of the hook", condition)
+				}
+			}
+		})
+	}
+}
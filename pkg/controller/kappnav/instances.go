@@ -0,0 +1,79 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kappnav
+
+import (
+	"context"
+	"fmt"
+
+	kamv1 "github.com/kappnav/operator/pkg/apis/actions/v1"
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	appv1beta1 "github.com/kubernetes-sigs/application/pkg/apis/app/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// deleteUnusedInstanceResources deletes the kappnav Application and the builtin
+// and kappnav-config config maps the CR created in the isolation mode it does
+// not use anymore
+func (r *ReconcileKappnav) deleteUnusedInstanceResources(instance *kappnavv1.Kappnav) error {
+	unusedNames := kappnavutils.GetUnusedInstanceNames(instance)
+	unused := []runtime.Object{
+		&appv1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: unusedNames.Application, Namespace: instance.GetNamespace()}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: unusedNames.BuiltinConfigMap, Namespace: instance.GetNamespace()}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: unusedNames.KappnavConfigMap, Namespace: instance.GetNamespace()}},
+	}
+	for i := range unused {
+		if err := r.deleteIfControlled(unused[i], instance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteUnusedInstanceKAM deletes the default KindActionMapping the CR created
+// in the isolation mode it does not use anymore
+func (r *ReconcileKappnav) deleteUnusedInstanceKAM(instance *kappnavv1.Kappnav) error {
+	kam := &kamv1.KindActionMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kappnavutils.GetUnusedInstanceNames(instance).KAM,
+			Namespace: instance.GetNamespace(),
+		},
+	}
+	return r.deleteIfControlled(kam, instance)
+}
+
+// deleteIfControlled deletes a resource if it exists and is controlled by the CR.
+// The resources of other CRs and of users are left alone.
+func (r *ReconcileKappnav) deleteIfControlled(obj runtime.Object, instance *kappnavv1.Kappnav) error {
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		return fmt.Errorf("%T is not a metav1.Object", obj)
+	}
+	err := r.GetClient().Get(context.TODO(), client.ObjectKey{Namespace: metaObj.GetNamespace(), Name: metaObj.GetName()}, obj)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(metaObj, instance) {
+		return nil
+	}
+	return r.DeleteResource(obj)
+}
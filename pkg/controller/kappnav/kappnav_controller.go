@@ -21,20 +21,26 @@ import (
 	"os"
 	"strings"
 	"text/template"
+	"time"
 
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	kamv1 "github.com/kappnav/operator/pkg/apis/actions/v1"
 	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
 	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	"github.com/kappnav/operator/version"
 	appv1beta1 "github.com/kubernetes-sigs/application/pkg/apis/app/v1beta1"
 	routev1 "github.com/openshift/api/route/v1"
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
-	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -53,69 +59,71 @@ var logName = "controller_kappnav"
 // Add creates a new Kappnav Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	logger := kappnavutils.NewLogger(true) //log in json format
+	logger := kappnavutils.NewLogger()
 
 	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
 		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Creating a new kappnav controller and adds it to the manager", logName)
 	}
-	return add(logger, mgr, newReconciler(logger, mgr))
+	reconciler, err := newReconciler(logger, mgr)
+	if err != nil {
+		return err
+	}
+
+	// Create or upgrade the CRDs shipped in the image once the manager is started.
+	if err := mgr.Add(reconciler.crdBootstrap); err != nil {
+		return err
+	}
+	// The controller is only added by the leader, so only the readiness of the
+	// leader waits for the CRDs. A pod waiting for the leader lock stays ready, so
+	// that a rolling update can replace the leader.
+	kappnavutils.AddReadinessCheck(kappnavutils.CRDReadinessCheckName, reconciler.crdBootstrap.Check)
+
+	return add(logger, mgr, reconciler)
 }
 
-// newReconciler returns a new reconcile.Reconciler
-func newReconciler(logger kappnavutils.Logger, mgr manager.Manager) reconcile.Reconciler {
+// newReconciler returns a new reconcile.Reconciler. Returns an error if the
+// dependencies of the registered components cannot be satisfied.
+func newReconciler(logger kappnavutils.Logger, mgr manager.Manager) (*ReconcileKappnav, error) {
 	reconciler := &ReconcileKappnav{ReconcilerBase: kappnavutils.NewReconcilerBase(mgr.GetClient(),
 		mgr.GetScheme(), mgr.GetConfig(), mgr.GetRecorder("kappnav-operator"))}
 
-	// Create CRDs if they do not already exist.
-	//test
-	files, err := ioutil.ReadDir("crds")
+	// The conversion webhooks are served by the operator, in the namespace of the operator.
+	namespace, _ := k8sutil.GetOperatorNamespace()
+	reconciler.crdManager = kappnavutils.NewCRDManager(&reconciler.ReconcilerBase, "crds", version.Version, namespace)
+	reconciler.crdBootstrap = kappnavutils.NewCRDBootstrap(logger, reconciler.crdManager,
+		reconciler.GetRecorder(), getOperatorPodReference(logger))
+
+	components, err := kappnavutils.OrderComponents(append(reconciler.builtinComponents(),
+		kappnavutils.GetRegisteredComponents()...))
 	if err != nil {
-		if logger.IsEnabled(kappnavutils.LogTypeError) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to read directory: crds, error: %s", err), logName)
-		}
-		os.Exit(1)
-	}
-	for _, file := range files {
-		if !file.IsDir() {
-			fileName := "crds/" + file.Name()
-			if strings.HasSuffix(fileName, ".yaml") || strings.HasSuffix(fileName, ".yml") {
-				if logger.IsEnabled(kappnavutils.LogTypeDebug) {
-					logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeDebug, fmt.Sprintf("Read file from the image: ", fileName), logName)
-				}
-				// Read the file from the image.
-				fData, err := ioutil.ReadFile(fileName)
-				if err != nil {
-					if logger.IsEnabled(kappnavutils.LogTypeError) {
-						logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to read file: %s, error: %s", fileName, err), logName)
-					}
-					os.Exit(1)
-				}
-				crd := &apiextensionsv1beta1.CustomResourceDefinition{}
-				// Unmarshal the YAML into an object.
-				err = yaml.Unmarshal(fData, crd)
-				if err != nil {
-					if logger.IsEnabled(kappnavutils.LogTypeError) {
-						logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to unmarshal YAML file: %s, error: %s", fileName, err), logName)
-					}
-					os.Exit(1)
-				}
-				// Create the CRD if it does not already exist.
-				err = reconciler.GetClient().Create(context.TODO(), crd)
-				if err != nil && !errors.IsAlreadyExists(err) {
-					if logger.IsEnabled(kappnavutils.LogTypeError) {
-						logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to create CRD: %s, error: %s", crd.GetName(), err), logName)
-					}
-					os.Exit(1)
-				}
-			}
-		}
+		return nil, err
 	}
+	reconciler.components = components
+	return reconciler, nil
+}
 
-	return reconciler
+// getOperatorPodReference returns a reference to the pod of the operator, which is
+// the object that CRD bootstrap events are recorded on, or nil if the pod is not
+// known, e.g. when the operator runs outside of the cluster.
+func getOperatorPodReference(logger kappnavutils.Logger) runtime.Object {
+	podName := os.Getenv(k8sutil.PodNameEnvVar)
+	namespace, err := k8sutil.GetOperatorNamespace()
+	if err != nil || len(podName) == 0 {
+		if logger.IsEnabled(kappnavutils.LogTypeWarning) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeWarning, "Could not determine the operator pod, CRD bootstrap events will not be recorded", logName)
+		}
+		return nil
+	}
+	return &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Namespace:  namespace,
+		Name:       podName,
+	}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(logger kappnavutils.Logger, mgr manager.Manager, r reconcile.Reconciler) error {
+func add(logger kappnavutils.Logger, mgr manager.Manager, r *ReconcileKappnav) error {
 	// Create a new controller
 	c, err := controller.New("kappnav-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -133,41 +141,109 @@ func add(logger kappnavutils.Logger, mgr manager.Manager, r reconcile.Reconciler
 	}
 
 	// Watch for changes to secondary resources that are always created by the operator
-	// (such as Deployment, ConfigMap, Service, etc...) and requeue the owner Kappnav
+	// (such as Deployment, ConfigMap, Service, etc...) and requeue the owner Kappnav.
+	// The status of the Deployments is reflected in the status of the CR, the status
+	// of the other resources is not.
+	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Watch for changes to secondary resource", logName)
+	}
+	err = c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &kappnavv1.Kappnav{},
+	}, kappnavutils.IgnoreUnchangedUpdates())
+	if err != nil {
+		return err
+	}
 	types := []runtime.Object{
-		&appsv1.Deployment{},
 		&corev1.ConfigMap{},
 		&corev1.Secret{},
 		&corev1.Service{},
 		&corev1.ServiceAccount{},
-		&rbacv1.ClusterRoleBinding{},
 		&appv1beta1.Application{},
 		&kamv1.KindActionMapping{}}
-	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Watch for changes to secondary resource", logName)
+	// Cluster scoped resources can only be watched when all namespaces are watched.
+	// The cluster role binding is read from the API server otherwise.
+	if kappnavutils.IsClusterWatched() {
+		types = append(types, &rbacv1.ClusterRoleBinding{})
 	}
 	for i := range types {
 		err = c.Watch(&source.Kind{Type: types[i]}, &handler.EnqueueRequestForOwner{
 			IsController: true,
 			OwnerType:    &kappnavv1.Kappnav{},
-		})
+		}, kappnavutils.IgnoreStatusUpdates())
 		if err != nil {
 			return err
 		}
 	}
 
-	// Watch for changes to secondary resources Ingress and Route
-	// (when available) and requeue the owner Kappnav
-	types = []runtime.Object{&extensionsv1beta1.Ingress{}, &routev1.Route{}}
+	// Watch for changes to secondary resources Ingress, Route, ServiceMonitor and
+	// PrometheusRule (when available) and requeue the owner Kappnav
+	types = []runtime.Object{&extensionsv1beta1.Ingress{}, &routev1.Route{},
+		&monitoringv1.ServiceMonitor{}, &monitoringv1.PrometheusRule{}}
 	for i := range types {
 		_ = c.Watch(&source.Kind{Type: types[i]}, &handler.EnqueueRequestForOwner{
 			IsController: true,
 			OwnerType:    &kappnavv1.Kappnav{},
-		})
+		}, kappnavutils.IgnoreStatusUpdates())
+	}
+
+	// Watch for changes to the secondary resources of the extensions
+	types = kappnavutils.GetExtensionWatchedTypes()
+	for i := range types {
+		err = c.Watch(&source.Kind{Type: types[i]}, &handler.EnqueueRequestForOwner{
+			IsController: true,
+			OwnerType:    &kappnavv1.Kappnav{},
+		}, kappnavutils.IgnoreUnchangedUpdates())
+		if err != nil && logger.IsEnabled(kappnavutils.LogTypeWarning) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeWarning, fmt.Sprintf("Could not watch %T for an extension, Error: %s", types[i], err), logName)
+		}
+	}
+
+	// Watch for changes to the console configuration and requeue all Kappnav, so
+	// that their builtin config maps follow the console URLs. The console config
+	// maps are in namespaces of their own, which can only be watched when all
+	// namespaces are watched.
+	if kappnavutils.IsClusterWatched() {
+		watcher, err := kappnavutils.NewConsoleConfigWatcher(mgr.GetConfig())
+		if err != nil {
+			return err
+		}
+		if err = mgr.Add(watcher); err != nil {
+			return err
+		}
+		for _, src := range watcher.Sources() {
+			err = c.Watch(src, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.mapConsoleConfig)},
+				kappnavutils.IgnoreUnchangedUpdates())
+			if err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
+// mapConsoleConfig returns a request for each Kappnav when the console
+// configuration changes
+func (r *ReconcileKappnav) mapConsoleConfig(obj handler.MapObject) []reconcile.Request {
+	logger := kappnavutils.NewLogger()
+	instances := &kappnavv1.KappnavList{}
+	if err := r.GetClient().List(context.TODO(), &client.ListOptions{}, instances); err != nil {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Could not list the Kappnav to refresh after the console configuration %s/%s changed, Error: %s",
+				obj.Meta.GetNamespace(), obj.Meta.GetName(), err), logName)
+		}
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range instances.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: instances.Items[i].GetNamespace(),
+			Name:      instances.Items[i].GetName(),
+		}})
+	}
+	return requests
+}
+
 // blank assignment to verify that ReconcileKappnav implements reconcile.Reconciler
 var _ reconcile.Reconciler = &ReconcileKappnav{}
 
@@ -176,6 +252,11 @@ type ReconcileKappnav struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
 	kappnavutils.ReconcilerBase
+	crdManager   *kappnavutils.CRDManager
+	crdBootstrap *kappnavutils.CRDBootstrap
+	// components are the builtin and registered components in the order they
+	// are reconciled
+	components []kappnavutils.ComponentReconciler
 }
 
 // Reconcile reads that state of the cluster for a Kappnav object and makes changes based on the state read
@@ -184,7 +265,22 @@ type ReconcileKappnav struct {
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileKappnav) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	logger := kappnavutils.NewLogger(true) //log in json format
+	// Every message of this reconcile carries the request and a reconcile ID,
+	// so that the messages of one reconcile can be correlated.
+	logger := kappnavutils.NewLogger().WithFields("kind", "Kappnav", "namespace", request.Namespace,
+		"name", request.Name, "reconcileID", string(uuid.NewUUID()))
+
+	// The operations of the reconcile are traced as children of its span
+	logger, span := kappnavutils.StartSpan(logger, "Reconcile",
+		kappnavutils.ObjectAttributes("Kappnav", request.Namespace, request.Name)...)
+	result, err := r.reconcileKappnav(logger, request)
+	kappnavutils.EndSpan(span, err)
+	return result, err
+}
+
+// reconcileKappnav reconciles the Kappnav CR of the request, logging and tracing
+// with the logger of the reconcile
+func (r *ReconcileKappnav) reconcileKappnav(logger kappnavutils.Logger, request reconcile.Request) (result reconcile.Result, err error) {
 	var otherLogData = " in Request.Namespace: " + request.Namespace + ", Request.Name: " + request.Name
 
 	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
@@ -197,20 +293,23 @@ func (r *ReconcileKappnav) Reconcile(request reconcile.Request) (reconcile.Resul
 		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Fetch kappnav instance"+otherLogData, logName)
 	}
 
-	err := r.GetClient().Get(context.TODO(), request.NamespacedName, instance)
+	err = r.GetClient().Get(logger.Context(), request.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
-			// Return and don't requeue
+			// Remove the metrics of the instance. Return and don't requeue
+			kappnavutils.DeleteInstanceMetrics(request.Namespace, request.Name)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
 
-	// Call factory method to create new KappnavExtension
-	extension := kappnavutils.NewKappnavExtension()
+	// Run the deletion hooks of the extensions when the CR is deleted
+	if done, result, err := r.reconcileDeletion(logger, instance); done {
+		return result, err
+	}
 
 	// Apply defaults to the Kappnav instance
 	err = kappnavutils.SetKappnavDefaults(instance)
@@ -224,207 +323,104 @@ func (r *ReconcileKappnav) Reconcile(request reconcile.Request) (reconcile.Resul
 		return reconcile.Result{}, err
 	}
 
+	// Fill in proxy settings from the cluster and the operator environment
+	kappnavutils.SetProxyDefaults(logger, &r.ReconcilerBase, instance)
+
 	// Retrieve logging info from kappnav CR and update the kappnavutils level
 	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
 		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Retrieve logging info from kappnav CR"+otherLogData, logName)
 	}
-	loggingMap := instance.Spec.Logging
-	if len(loggingMap["operator"]) > 0 {
+	// Start, continue or end the debug period requested in the CR. The request is
+	// queued again when the period ends to revert the log levels, whatever the
+	// outcome of the reconcile, so that the backoff of a failed reconcile does not
+	// extend the period.
+	debugRemaining := kappnavutils.UpdateDebugLoggingStatus(instance, time.Now())
+	defer func() {
+		if debugRemaining <= 0 {
+			return
+		}
+		if err != nil {
+			// The result is ignored when an error is returned
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Reconcile failed"+otherLogData+", Error: %s", err), logName)
+			}
+			result, err = reconcile.Result{Requeue: true}, nil
+		}
+		if result.RequeueAfter == 0 || debugRemaining < result.RequeueAfter {
+			result.RequeueAfter = debugRemaining
+		}
+	}()
+	loggingMap := kappnavutils.GetEffectiveLogLevels(instance)
+	if len(loggingMap[kappnavutils.LoggingComponentOperator]) > 0 {
 		if logger.IsEnabled(kappnavutils.LogTypeDebug) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeDebug, fmt.Sprintf("Set the log level to ", loggingMap["operator"])+otherLogData, logName)
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeDebug, fmt.Sprintf("Set the log level to %s", loggingMap[kappnavutils.LoggingComponentOperator])+otherLogData, logName)
 		}
-		setLoggingLevel(logger, loggingMap["operator"])
-	}
-
-	kappnavName := &metav1.ObjectMeta{
-		Name:      "kappnav",
-		Namespace: instance.GetNamespace(),
+		setLoggingLevel(logger, string(loggingMap[kappnavutils.LoggingComponentOperator]))
 	}
 
-	uiServiceAndRouteName := &metav1.ObjectMeta{
-		Name:      instance.GetName() + "-ui-service",
-		Namespace: instance.GetNamespace(),
-	}
-
-	serviceAccount := &corev1.ServiceAccount{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      instance.GetName() + "-" + kappnavutils.ServiceAccountNameSuffix,
-			Namespace: instance.GetNamespace(),
-		},
-	}
-	// Create or update service account
-	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update service account"+otherLogData, logName)
-	}
-	err = r.CreateOrUpdate(logger, serviceAccount, instance, func() error {
-		kappnavutils.CustomizeServiceAccount(logger, serviceAccount, uiServiceAndRouteName, instance)
-		return nil
-	})
-	if err != nil {
-		if logger.IsEnabled(kappnavutils.LogTypeError) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the ServiceAccount"+otherLogData+", Error: %s ", err), logName)
-		}
+	// Wait until the CRDs shipped in the image are established
+	if err := r.crdBootstrap.Check(); err != nil {
 		return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
 	}
 
-	// Create or update cluster role binding
-	crb := &rbacv1.ClusterRoleBinding{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      instance.GetName() + "-" + instance.GetNamespace() + "-crb",
-			Namespace: instance.GetNamespace(),
-		},
-	}
-	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update cluster role binding"+otherLogData, logName)
-	}
-	err = r.CreateOrUpdate(logger, crb, instance, func() error {
-		kappnavutils.CustomizeClusterRoleBinding(crb, serviceAccount, instance)
-		return nil
-	})
-	if err != nil && !errors.IsAlreadyExists(err) {
+	// Refuse the CR if it would take over the resources of another CR
+	if err := kappnavutils.CheckInstanceConflicts(logger, &r.ReconcilerBase, instance); err != nil {
 		if logger.IsEnabled(kappnavutils.LogTypeError) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the ClusterRoleBinding"+otherLogData+", Error: %s ", err), logName)
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Kappnav conflicts with another Kappnav"+otherLogData+", Error: %s", err), logName)
 		}
 		return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
 	}
 
-	// Dummy secret for Minikube support
-	dummySecret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      instance.GetName() + "-" + kappnavutils.OAuthVolumeName,
-			Namespace: instance.GetNamespace(),
-		},
+	// Upgrade the installation first if the operator version differs from the installed version
+	if inProgress, result, err := r.reconcileUpgrade(logger, instance); inProgress {
+		return result, err
 	}
 
-	// The kappnav application
-	kappnavCR := &appv1beta1.Application{
-		ObjectMeta: *kappnavName,
-	}
-	kappnavCRAnnotations := map[string]string{
-		"kappnav.application.hidden": "true",
+	// Reconcile the components. A component that fails does not stop the
+	// components that do not depend on it.
+	ctx := &kappnavutils.ComponentContext{
+		Request:    request,
+		Reconciler: &r.ReconcilerBase,
+		Instance:   instance,
+		Names:      kappnavutils.GetInstanceNames(instance),
 	}
+	result, failed := kappnavutils.ReconcileComponents(logger, ctx, r.components)
+	kappnavutils.SetExtensionConditions(logger, &r.ReconcilerBase, instance)
 
-	// Create or update the kappnav Application
-	err = r.CreateOrUpdate(logger, kappnavCR, instance, func() error {
-		kappnavutils.CustomizeApplication(kappnavCR, instance, kappnavCRAnnotations)
-		return nil
-	})
-	if err != nil {
-		if logger.IsEnabled(kappnavutils.LogTypeError) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the kappnav Application"+otherLogData+", Error: %s ", err), logName)
-		}
+	// Record the images and rollout progress of the deployments in the status.
+	// Deployment status changes are delivered through the Deployment watch.
+	kappnavutils.SetComponentStatus(logger, &r.ReconcilerBase, instance, ctx.Deployments...)
+
+	if err := kappnavutils.GetComponentsError(r.components, failed); err != nil {
 		return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
 	}
 
-	// The UI service
-	uiService := &corev1.Service{
-		ObjectMeta: *uiServiceAndRouteName,
+	// Record the installed version once a new installation has been reconciled.
+	if instance.Status.Upgrade == nil || instance.Status.Upgrade.Phase == kappnavv1.UpgradePhaseCompleted {
+		instance.Status.Version = version.Version
 	}
-	uiServiceAnnotations := map[string]string{
-		"service.alpha.openshift.io/serving-cert-secret-name": dummySecret.Name,
-	}
-
-	// Kappnav URL is computed from the route
-	kappnavURL := ""
 
-	isMinikube := kappnavutils.IsMinikubeEnv(instance.Spec.Env.KubeEnv)
-	if isMinikube {
-		// Create or update dummy secret
-		err = r.CreateOrUpdate(logger, dummySecret, instance, func() error {
-			kappnavutils.CustomizeSecret(dummySecret, instance)
-			return nil
-		})
-		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update dummy secret"+otherLogData, logName)
-		}
-		if err != nil {
-			if logger.IsEnabled(kappnavutils.LogTypeError) {
-				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the dummy secret"+otherLogData+", Error: %s ", err), logName)
-			}
-			return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
-		}
-		// Create or update the UI service
-		err = r.CreateOrUpdate(logger, uiService, instance, func() error {
-			kappnavutils.CustomizeService(uiService, instance, uiServiceAnnotations)
-			kappnavutils.CustomizeUIServiceSpec(&uiService.Spec, instance)
-			return nil
-		})
-		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update UI service"+otherLogData, logName)
-		}
-		if err != nil {
-			if logger.IsEnabled(kappnavutils.LogTypeError) {
-				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the UI service"+otherLogData+", Error: %s ", err), logName)
-			}
-			return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
-		}
-		// Create or update UI ingress
-		uiIngress := &extensionsv1beta1.Ingress{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      instance.GetName() + "-ui-ingress",
-				Namespace: instance.GetNamespace(),
-			},
-		}
-		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update UI ingress"+otherLogData, logName)
-		}
-		err = r.CreateOrUpdate(logger, uiIngress, instance, func() error {
-			kappnavutils.CustomizeIngress(uiIngress, instance)
-			kappnavutils.CustomizeUIIngressSpec(&uiIngress.Spec, uiService, instance)
-			return nil
-		})
-		if err != nil {
-			if logger.IsEnabled(kappnavutils.LogTypeError) {
-				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the UI ingress"+otherLogData+", Error: %s ", err), logName)
-			}
-			return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
-		}
-	} else {
-		// Create or update the UI service
-		err = r.CreateOrUpdate(logger, uiService, instance, func() error {
-			kappnavutils.CustomizeService(uiService, instance, uiServiceAnnotations)
-			kappnavutils.CustomizeUIServiceSpec(&uiService.Spec, instance)
-			return nil
-		})
-		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update UI service"+otherLogData, logName)
-		}
-		if err != nil {
-			if logger.IsEnabled(kappnavutils.LogTypeError) {
-				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the UI Service"+otherLogData+", Error: %s ", err), logName)
-			}
-			return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
-		}
-		// Create or update UI route
-		uiRoute := &routev1.Route{
-			ObjectMeta: *uiServiceAndRouteName,
-		}
-		err = r.CreateOrUpdate(logger, uiRoute, instance, func() error {
-			kappnavutils.CustomizeRoute(uiRoute, instance)
-			kappnavutils.CustomizeUIRouteSpec(&uiRoute.Spec, uiServiceAndRouteName, instance)
-			return nil
-		})
-		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update UI route"+otherLogData, logName)
-		}
-		if err != nil {
-			if logger.IsEnabled(kappnavutils.LogTypeError) {
-				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the UI route"+otherLogData+", Error: %s ", err), logName)
-			}
-			return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
-		}
-		// Compute Kappnav URL from route.
-		routeHost := uiRoute.Spec.Host
-		routePath := uiRoute.Spec.Path
-		if len(routeHost) > 0 && len(routePath) > 0 {
-			kappnavURL = "https://" + routeHost + routePath
-		}
+	successResult, err := r.ManageSuccess(logger, kappnavv1.StatusConditionTypeReconciled, instance)
+	if err == nil && (successResult.Requeue || successResult.RequeueAfter > 0) {
+		result = successResult
 	}
-
-	// Create or update action, section and status config maps.
-	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update action, section and status config maps"+otherLogData, logName)
+	// Come back to refresh the console URLs of the builtin config map.
+	if resync := kappnavutils.GetBuiltinResyncPeriod(instance); err == nil && resync > 0 && (result.RequeueAfter == 0 || resync < result.RequeueAfter) {
+		result.RequeueAfter = resync
 	}
+	return result, err
+
+}
+
+// reconcileConfigMaps creates or updates the action, section and status config maps
+// shipped in the image. The data of existing maps, which users may have customized,
+// is kept. If addNewKeys is true the shipped keys the maps do not have are added.
+func (r *ReconcileKappnav) reconcileConfigMaps(logger kappnavutils.Logger, instance *kappnavv1.Kappnav, addNewKeys bool) (err error) {
+	var otherLogData = " in Request.Namespace: " + instance.GetNamespace() + ", Request.Name: " + instance.GetName()
+	logger, span := kappnavutils.StartSpan(logger, "ReconcileConfigMaps")
+	defer func() { kappnavutils.EndSpan(span, err) }()
+	names := kappnavutils.GetInstanceNames(instance)
+	unusedNames := kappnavutils.GetUnusedInstanceNames(instance)
 	mapDirs := []string{"maps/action", "maps/sections", "maps/status"}
 	for _, dir := range mapDirs {
 		if logger.IsEnabled(kappnavutils.LogTypeDebug) {
@@ -435,7 +431,7 @@ func (r *ReconcileKappnav) Reconcile(request reconcile.Request) (reconcile.Resul
 			if logger.IsEnabled(kappnavutils.LogTypeError) {
 				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the read directory %s "+otherLogData+", Error: %s ", dir, err), logName)
 			}
-			return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
+			return err
 		}
 		for _, file := range files {
 			if !file.IsDir() {
@@ -450,7 +446,7 @@ func (r *ReconcileKappnav) Reconcile(request reconcile.Request) (reconcile.Resul
 						if logger.IsEnabled(kappnavutils.LogTypeError) {
 							logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to read file: %s "+otherLogData+", Error: %s ", fileName, err), logName)
 						}
-						return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
+						return err
 					}
 					// Parse the file into a template.
 					t, err := template.New(fileName).Parse(string(fData))
@@ -458,16 +454,17 @@ func (r *ReconcileKappnav) Reconcile(request reconcile.Request) (reconcile.Resul
 						if logger.IsEnabled(kappnavutils.LogTypeError) {
 							logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to parse template file: %s "+otherLogData+", Error: %s ", fileName, err), logName)
 						}
-						return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
+						return err
 					}
-					// Execute the template against the Kappnav CR instance.
+					// Execute the template against the Kappnav CR instance and the
+					// data of the extensions.
 					var buf bytes.Buffer
-					err = t.Execute(&buf, instance)
+					err = t.Execute(&buf, kappnavutils.GetMapTemplateData(instance))
 					if err != nil {
 						if logger.IsEnabled(kappnavutils.LogTypeError) {
 							logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to execute template: %s "+otherLogData+", Error: %s ", fileName, err), logName)
 						}
-						return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
+						return err
 					}
 					configMap := &corev1.ConfigMap{}
 					// Unmarshal the YAML into an object.
@@ -476,11 +473,11 @@ func (r *ReconcileKappnav) Reconcile(request reconcile.Request) (reconcile.Resul
 						if logger.IsEnabled(kappnavutils.LogTypeError) {
 							logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to unmarshal YAML file: %s "+otherLogData+", Error: %s", fileName, err), logName)
 						}
-						return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
+						return err
 					}
 					clusterMap := &corev1.ConfigMap{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      configMap.GetName(),
+							Name:      names.ConfigMapName(configMap.GetName()),
 							Namespace: instance.GetNamespace(),
 						},
 					}
@@ -490,104 +487,45 @@ func (r *ReconcileKappnav) Reconcile(request reconcile.Request) (reconcile.Resul
 						// Write the data section if it doesn't exist or is empty.
 						if clusterMap.Data == nil || len(clusterMap.Data) == 0 {
 							clusterMap.Data = configMap.Data
+						} else if addNewKeys {
+							for key, value := range configMap.Data {
+								if _, ok := clusterMap.Data[key]; !ok {
+									clusterMap.Data[key] = value
+								}
+							}
 						}
 						return nil
 					})
 					if err != nil {
 						if logger.IsEnabled(kappnavutils.LogTypeError) {
-							logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the %s ConfigMap"+otherLogData+", Error: %s", configMap.GetName(), err), logName)
+							logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the %s ConfigMap"+otherLogData+", Error: %s", clusterMap.GetName(), err), logName)
 						}
-						return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
+						return err
+					}
+					// Delete the map of the isolation mode the CR does not use.
+					err = r.deleteIfControlled(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+						Name:      unusedNames.ConfigMapName(configMap.GetName()),
+						Namespace: instance.GetNamespace(),
+					}}, instance)
+					if err != nil {
+						return err
 					}
 				}
 			}
 		}
 	}
+	return nil
+}
 
-	// Create or update builtin config
-	builtinConfig := &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "builtin",
-			Namespace: instance.GetNamespace(),
-		},
-	}
-	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update builtin config"+otherLogData, logName)
-	}
-	err = r.CreateOrUpdate(logger, builtinConfig, instance, func() error {
-		kappnavutils.CustomizeConfigMap(builtinConfig, instance, "builtin")
-		kappnavutils.CustomizeBuiltinConfigMap(logger, builtinConfig, &r.ReconcilerBase, instance)
-		return nil
-	})
-	if err != nil {
-		if logger.IsEnabled(kappnavutils.LogTypeError) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the kappnav-config ConfigMap"+otherLogData+", Error: %s ", err), logName)
-		}
-		return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
-	}
-
-	// Create or update kappnav-config
-	kappnavConfig := &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "kappnav-config",
-			Namespace: instance.GetNamespace(),
-		},
-	}
-	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update kappnav-config"+otherLogData, logName)
-	}
-	err = r.CreateOrUpdate(logger, kappnavConfig, instance, func() error {
-		kappnavutils.CustomizeConfigMap(kappnavConfig, instance, "builtin")
-		kappnavutils.CustomizeKappnavConfigMap(kappnavConfig, kappnavURL, instance)
-		return nil
-	})
-	if err != nil {
-		if logger.IsEnabled(kappnavutils.LogTypeError) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the kappnav-config ConfigMap"+otherLogData+", Error: %s", err), logName)
-		}
-		return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
-	}
-
-	// Apply defaults to the KindActionMapping (kam) instance
-	default_kam := &kamv1.KindActionMapping{}
-	err = kappnavutils.SetKAMDefaults(default_kam)
-
-	// Set ObjectMeta of KindActionMapping
-	kamCR := &kamv1.KindActionMapping{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "default",
-			Namespace: instance.GetNamespace(),
-		},
-	}
-
-	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update KindActionMapping"+otherLogData, logName)
-	}
-
-	// Create or update the KindActionMapping (kam)
-	err = r.CreateOrUpdate(logger, kamCR, instance, func() error {
-		kappnavutils.CustomizeKAM(kamCR, default_kam, instance)
-		return nil
-	})
-
-	if err != nil {
-		if logger.IsEnabled(kappnavutils.LogTypeError) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the KindActionMapping"+otherLogData+", Error: %s", err), logName)
-		}
-		return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
-	}
-
-	// Create or update the UI deployment
+// reconcileUIDeployment creates or updates the UI deployment
+func (r *ReconcileKappnav) reconcileUIDeployment(logger kappnavutils.Logger, instance *kappnavv1.Kappnav) (*appsv1.Deployment, error) {
 	uiDeployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      instance.GetName() + "-ui",
 			Namespace: instance.GetNamespace(),
 		},
 	}
-	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update UI deployment"+otherLogData, logName)
-	}
-	err = r.CreateOrUpdate(logger, uiDeployment, instance, func() error {
+	err := r.CreateOrUpdate(logger, uiDeployment, instance, func() error {
 		pts := &uiDeployment.Spec.Template
 		kappnavutils.CustomizeDeployment(uiDeployment, instance)
 		kappnavutils.CustomizePodSpec(pts, &uiDeployment.ObjectMeta,
@@ -595,46 +533,29 @@ func (r *ReconcileKappnav) Reconcile(request reconcile.Request) (reconcile.Resul
 			kappnavutils.CreateUIVolumes(instance), instance)
 		return nil
 	})
-	if err != nil {
-		if logger.IsEnabled(kappnavutils.LogTypeError) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the UI Deployment"+otherLogData+", Error: %s ", err), logName)
-		}
-		return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
-	}
+	return uiDeployment, err
+}
 
-	// Create or update the Controller deployment
+// reconcileControllerDeployment creates or updates the controller deployment
+func (r *ReconcileKappnav) reconcileControllerDeployment(logger kappnavutils.Logger, instance *kappnavv1.Kappnav) (*appsv1.Deployment, error) {
 	controllerDeployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      instance.GetName() + "-controller",
 			Namespace: instance.GetNamespace(),
 		},
 	}
-	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
-		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update controller deployment"+otherLogData, logName)
-	}
-	err = r.CreateOrUpdate(logger, controllerDeployment, instance, func() error {
+	err := r.CreateOrUpdate(logger, controllerDeployment, instance, func() error {
 		pts := &controllerDeployment.Spec.Template
 		kappnavutils.CustomizeDeployment(controllerDeployment, instance)
 		kappnavutils.CustomizePodSpec(pts, &controllerDeployment.ObjectMeta,
-			kappnavutils.CreateControllerDeploymentContainers(pts.Spec.Containers, instance), nil, instance)
+			kappnavutils.CreateControllerDeploymentContainers(pts.Spec.Containers, instance),
+			kappnavutils.CreateControllerVolumes(instance), instance)
 		return nil
 	})
-	if err != nil {
-		if logger.IsEnabled(kappnavutils.LogTypeError) {
-			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the Controller Deployment"+otherLogData+", Error: %s", err), logName)
-		}
-		return r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
-	}
-
-	// If an extension exists call its reconcile function, otherwise return success.
-	if extension != nil {
-		return extension.ReconcileAdditionalResources(logger, request, &r.ReconcilerBase, instance)
-	}
-	return r.ManageSuccess(logger, kappnavv1.StatusConditionTypeReconciled, instance)
-
+	return controllerDeployment, err
 }
 
-// get logging info from CR and set logger level
+// get logging info from CR and set the process-wide logger level
 func setLoggingLevel(logger kappnavutils.Logger, loginfo string) {
 	switch loginfo {
 	case "info":
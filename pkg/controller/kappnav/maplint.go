@@ -0,0 +1,123 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kappnav
+
+import (
+	"context"
+	"fmt"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	"github.com/kappnav/operator/pkg/lint"
+	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxMapIssues limits the number of issues recorded in the status of the CR
+const maxMapIssues = 50
+
+// lintConfigMaps checks the action, section and status config maps in the
+// namespace of the CR, including the maps created by users, and records the
+// errors found and the MapsValid condition in the status of the CR. Errors in
+// the maps do not fail the reconcile. The caller is responsible for writing
+// the status.
+func (r *ReconcileKappnav) lintConfigMaps(logger kappnavutils.Logger, instance *kappnavv1.Kappnav) {
+	var otherLogData = " in Request.Namespace: " + instance.GetNamespace() + ", Request.Name: " + instance.GetName()
+	// The maps of users do not have the managed-by label of the operator and are
+	// not in the cache. They usually do not have the map type label either, so all
+	// the maps are listed and classified by their label or name.
+	reader, err := r.GetAPIReader()
+	if err != nil {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to list config maps for linting"+otherLogData+", Error: %s ", err), logName)
+		}
+		return
+	}
+	list := &corev1.ConfigMapList{}
+	err = reader.List(context.TODO(), &client.ListOptions{Namespace: instance.GetNamespace()}, list)
+	if err != nil {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to list config maps for linting"+otherLogData+", Error: %s ", err), logName)
+		}
+		return
+	}
+	var configMaps []corev1.ConfigMap
+	for i := range list.Items {
+		if len(lint.GetMapType(&list.Items[i])) > 0 {
+			configMaps = append(configMaps, list.Items[i])
+		}
+	}
+
+	// Record the number of maps of each type
+	counts := map[lint.MapType]int{lint.MapTypeAction: 0, lint.MapTypeSections: 0, lint.MapTypeStatus: 0}
+	for i := range configMaps {
+		counts[lint.GetMapType(&configMaps[i])]++
+	}
+	for mapType, count := range counts {
+		kappnavutils.SetConfigMapCount(instance.GetNamespace(), string(mapType), count)
+	}
+
+	var mapIssues []kappnavv1.MapIssue
+	errors, warnings := 0, 0
+	for i := range configMaps {
+		for _, issue := range lint.ConfigMap(&configMaps[i]) {
+			if issue.Severity != lint.SeverityError {
+				warnings++
+				if logger.IsEnabled(kappnavutils.LogTypeDebug) {
+					logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeDebug, issue.String()+otherLogData, logName)
+				}
+				continue
+			}
+			errors++
+			if logger.IsEnabled(kappnavutils.LogTypeWarning) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeWarning, issue.String()+otherLogData, logName)
+			}
+			if len(mapIssues) < maxMapIssues {
+				mapIssues = append(mapIssues, kappnavv1.MapIssue{
+					Map:      issue.Map,
+					Location: issue.Key + issue.Path,
+					Message:  issue.Message,
+				})
+			}
+		}
+	}
+	instance.Status.MapIssues = mapIssues
+
+	status := corev1.ConditionTrue
+	reason := ""
+	message := fmt.Sprintf("%d config maps checked, %d warnings", len(configMaps), warnings)
+	if errors > 0 {
+		status = corev1.ConditionFalse
+		reason = "MapErrors"
+		message = fmt.Sprintf("%d errors and %d warnings in %d config maps", errors, warnings, len(configMaps))
+	}
+	now := metav1.Now()
+	condition := kappnavv1.StatusCondition{
+		LastTransitionTime: &now,
+		LastUpdateTime:     now,
+		Reason:             reason,
+		Message:            message,
+		Status:             status,
+		Type:               kappnavv1.StatusConditionTypeMapsValid,
+	}
+	// Keep the old times when nothing has changed
+	if old := kappnavutils.GetCondition(kappnavv1.StatusConditionTypeMapsValid, &instance.Status); old != nil && old.Status == status {
+		condition.LastTransitionTime = old.LastTransitionTime
+		if old.Message == message {
+			condition.LastUpdateTime = old.LastUpdateTime
+		}
+	}
+	kappnavutils.SetCondition(condition, &instance.Status)
+}
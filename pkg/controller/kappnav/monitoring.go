@@ -0,0 +1,116 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kappnav
+
+import (
+	"fmt"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// reconcileMonitoring creates or updates the metrics Services and ServiceMonitors
+// of the kAppNav components and the PrometheusRule with the default alerts, or
+// deletes them when monitoring is disabled in the CR. Nothing is done when the
+// Prometheus operator is not installed.
+func (r *ReconcileKappnav) reconcileMonitoring(logger kappnavutils.Logger, instance *kappnavv1.Kappnav) error {
+	var otherLogData = " in Request.Namespace: " + instance.GetNamespace() + ", Request.Name: " + instance.GetName()
+
+	// The certificate alert is based on this metric
+	kappnavutils.RecordCertificateExpiry(logger, &r.ReconcilerBase, instance)
+
+	supported, err := r.IsGroupVersionSupported(logger, kappnavutils.MonitoringGroupVersion)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		if kappnavutils.IsMonitoringEnabled(instance) && logger.IsEnabled(kappnavutils.LogTypeWarning) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeWarning, "Monitoring is enabled but "+kappnavutils.MonitoringGroupVersion+" is not available, install the Prometheus operator"+otherLogData, logName)
+		}
+		return nil
+	}
+
+	var unused []runtime.Object
+	for i := range kappnavutils.MetricsComponents {
+		component := &kappnavutils.MetricsComponents[i]
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kappnavutils.GetMetricsServiceName(instance, component),
+				Namespace: instance.GetNamespace(),
+			},
+		}
+		serviceMonitor := &monitoringv1.ServiceMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kappnavutils.GetServiceMonitorName(instance, component),
+				Namespace: instance.GetNamespace(),
+			},
+		}
+		if !kappnavutils.IsMonitoringEnabled(instance) {
+			unused = append(unused, serviceMonitor, service)
+			continue
+		}
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update "+component.Name+" metrics service and service monitor"+otherLogData, logName)
+		}
+		err = r.CreateOrUpdate(logger, service, instance, func() error {
+			kappnavutils.CustomizeMetricsService(service, instance, component)
+			return nil
+		})
+		if err != nil {
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the %s metrics Service"+otherLogData+", Error: %s", component.Name, err), logName)
+			}
+			return err
+		}
+		err = r.CreateOrUpdate(logger, serviceMonitor, instance, func() error {
+			kappnavutils.CustomizeServiceMonitor(serviceMonitor, instance, component)
+			return nil
+		})
+		if err != nil {
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the %s ServiceMonitor"+otherLogData+", Error: %s", component.Name, err), logName)
+			}
+			return err
+		}
+	}
+
+	rule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kappnavutils.GetPrometheusRuleName(instance),
+			Namespace: instance.GetNamespace(),
+		},
+	}
+	if kappnavutils.IsAlertingEnabled(instance) {
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Create or update prometheus rule"+otherLogData, logName)
+		}
+		err = r.CreateOrUpdate(logger, rule, instance, func() error {
+			kappnavutils.CustomizePrometheusRule(rule, instance)
+			return nil
+		})
+		if err != nil {
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Failed to reconcile the PrometheusRule"+otherLogData+", Error: %s", err), logName)
+			}
+			return err
+		}
+	} else {
+		unused = append(unused, rule)
+	}
+	return r.DeleteResources(unused)
+}
@@ -0,0 +1,432 @@
+/*
+Copyright 2019 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kappnav
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	kamv1 "github.com/kappnav/operator/pkg/apis/actions/v1"
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	"github.com/kappnav/operator/version"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// upgradeRolloutTimeout is how long a Deployment may take to become ready
+	// during an upgrade before the upgrade is rolled back.
+	upgradeRolloutTimeout = 10 * time.Minute
+	// upgradePollInterval is how often the rollout of a Deployment is checked.
+	upgradePollInterval = 10 * time.Second
+)
+
+// upgradeHook is a step run before or after the Deployments are rolled.
+type upgradeHook struct {
+	name string
+	run  func(r *ReconcileKappnav, logger kappnavutils.Logger, instance *kappnavv1.Kappnav) error
+}
+
+// preUpgradeHooks run in order before any Deployment is rolled.
+var preUpgradeHooks = []upgradeHook{
+	{name: "migrate config maps", run: migrateConfigMaps},
+	{name: "migrate kind action mappings", run: migrateKAMs},
+	{name: "update CRDs", run: updateCRDs},
+}
+
+// postUpgradeHooks run in order after all Deployments were rolled.
+var postUpgradeHooks = []upgradeHook{
+	{name: "verify deployments", run: verifyDeployments},
+}
+
+// upgradeDeployment is a Deployment that is rolled during an upgrade.
+type upgradeDeployment struct {
+	suffix    string
+	reconcile func(r *ReconcileKappnav, logger kappnavutils.Logger, instance *kappnavv1.Kappnav) (*appsv1.Deployment, error)
+}
+
+// upgradeDeployments are rolled one at a time in this order.
+var upgradeDeployments = []upgradeDeployment{
+	{suffix: "-controller", reconcile: (*ReconcileKappnav).reconcileControllerDeployment},
+	{suffix: "-ui", reconcile: (*ReconcileKappnav).reconcileUIDeployment},
+}
+
+// reconcileUpgrade drives the upgrade from the installed kAppNav version to the
+// version of the operator. It returns true while the upgrade is in progress or has
+// failed, in which case the rest of the reconcile must not run and the returned
+// result and error are passed back to the controller.
+func (r *ReconcileKappnav) reconcileUpgrade(logger kappnavutils.Logger, instance *kappnavv1.Kappnav) (bool, reconcile.Result, error) {
+	status := &instance.Status
+	upgrade := status.Upgrade
+	if upgrade == nil || upgrade.ToVersion != version.Version {
+		if status.Version == version.Version {
+			return false, reconcile.Result{}, nil
+		}
+		fromVersion := status.Version
+		if len(fromVersion) == 0 {
+			// The CR predates version tracking or is a new install.
+			fromVersion = r.getInstalledVersion(instance)
+			if len(fromVersion) == 0 || fromVersion == version.Version {
+				return false, reconcile.Result{}, nil
+			}
+		}
+		return r.startUpgrade(logger, instance, fromVersion)
+	}
+
+	switch upgrade.Phase {
+	case kappnavv1.UpgradePhaseCompleted:
+		return false, reconcile.Result{}, nil
+	case kappnavv1.UpgradePhaseFailed:
+		if upgrade.ObservedGeneration != instance.GetGeneration() {
+			// The CR was changed since the failure, try again.
+			return r.startUpgrade(logger, instance, upgrade.FromVersion)
+		}
+		result, err := r.ManageError(logger, fmt.Errorf("upgrade from %s to %s failed: %s",
+			upgrade.FromVersion, upgrade.ToVersion, upgrade.Message), kappnavv1.StatusConditionTypeReconciled, instance)
+		return true, result, err
+	case kappnavv1.UpgradePhasePreUpgrade:
+		return r.runUpgradeHooks(logger, instance, preUpgradeHooks, kappnavv1.UpgradePhaseRollDeployments)
+	case kappnavv1.UpgradePhaseRollDeployments:
+		return r.rollDeployments(logger, instance)
+	case kappnavv1.UpgradePhasePostUpgrade:
+		return r.runUpgradeHooks(logger, instance, postUpgradeHooks, kappnavv1.UpgradePhaseCompleted)
+	case kappnavv1.UpgradePhaseRollingBack:
+		return r.rollBackDeployments(logger, instance)
+	}
+	return r.startUpgrade(logger, instance, upgrade.FromVersion)
+}
+
+func (r *ReconcileKappnav) startUpgrade(logger kappnavutils.Logger, instance *kappnavv1.Kappnav, fromVersion string) (bool, reconcile.Result, error) {
+	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, fmt.Sprintf("Starting upgrade from %s to %s", fromVersion, version.Version), logName)
+	}
+	r.GetRecorder().Event(instance, "Normal", "UpgradeStarted",
+		fmt.Sprintf("Upgrading kAppNav from %s to %s", fromVersion, version.Version))
+	now := metav1.Now()
+	instance.Status.Upgrade = &kappnavv1.UpgradeStatus{
+		FromVersion:        fromVersion,
+		ToVersion:          version.Version,
+		Phase:              kappnavv1.UpgradePhasePreUpgrade,
+		ObservedGeneration: instance.GetGeneration(),
+		StartTime:          now,
+		PhaseStartTime:     now,
+	}
+	return r.updateUpgradeStatus(logger, instance, reconcile.Result{Requeue: true})
+}
+
+// runUpgradeHooks runs the hooks in order and moves the upgrade to the next phase.
+func (r *ReconcileKappnav) runUpgradeHooks(logger kappnavutils.Logger, instance *kappnavv1.Kappnav,
+	hooks []upgradeHook, nextPhase kappnavv1.UpgradePhase) (bool, reconcile.Result, error) {
+	upgrade := instance.Status.Upgrade
+	for _, hook := range hooks {
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Running upgrade step: "+hook.name, logName)
+		}
+		err := hook.run(r, logger, instance)
+		if err != nil {
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Upgrade step %s failed, Error: %s", hook.name, err), logName)
+			}
+			upgrade.Message = fmt.Sprintf("%s: %s", hook.name, err)
+			if len(upgrade.PreviousImages) > 0 {
+				return r.setUpgradePhase(logger, instance, kappnavv1.UpgradePhaseRollingBack)
+			}
+			return r.setUpgradePhase(logger, instance, kappnavv1.UpgradePhaseFailed)
+		}
+	}
+	if nextPhase == kappnavv1.UpgradePhaseRollDeployments {
+		upgrade.Deployment = instance.GetName() + upgradeDeployments[0].suffix
+	}
+	if nextPhase == kappnavv1.UpgradePhaseCompleted {
+		instance.Status.Version = upgrade.ToVersion
+		upgrade.Deployment = ""
+		upgrade.Message = ""
+		r.GetRecorder().Event(instance, "Normal", "UpgradeCompleted",
+			fmt.Sprintf("Upgraded kAppNav from %s to %s", upgrade.FromVersion, upgrade.ToVersion))
+	}
+	return r.setUpgradePhase(logger, instance, nextPhase)
+}
+
+// rollDeployments updates the current Deployment and waits for it to become ready
+// before moving on to the next one.
+func (r *ReconcileKappnav) rollDeployments(logger kappnavutils.Logger, instance *kappnavv1.Kappnav) (bool, reconcile.Result, error) {
+	upgrade := instance.Status.Upgrade
+	index := -1
+	for i, d := range upgradeDeployments {
+		if instance.GetName()+d.suffix == upgrade.Deployment {
+			index = i
+		}
+	}
+	if index < 0 {
+		upgrade.Deployment = instance.GetName() + upgradeDeployments[0].suffix
+		index = 0
+	}
+
+	// Remember the images in use before the Deployment is first updated.
+	if err := r.recordPreviousImages(instance, upgrade.Deployment); err != nil {
+		upgrade.Message = err.Error()
+		return r.setUpgradePhase(logger, instance, kappnavv1.UpgradePhaseRollingBack)
+	}
+
+	desired, err := upgradeDeployments[index].reconcile(r, logger, instance)
+	if err != nil {
+		upgrade.Message = fmt.Sprintf("failed to update Deployment %s: %s", upgrade.Deployment, err)
+		return r.setUpgradePhase(logger, instance, kappnavv1.UpgradePhaseRollingBack)
+	}
+
+	state := r.getDeploymentRolloutState(desired)
+	switch {
+	case state == kappnavv1.RolloutStateComplete:
+		if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Upgraded Deployment "+upgrade.Deployment, logName)
+		}
+		if index+1 < len(upgradeDeployments) {
+			upgrade.Deployment = instance.GetName() + upgradeDeployments[index+1].suffix
+			upgrade.PhaseStartTime = metav1.Now()
+			return r.updateUpgradeStatus(logger, instance, reconcile.Result{Requeue: true})
+		}
+		return r.setUpgradePhase(logger, instance, kappnavv1.UpgradePhasePostUpgrade)
+	case state == kappnavv1.RolloutStateFailed:
+		upgrade.Message = fmt.Sprintf("Deployment %s exceeded its progress deadline", upgrade.Deployment)
+		return r.setUpgradePhase(logger, instance, kappnavv1.UpgradePhaseRollingBack)
+	case time.Since(upgrade.PhaseStartTime.Time) > upgradeRolloutTimeout:
+		upgrade.Message = fmt.Sprintf("Deployment %s did not become ready within %s", upgrade.Deployment, upgradeRolloutTimeout)
+		return r.setUpgradePhase(logger, instance, kappnavv1.UpgradePhaseRollingBack)
+	}
+	upgrade.Message = fmt.Sprintf("waiting for Deployment %s to become ready", upgrade.Deployment)
+	return r.updateUpgradeStatus(logger, instance, reconcile.Result{RequeueAfter: upgradePollInterval})
+}
+
+// rollBackDeployments restores the images recorded before the upgrade and waits
+// for the Deployments to become ready again.
+func (r *ReconcileKappnav) rollBackDeployments(logger kappnavutils.Logger, instance *kappnavv1.Kappnav) (bool, reconcile.Result, error) {
+	upgrade := instance.Status.Upgrade
+	ready := true
+	for _, d := range upgradeDeployments {
+		deployment := &appsv1.Deployment{}
+		err := r.GetClient().Get(context.TODO(), client.ObjectKey{
+			Namespace: instance.GetNamespace(),
+			Name:      instance.GetName() + d.suffix,
+		}, deployment)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			result, err := r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
+			return true, result, err
+		}
+		changed := false
+		containers := deployment.Spec.Template.Spec.Containers
+		for i := range containers {
+			image, ok := upgrade.PreviousImages[deployment.GetName()+"/"+containers[i].Name]
+			if ok && containers[i].Image != image {
+				containers[i].Image = image
+				changed = true
+			}
+		}
+		if changed {
+			if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Rolling back Deployment "+deployment.GetName(), logName)
+			}
+			if err = r.GetClient().Update(context.TODO(), deployment); err != nil {
+				result, err := r.ManageError(logger, err, kappnavv1.StatusConditionTypeReconciled, instance)
+				return true, result, err
+			}
+			ready = false
+		} else if kappnavutils.GetRolloutState(deployment) == kappnavv1.RolloutStateProgressing {
+			ready = false
+		}
+	}
+	if ready || time.Since(upgrade.PhaseStartTime.Time) > upgradeRolloutTimeout {
+		r.GetRecorder().Event(instance, "Warning", "UpgradeFailed",
+			fmt.Sprintf("Upgrade from %s to %s was rolled back: %s", upgrade.FromVersion, upgrade.ToVersion, upgrade.Message))
+		return r.setUpgradePhase(logger, instance, kappnavv1.UpgradePhaseFailed)
+	}
+	return r.updateUpgradeStatus(logger, instance, reconcile.Result{RequeueAfter: upgradePollInterval})
+}
+
+// recordPreviousImages stores the images of the existing Deployment in the upgrade status.
+func (r *ReconcileKappnav) recordPreviousImages(instance *kappnavv1.Kappnav, name string) error {
+	upgrade := instance.Status.Upgrade
+	deployment := &appsv1.Deployment{}
+	err := r.GetClient().Get(context.TODO(), client.ObjectKey{Namespace: instance.GetNamespace(), Name: name}, deployment)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if upgrade.PreviousImages == nil {
+		upgrade.PreviousImages = make(map[string]string)
+	}
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		key := name + "/" + c.Name
+		if _, ok := upgrade.PreviousImages[key]; !ok {
+			upgrade.PreviousImages[key] = c.Image
+		}
+	}
+	return nil
+}
+
+// getDeploymentRolloutState returns the rollout state of the Deployment in the cluster.
+// The state is only reported as complete once the cluster reflects the desired images.
+func (r *ReconcileKappnav) getDeploymentRolloutState(desired *appsv1.Deployment) kappnavv1.RolloutState {
+	current := &appsv1.Deployment{}
+	err := r.GetClient().Get(context.TODO(), client.ObjectKey{
+		Namespace: desired.GetNamespace(),
+		Name:      desired.GetName(),
+	}, current)
+	if err != nil {
+		return kappnavv1.RolloutStateUnknown
+	}
+	desiredContainers := desired.Spec.Template.Spec.Containers
+	currentContainers := current.Spec.Template.Spec.Containers
+	if len(desiredContainers) != len(currentContainers) {
+		return kappnavv1.RolloutStateProgressing
+	}
+	for i := range desiredContainers {
+		if desiredContainers[i].Image != currentContainers[i].Image {
+			return kappnavv1.RolloutStateProgressing
+		}
+	}
+	return kappnavutils.GetRolloutState(current)
+}
+
+// getInstalledVersion infers the kAppNav version of an installation that predates
+// version tracking from the image tag of the UI container. An empty string is
+// returned if kAppNav is not installed yet.
+func (r *ReconcileKappnav) getInstalledVersion(instance *kappnavv1.Kappnav) string {
+	// The Deployment is read from the API server, as an older installation may not
+	// have labeled it with the managed-by label of the operator
+	reader, err := r.GetAPIReader()
+	if err != nil {
+		return ""
+	}
+	deployment := &appsv1.Deployment{}
+	err = reader.Get(context.TODO(), client.ObjectKey{
+		Namespace: instance.GetNamespace(),
+		Name:      instance.GetName() + "-ui",
+	}, deployment)
+	if err != nil {
+		return ""
+	}
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		if c.Name == kappnavutils.UIContainerName {
+			if i := strings.LastIndex(c.Image, ":"); i > strings.LastIndex(c.Image, "/") && !strings.Contains(c.Image, "@") {
+				return c.Image[i+1:]
+			}
+		}
+	}
+	return "unknown"
+}
+
+func (r *ReconcileKappnav) setUpgradePhase(logger kappnavutils.Logger, instance *kappnavv1.Kappnav, phase kappnavv1.UpgradePhase) (bool, reconcile.Result, error) {
+	upgrade := instance.Status.Upgrade
+	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, fmt.Sprintf("Upgrade to %s entering phase %s", upgrade.ToVersion, phase), logName)
+	}
+	upgrade.Phase = phase
+	upgrade.PhaseStartTime = metav1.Now()
+	upgrade.ObservedGeneration = instance.GetGeneration()
+	if phase == kappnavv1.UpgradePhaseCompleted {
+		// Continue with the regular reconcile.
+		_, result, err := r.updateUpgradeStatus(logger, instance, reconcile.Result{})
+		return false, result, err
+	}
+	return r.updateUpgradeStatus(logger, instance, reconcile.Result{Requeue: true})
+}
+
+func (r *ReconcileKappnav) updateUpgradeStatus(logger kappnavutils.Logger, instance *kappnavv1.Kappnav, result reconcile.Result) (bool, reconcile.Result, error) {
+	err := r.GetClient().Status().Update(context.TODO(), instance)
+	if err != nil {
+		if logger.IsEnabled(kappnavutils.LogTypeError) {
+			logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, fmt.Sprintf("Unable to update upgrade status, Error: %s ", err), logName)
+		}
+		return true, reconcile.Result{RequeueAfter: time.Second, Requeue: true}, nil
+	}
+	return true, result, nil
+}
+
+// migrateConfigMaps adds the keys that are new in the shipped action, section and
+// status config maps. The existing keys keep their data, which users may have
+// customized, so nothing needs to be restored when the upgrade is rolled back.
+func migrateConfigMaps(r *ReconcileKappnav, logger kappnavutils.Logger, instance *kappnavv1.Kappnav) error {
+	return r.reconcileConfigMaps(logger, instance, true)
+}
+
+// migrateKAMs applies the new default KindActionMapping and rewrites the other
+// KindActionMappings in the namespace so they are stored in the current version.
+func migrateKAMs(r *ReconcileKappnav, logger kappnavutils.Logger, instance *kappnavv1.Kappnav) error {
+	defaultKAM := &kamv1.KindActionMapping{}
+	if err := kappnavutils.SetKAMDefaults(defaultKAM); err != nil {
+		return err
+	}
+	kamCR := &kamv1.KindActionMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kappnavutils.GetInstanceNames(instance).KAM,
+			Namespace: instance.GetNamespace(),
+		},
+	}
+	err := r.CreateOrUpdate(logger, kamCR, instance, func() error {
+		kappnavutils.CustomizeKAM(kamCR, defaultKAM, instance)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	kams := &kamv1.KindActionMappingList{}
+	err = r.GetClient().List(context.TODO(), &client.ListOptions{Namespace: instance.GetNamespace()}, kams)
+	if err != nil {
+		return err
+	}
+	for i := range kams.Items {
+		if kams.Items[i].GetName() == kamCR.GetName() {
+			continue
+		}
+		if err = r.GetClient().Update(context.TODO(), &kams.Items[i]); err != nil && !errors.IsConflict(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateCRDs applies the CRDs shipped in the image, replacing the ones in the cluster
+// even if they carry the same version.
+func updateCRDs(r *ReconcileKappnav, logger kappnavutils.Logger, instance *kappnavv1.Kappnav) error {
+	return r.crdManager.Apply(logger, true)
+}
+
+// verifyDeployments checks that all Deployments are ready after the upgrade.
+func verifyDeployments(r *ReconcileKappnav, logger kappnavutils.Logger, instance *kappnavv1.Kappnav) error {
+	for _, d := range upgradeDeployments {
+		deployment := &appsv1.Deployment{}
+		err := r.GetClient().Get(context.TODO(), client.ObjectKey{
+			Namespace: instance.GetNamespace(),
+			Name:      instance.GetName() + d.suffix,
+		}, deployment)
+		if err != nil {
+			return err
+		}
+		if state := kappnavutils.GetRolloutState(deployment); state != kappnavv1.RolloutStateComplete {
+			return fmt.Errorf("Deployment %s is %s", deployment.GetName(), state)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package example is an example of a kAppNav extension. It protects the UI pods
+// with a PodDisruptionBudget and mounts a ConfigMap of its own in them. It is
+// registered when the package is imported by the operator binary:
+//
+//	import _ "github.com/kappnav/operator/pkg/extensions/example"
+//
+// The role of the operator must then allow poddisruptionbudgets in the policy
+// API group.
+package example
+
+import (
+	"fmt"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// ExtensionName is the name of the extension and of its component
+	ExtensionName string = "Example"
+	// ConditionTypeBudgetHealthy enough UI pods are healthy to satisfy the budget
+	ConditionTypeBudgetHealthy kappnavv1.StatusConditionType = "ExampleBudgetHealthy"
+	// MountPath is where the ConfigMap of the extension is mounted
+	MountPath string = "/etc/kappnav/example"
+)
+
+var logName = "extension_example"
+
+func init() {
+	// The UI pods must exist before the budget is useful
+	kappnavutils.RegisterKappnavExtension(ExtensionName, &Extension{}, kappnavutils.ComponentUI)
+}
+
+// Extension implements KappnavExtension and every optional extension interface
+type Extension struct{}
+
+var (
+	_ kappnavutils.KappnavExtension   = &Extension{}
+	_ kappnavutils.WatchExtension     = &Extension{}
+	_ kappnavutils.PodExtension       = &Extension{}
+	_ kappnavutils.MapDataExtension   = &Extension{}
+	_ kappnavutils.DeletionExtension  = &Extension{}
+	_ kappnavutils.ConditionExtension = &Extension{}
+)
+
+func getConfigMapName(instance *kappnavv1.Kappnav) string {
+	return instance.GetName() + "-example"
+}
+
+func getBudgetName(instance *kappnavv1.Kappnav) string {
+	return instance.GetName() + "-ui"
+}
+
+// ReconcileAdditionalResources creates or updates the ConfigMap and the
+// PodDisruptionBudget of the extension. Both are owned by the CR, so they are
+// deleted with it.
+func (e *Extension) ReconcileAdditionalResources(logger kappnavutils.Logger, request reconcile.Request,
+	r *kappnavutils.ReconcilerBase, instance *kappnavv1.Kappnav) (reconcile.Result, error) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getConfigMapName(instance),
+			Namespace: instance.GetNamespace(),
+		},
+	}
+	err := r.CreateOrUpdate(logger, configMap, instance, func() error {
+		configMap.Labels = kappnavutils.GetLabels(instance, configMap.Labels, &configMap.ObjectMeta, "")
+		configMap.Data = map[string]string{
+			"kappnav-url": instance.Status.URL,
+		}
+		return nil
+	})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	budget := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getBudgetName(instance),
+			Namespace: instance.GetNamespace(),
+		},
+	}
+	err = r.CreateOrUpdate(logger, budget, instance, func() error {
+		budget.Labels = kappnavutils.GetLabels(instance, budget.Labels, &budget.ObjectMeta, "")
+		minAvailable := intstr.FromInt(1)
+		budget.Spec.MinAvailable = &minAvailable
+		budget.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"app.kubernetes.io/component": instance.GetName() + "-ui",
+			},
+		}
+		return nil
+	})
+	return reconcile.Result{}, err
+}
+
+// WatchedTypes returns the PodDisruptionBudget, which the operator does not watch
+func (e *Extension) WatchedTypes() []runtime.Object {
+	return []runtime.Object{&policyv1beta1.PodDisruptionBudget{}}
+}
+
+// PodContribution mounts the ConfigMap of the extension in the UI pod. The volume
+// is optional because the ConfigMap is created after the UI Deployment.
+func (e *Extension) PodContribution(instance *kappnavv1.Kappnav, target string) kappnavutils.PodContribution {
+	if target != kappnavutils.ExtensionTargetUI {
+		return kappnavutils.PodContribution{}
+	}
+	optional := true
+	return kappnavutils.PodContribution{
+		Volumes: []corev1.Volume{
+			{
+				Name: getConfigMapName(instance),
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: getConfigMapName(instance)},
+						Optional:             &optional,
+					},
+				},
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      getConfigMapName(instance),
+				MountPath: MountPath,
+				ReadOnly:  true,
+			},
+		},
+	}
+}
+
+// MapTemplateData makes the name of the budget available to the config map
+// templates as {{ .Extensions.Example.budget }}
+func (e *Extension) MapTemplateData(instance *kappnavv1.Kappnav) map[string]interface{} {
+	return map[string]interface{}{
+		"budget": getBudgetName(instance),
+	}
+}
+
+// Delete is called before the CR is deleted. The resources of the extension are
+// owned by the CR and garbage collected, so there is nothing to clean up. Cluster
+// scoped resources or resources in other namespaces would be deleted here.
+func (e *Extension) Delete(logger kappnavutils.Logger, r *kappnavutils.ReconcilerBase, instance *kappnavv1.Kappnav) error {
+	if logger.IsEnabled(kappnavutils.LogTypeInfo) {
+		logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeInfo, "Nothing to clean up for "+instance.GetNamespace()+"/"+instance.GetName(), logName)
+	}
+	return nil
+}
+
+// Conditions reports whether enough UI pods are healthy to satisfy the budget
+func (e *Extension) Conditions(logger kappnavutils.Logger, r *kappnavutils.ReconcilerBase, instance *kappnavv1.Kappnav) []kappnavv1.StatusCondition {
+	budget := &policyv1beta1.PodDisruptionBudget{}
+	err := r.GetClient().Get(logger.Context(), client.ObjectKey{
+		Namespace: instance.GetNamespace(),
+		Name:      getBudgetName(instance),
+	}, budget)
+	condition := kappnavv1.StatusCondition{Type: ConditionTypeBudgetHealthy}
+	switch {
+	case err != nil:
+		condition.Status = corev1.ConditionUnknown
+		condition.Reason = "BudgetUnavailable"
+		condition.Message = err.Error()
+	case budget.Status.CurrentHealthy < budget.Status.DesiredHealthy:
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "InsufficientPods"
+		condition.Message = fmt.Sprintf("%d of %d UI pods are healthy", budget.Status.CurrentHealthy, budget.Status.DesiredHealthy)
+	default:
+		condition.Status = corev1.ConditionTrue
+	}
+	return []kappnavv1.StatusCondition{condition}
+}
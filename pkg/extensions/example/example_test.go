@@ -0,0 +1,171 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package example
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	if err := kappnavv1.SchemeBuilder.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
+}
+
+func newTestInstance() *kappnavv1.Kappnav {
+	return &kappnavv1.Kappnav{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "kappnav.operator.kappnav.io/v1", Kind: "Kappnav"},
+		ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "kappnav", UID: "1234"},
+		Status:     kappnavv1.KappnavStatus{URL: "https://kappnav.example.com"},
+	}
+}
+
+func newTestReconciler(objects ...runtime.Object) *kappnavutils.ReconcilerBase {
+	r := kappnavutils.NewReconcilerBase(fake.NewFakeClient(objects...), scheme.Scheme, nil, record.NewFakeRecorder(10))
+	return &r
+}
+
+func TestRegistered(t *testing.T) {
+	for _, component := range kappnavutils.GetRegisteredComponents() {
+		if component.Name() != ExtensionName {
+			continue
+		}
+		if dependsOn := component.DependsOn(); !reflect.DeepEqual(dependsOn, []string{kappnavutils.ComponentUI}) {
+			t.Errorf("got dependencies %v, want [%s]", dependsOn, kappnavutils.ComponentUI)
+		}
+		return
+	}
+	t.Errorf("the component %s is not registered", ExtensionName)
+}
+
+func TestReconcileAdditionalResources(t *testing.T) {
+	instance := newTestInstance()
+	r := newTestReconciler()
+	extension := &Extension{}
+	request := reconcile.Request{NamespacedName: client.ObjectKey{Namespace: "kappnav", Name: "instance"}}
+	if _, err := extension.ReconcileAdditionalResources(kappnavutils.NewLogger(), request, r, instance); err != nil {
+		t.Fatalf("ReconcileAdditionalResources failed: %s", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.GetClient().Get(context.Background(), client.ObjectKey{Namespace: "kappnav", Name: "instance-example"}, configMap); err != nil {
+		t.Fatalf("failed to get the ConfigMap: %s", err)
+	}
+	if url := configMap.Data["kappnav-url"]; url != instance.Status.URL {
+		t.Errorf("got kappnav-url %s, want %s", url, instance.Status.URL)
+	}
+	if !metav1.IsControlledBy(configMap, instance) {
+		t.Errorf("the ConfigMap is not owned by the CR: %+v", configMap.OwnerReferences)
+	}
+
+	budget := &policyv1beta1.PodDisruptionBudget{}
+	if err := r.GetClient().Get(context.Background(), client.ObjectKey{Namespace: "kappnav", Name: "instance-ui"}, budget); err != nil {
+		t.Fatalf("failed to get the PodDisruptionBudget: %s", err)
+	}
+	if budget.Spec.MinAvailable == nil || budget.Spec.MinAvailable.IntValue() != 1 {
+		t.Errorf("got minAvailable %v, want 1", budget.Spec.MinAvailable)
+	}
+	if component := budget.Spec.Selector.MatchLabels["app.kubernetes.io/component"]; component != "instance-ui" {
+		t.Errorf("got selected component %s, want instance-ui", component)
+	}
+}
+
+func TestPodContribution(t *testing.T) {
+	instance := newTestInstance()
+	extension := &Extension{}
+
+	if contribution := extension.PodContribution(instance, kappnavutils.ExtensionTargetController); !reflect.DeepEqual(contribution, kappnavutils.PodContribution{}) {
+		t.Errorf("got contribution %+v to the controller pod, want none", contribution)
+	}
+
+	contribution := extension.PodContribution(instance, kappnavutils.ExtensionTargetUI)
+	if len(contribution.Containers) != 0 {
+		t.Errorf("got %d containers, want none", len(contribution.Containers))
+	}
+	if len(contribution.Volumes) != 1 || contribution.Volumes[0].ConfigMap == nil ||
+		contribution.Volumes[0].ConfigMap.Name != "instance-example" || !*contribution.Volumes[0].ConfigMap.Optional {
+		t.Errorf("got volumes %+v, want the optional instance-example ConfigMap", contribution.Volumes)
+	}
+	if len(contribution.VolumeMounts) != 1 || contribution.VolumeMounts[0].MountPath != MountPath {
+		t.Errorf("got volume mounts %+v, want a mount at %s", contribution.VolumeMounts, MountPath)
+	}
+}
+
+func TestMapTemplateData(t *testing.T) {
+	data := (&Extension{}).MapTemplateData(newTestInstance())
+	if budget := data["budget"]; budget != "instance-ui" {
+		t.Errorf("got budget %v, want instance-ui", budget)
+	}
+}
+
+func TestWatchedTypes(t *testing.T) {
+	types := (&Extension{}).WatchedTypes()
+	if len(types) != 1 {
+		t.Fatalf("got %d watched types, want 1", len(types))
+	}
+	if _, ok := types[0].(*policyv1beta1.PodDisruptionBudget); !ok {
+		t.Errorf("got watched type %T, want a PodDisruptionBudget", types[0])
+	}
+}
+
+func TestDelete(t *testing.T) {
+	if err := (&Extension{}).Delete(kappnavutils.NewLogger(), newTestReconciler(), newTestInstance()); err != nil {
+		t.Errorf("Delete failed: %s", err)
+	}
+}
+
+func TestConditions(t *testing.T) {
+	newBudget := func(currentHealthy int32, desiredHealthy int32) *policyv1beta1.PodDisruptionBudget {
+		return &policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "instance-ui", Namespace: "kappnav"},
+			Status:     policyv1beta1.PodDisruptionBudgetStatus{CurrentHealthy: currentHealthy, DesiredHealthy: desiredHealthy},
+		}
+	}
+	tests := []struct {
+		name       string
+		objects    []runtime.Object
+		wantStatus corev1.ConditionStatus
+		wantReason string
+	}{
+		{name: "no budget", wantStatus: corev1.ConditionUnknown, wantReason: "BudgetUnavailable"},
+		{name: "insufficient pods", objects: []runtime.Object{newBudget(0, 1)}, wantStatus: corev1.ConditionFalse, wantReason: "InsufficientPods"},
+		{name: "healthy", objects: []runtime.Object{newBudget(2, 1)}, wantStatus: corev1.ConditionTrue},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conditions := (&Extension{}).Conditions(kappnavutils.NewLogger(), newTestReconciler(test.objects...), newTestInstance())
+			if len(conditions) != 1 {
+				t.Fatalf("got %d conditions, want 1", len(conditions))
+			}
+			condition := conditions[0]
+			if condition.Type != ConditionTypeBudgetHealthy || condition.Status != test.wantStatus || condition.Reason != test.wantReason {
+				t.Errorf("got condition %+v, want status %s and reason %q", condition, test.wantStatus, test.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kam
+
+import (
+	"context"
+
+	kamv1 "github.com/kappnav/operator/pkg/apis/actions/v1"
+	kamv2 "github.com/kappnav/operator/pkg/apis/actions/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ListKindActionMappings lists the KindActionMappings in namespace, or in all
+// namespaces if namespace is empty, and converts them to v2. They are read as v1,
+// the storage version, so that the conversion webhook is not needed.
+func ListKindActionMappings(c client.Client, namespace string) ([]kamv2.KindActionMapping, error) {
+	list := &kamv1.KindActionMappingList{}
+	if err := c.List(context.TODO(), &client.ListOptions{Namespace: namespace}, list); err != nil {
+		return nil, err
+	}
+	return ConvertV1(list.Items)
+}
+
+// GetResource gets the resource namespace/name of the given apiVersion and kind,
+// together with the labels of its namespace.
+func GetResource(c client.Client, apiVersion string, kind string, namespace string, name string) (*Resource, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		return nil, err
+	}
+	var namespaceLabels map[string]string
+	if len(namespace) > 0 {
+		ns := &corev1.Namespace{}
+		if err := c.Get(context.TODO(), client.ObjectKey{Name: namespace}, ns); err != nil {
+			return nil, err
+		}
+		namespaceLabels = ns.Labels
+	}
+	return NewResource(obj, apiVersion, kind, namespaceLabels), nil
+}
+
+// ConfigMapLookupFor returns a ConfigMapLookup that gets the config maps with c.
+func ConfigMapLookupFor(c client.Client) ConfigMapLookup {
+	return func(namespace string, name string) (bool, error) {
+		err := c.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: name}, &corev1.ConfigMap{})
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kam
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	kamv2 "github.com/kappnav/operator/pkg/apis/actions/v2"
+)
+
+// MappingResult is the outcome of matching a single mapping against a resource
+type MappingResult struct {
+	// KAM is the namespace/name of the KindActionMapping of the mapping
+	KAM string
+	// Index is the index of the mapping in the KindActionMapping
+	Index int
+	// Mapping is the mapping
+	Mapping kamv2.MappingConfiguration
+	// Precedence is the precedence of the KindActionMapping
+	Precedence int
+	// Mismatch is the first field of the mapping that the resource does not match,
+	// empty if the mapping applies to the resource
+	Mismatch string
+	// Err is the error in the mapping, such as an invalid pattern or selector
+	Err error
+}
+
+// Matched returns whether the mapping applies to the resource
+func (r *MappingResult) Matched() bool {
+	return len(r.Mismatch) == 0 && r.Err == nil
+}
+
+// ConfigMapStatus tells whether the action config map of a candidate exists
+type ConfigMapStatus struct {
+	Candidate
+	// Exists is true if the config map exists in one of the searched namespaces
+	Exists bool
+	// Namespace is the first searched namespace that has the config map
+	Namespace string
+}
+
+// Explanation describes how the action config maps of a resource are resolved
+type Explanation struct {
+	Resource *Resource
+	// Namespaces are the namespaces searched for the action config maps, in order
+	Namespaces []string
+	// Mappings are the results of all mappings of all KindActionMappings
+	Mappings []MappingResult
+	// ConfigMaps are the candidate action config maps in the order of Resolve
+	ConfigMaps []ConfigMapStatus
+}
+
+// ConfigMapLookup returns whether the config map namespace/name exists
+type ConfigMapLookup func(namespace string, name string) (bool, error)
+
+// Explain matches every mapping of kams against resource and looks up each
+// resolved candidate in namespaces, in order. Unlike Resolve, it does not fail
+// on invalid mappings but records the error in their MappingResult.
+func Explain(resource *Resource, kams []kamv2.KindActionMapping, namespaces []string, lookup ConfigMapLookup) (*Explanation, error) {
+	explanation := &Explanation{Resource: resource, Namespaces: namespaces}
+	var candidates []Candidate
+	for i := range kams {
+		kam := &kams[i]
+		for j := range kam.Spec.Mappings {
+			mapping := &kam.Spec.Mappings[j]
+			result := MappingResult{
+				KAM:        kam.Namespace + "/" + kam.Name,
+				Index:      j,
+				Mapping:    *mapping.DeepCopy(),
+				Precedence: kam.Spec.Precedence,
+			}
+			result.Mismatch, result.Err = match(mapping, resource)
+			explanation.Mappings = append(explanation.Mappings, result)
+			if result.Matched() {
+				candidates = append(candidates, Candidate{
+					Mapname:    Mapname(mapping.Mapname, resource),
+					KAM:        result.KAM,
+					Index:      j,
+					Mapping:    result.Mapping,
+					Precedence: result.Precedence,
+				})
+			}
+		}
+	}
+
+	for _, candidate := range orderCandidates(candidates) {
+		status := ConfigMapStatus{Candidate: candidate}
+		for _, namespace := range namespaces {
+			exists, err := lookup(namespace, candidate.Mapname)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get config map %s/%s: %v", namespace, candidate.Mapname, err)
+			}
+			if exists {
+				status.Exists = true
+				status.Namespace = namespace
+				break
+			}
+		}
+		explanation.ConfigMaps = append(explanation.ConfigMaps, status)
+	}
+	return explanation, nil
+}
+
+// Write prints the explanation in a human readable form
+func (e *Explanation) Write(w io.Writer) error {
+	r := e.Resource
+	fmt.Fprintf(w, "Resource: %s %s %s/%s", r.APIVersion, r.Kind, r.Namespace, r.Name)
+	if len(r.Subkind) > 0 {
+		fmt.Fprintf(w, " (subkind %s)", r.Subkind)
+	}
+	fmt.Fprintf(w, "\n\nMappings:\n")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "  KAM\tPRECEDENCE\tPRIORITY\tMAPNAME\tRESULT\n")
+	for i := range e.Mappings {
+		m := &e.Mappings[i]
+		result := "matched"
+		switch {
+		case m.Err != nil:
+			result = "invalid: " + m.Err.Error()
+		case !m.Matched():
+			result = "no match on " + m.Mismatch
+		}
+		fmt.Fprintf(tw, "  %s[%d]\t%d\t%d\t%s\t%s\n", m.KAM, m.Index, m.Precedence, m.Mapping.Priority, m.Mapping.Mapname, result)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nAction config maps, in order, searched in %v:\n", e.Namespaces)
+	if len(e.ConfigMaps) == 0 {
+		fmt.Fprintf(w, "  none, no mapping applies to the resource\n")
+		return nil
+	}
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for i := range e.ConfigMaps {
+		c := &e.ConfigMaps[i]
+		found := "missing"
+		if c.Exists {
+			found = "found in " + c.Namespace
+		}
+		fmt.Fprintf(tw, "  %d.\t%s\t%s\tfrom %s[%d]\n", i+1, c.Mapname, found, c.KAM, c.Index)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kam
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	kamv1 "github.com/kappnav/operator/pkg/apis/actions/v1"
+	kamv2 "github.com/kappnav/operator/pkg/apis/actions/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestResource(apiVersion string, kind string, name string, labels map[string]string, subkind string) *Resource {
+	obj := &metav1.ObjectMeta{Name: name, Namespace: "payments", Labels: labels}
+	if len(subkind) > 0 {
+		obj.Annotations = map[string]string{SubkindAnnotation: subkind}
+	}
+	return NewResource(obj, apiVersion, kind, map[string]string{"env": "prod"})
+}
+
+func getMapnames(candidates []Candidate) []string {
+	var mapnames []string
+	for _, candidate := range candidates {
+		mapnames = append(mapnames, candidate.Mapname)
+	}
+	return mapnames
+}
+
+// v1KAMs are the default mappings of kAppNav and the mappings of a product with a
+// higher precedence
+var v1KAMs = []kamv1.KindActionMapping{
+	{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "kappnav"},
+		Spec: kamv1.KindActionMappingSpec{
+			Precedence: 1,
+			Mappings: []kamv1.MappingConfiguration{
+				{APIVersion: "apps/v1", Kind: "Deployment", Mapname: "kappnav.actions.${kind}"},
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "${name}", Mapname: "${namespace}.actions.${kind}-${name}"},
+				{APIVersion: "v1", Kind: "Service", Mapname: "kappnav.actions.${kind}"},
+				{APIVersion: "apps/v1", Kind: "Deployment", Subkind: "Liberty", Mapname: "kappnav.actions.${kind}.${subkind}"},
+			},
+		},
+	},
+	{
+		ObjectMeta: metav1.ObjectMeta{Name: "product", Namespace: "kappnav"},
+		Spec: kamv1.KindActionMappingSpec{
+			Precedence: 5,
+			Mappings: []kamv1.MappingConfiguration{
+				{APIVersion: "apps/v1", Kind: "Deployment", Mapname: "product.actions.${kind}"},
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "payments-*", Mapname: "product.actions.payments"},
+			},
+		},
+	},
+}
+
+func TestResolveV1(t *testing.T) {
+	kams, err := ConvertV1(v1KAMs)
+	if err != nil {
+		t.Fatalf("ConvertV1 failed: %s", err)
+	}
+	tests := []struct {
+		name     string
+		resource *Resource
+		want     []string
+	}{
+		{
+			name:     "kind only, by precedence",
+			resource: newTestResource("apps/v1", "Deployment", "orders", nil, ""),
+			want:     []string{"product.actions.deployment", "kappnav.actions.deployment"},
+		},
+		{
+			name:     "name glob before kind only",
+			resource: newTestResource("apps/v1", "Deployment", "payments-api", nil, ""),
+			want:     []string{"product.actions.payments", "product.actions.deployment", "kappnav.actions.deployment"},
+		},
+		{
+			name:     "subkind before name before kind",
+			resource: newTestResource("apps/v1", "Deployment", "payments-api", nil, "Liberty"),
+			want: []string{"kappnav.actions.deployment.liberty", "product.actions.payments",
+				"product.actions.deployment", "kappnav.actions.deployment"},
+		},
+		{
+			name:     "other kind",
+			resource: newTestResource("v1", "Service", "payments-api", nil, ""),
+			want:     []string{"kappnav.actions.service"},
+		},
+		{
+			name:     "other apiVersion",
+			resource: newTestResource("extensions/v1beta1", "Deployment", "orders", nil, ""),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			candidates, err := Resolve(test.resource, kams)
+			if err != nil {
+				t.Fatalf("Resolve failed: %s", err)
+			}
+			if mapnames := getMapnames(candidates); !reflect.DeepEqual(mapnames, test.want) {
+				t.Errorf("got %v, want %v", mapnames, test.want)
+			}
+		})
+	}
+}
+
+// v2KAMs use the globs, regular expressions, selectors and priorities of v2
+var v2KAMs = []kamv2.KindActionMapping{
+	{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "kappnav"},
+		Spec: kamv2.KindActionMappingSpec{
+			Precedence: 1,
+			Mappings: []kamv2.MappingConfiguration{
+				{APIVersion: "apps/*", Kind: "*", Mapname: "kappnav.actions.${kind}"},
+				{APIVersion: "*/*", Kind: "Deployment", NameRegex: "payments-[0-9]+", Mapname: "kappnav.actions.payments-numbered"},
+			},
+		},
+	},
+	{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "payments"},
+		Spec: kamv2.KindActionMappingSpec{
+			Precedence: 3,
+			Mappings: []kamv2.MappingConfiguration{
+				{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+					Mapname:    "team.actions.payments",
+				},
+				{
+					APIVersion:        "apps/v1",
+					Kind:              "Deployment",
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+					Priority:          10,
+					Mapname:           "team.actions.prod",
+				},
+				{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"team": "orders"}},
+					Mapname:    "team.actions.orders",
+				},
+			},
+		},
+	},
+}
+
+func TestResolveV2(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource *Resource
+		want     []string
+	}{
+		{
+			name:     "priority before name regex before precedence",
+			resource: newTestResource("apps/v1", "Deployment", "payments-42", map[string]string{"team": "payments"}, ""),
+			want: []string{"team.actions.prod", "kappnav.actions.payments-numbered",
+				"team.actions.payments", "kappnav.actions.deployment"},
+		},
+		{
+			name:     "name regex matches the whole name",
+			resource: newTestResource("apps/v1", "Deployment", "payments-42-canary", nil, ""),
+			want:     []string{"team.actions.prod", "kappnav.actions.deployment"},
+		},
+		{
+			name:     "apiVersion glob",
+			resource: newTestResource("apps/v1", "StatefulSet", "payments", nil, ""),
+			want:     []string{"kappnav.actions.statefulset"},
+		},
+		{
+			name:     "core apiVersion does not match a group glob",
+			resource: newTestResource("v1", "Service", "payments", nil, ""),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			candidates, err := Resolve(test.resource, v2KAMs)
+			if err != nil {
+				t.Fatalf("Resolve failed: %s", err)
+			}
+			if mapnames := getMapnames(candidates); !reflect.DeepEqual(mapnames, test.want) {
+				t.Errorf("got %v, want %v", mapnames, test.want)
+			}
+		})
+	}
+}
+
+func TestResolveInvalidMapping(t *testing.T) {
+	kams := []kamv2.KindActionMapping{{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "kappnav"},
+		Spec: kamv2.KindActionMappingSpec{Mappings: []kamv2.MappingConfiguration{
+			{APIVersion: "apps/v1", Kind: "Deployment", NameRegex: "payments-[", Mapname: "invalid"},
+		}},
+	}}
+	if _, err := Resolve(newTestResource("apps/v1", "Deployment", "payments", nil, ""), kams); err == nil {
+		t.Error("Resolve accepted an invalid nameRegex")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	kams := append([]kamv2.KindActionMapping{{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "kappnav"},
+		Spec: kamv2.KindActionMappingSpec{Mappings: []kamv2.MappingConfiguration{
+			{APIVersion: "apps/v1", Kind: "Deployment", NameRegex: "payments-[", Mapname: "invalid"},
+		}},
+	}}, v2KAMs...)
+	existing := map[string]bool{
+		"payments/team.actions.payments":     true,
+		"kappnav/team.actions.payments":      true,
+		"kappnav/kappnav.actions.deployment": true,
+	}
+	lookup := func(namespace string, name string) (bool, error) {
+		return existing[namespace+"/"+name], nil
+	}
+	resource := newTestResource("apps/v1", "Deployment", "payments-api", map[string]string{"team": "payments"}, "")
+	explanation, err := Explain(resource, kams, []string{"payments", "kappnav"}, lookup)
+	if err != nil {
+		t.Fatalf("Explain failed: %s", err)
+	}
+
+	wantMappings := []struct {
+		kam      string
+		mismatch string
+		invalid  bool
+	}{
+		{kam: "kappnav/invalid", mismatch: "nameRegex", invalid: true},
+		{kam: "kappnav/default"},
+		{kam: "kappnav/default", mismatch: "nameRegex"},
+		{kam: "payments/team"},
+		{kam: "payments/team"},
+		{kam: "payments/team", mismatch: "selector"},
+	}
+	if len(explanation.Mappings) != len(wantMappings) {
+		t.Fatalf("got %d mapping results, want %d", len(explanation.Mappings), len(wantMappings))
+	}
+	for i, want := range wantMappings {
+		result := explanation.Mappings[i]
+		if result.KAM != want.kam || result.Mismatch != want.mismatch || (result.Err != nil) != want.invalid {
+			t.Errorf("mapping %d: got %s, mismatch %q, error %v, want %s, mismatch %q, invalid %t",
+				i, result.KAM, result.Mismatch, result.Err, want.kam, want.mismatch, want.invalid)
+		}
+	}
+
+	wantConfigMaps := []ConfigMapStatus{
+		{Candidate: Candidate{Mapname: "team.actions.prod"}},
+		{Candidate: Candidate{Mapname: "team.actions.payments"}, Exists: true, Namespace: "payments"},
+		{Candidate: Candidate{Mapname: "kappnav.actions.deployment"}, Exists: true, Namespace: "kappnav"},
+	}
+	if len(explanation.ConfigMaps) != len(wantConfigMaps) {
+		t.Fatalf("got config maps %+v, want %d", explanation.ConfigMaps, len(wantConfigMaps))
+	}
+	for i, want := range wantConfigMaps {
+		got := explanation.ConfigMaps[i]
+		if got.Mapname != want.Mapname || got.Exists != want.Exists || got.Namespace != want.Namespace {
+			t.Errorf("config map %d: got %s, exists %t in %q, want %s, exists %t in %q",
+				i, got.Mapname, got.Exists, got.Namespace, want.Mapname, want.Exists, want.Namespace)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := explanation.Write(&out); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	for _, want := range []string{"no match on selector", "invalid: invalid nameRegex", "missing", "found in payments", "from payments/team[1]"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("the explanation does not contain %q:\n%s", want, out.String())
+		}
+	}
+}
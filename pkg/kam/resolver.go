@@ -0,0 +1,265 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kam resolves the action config maps that KindActionMappings map a
+// resource to. It implements the v2 KindActionMapping semantics and accepts v1
+// KindActionMappings, which are converted to v2 first.
+package kam
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	kamv1 "github.com/kappnav/operator/pkg/apis/actions/v1"
+	kamv2 "github.com/kappnav/operator/pkg/apis/actions/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// SubkindAnnotation is the annotation holding the subkind of a resource
+const SubkindAnnotation string = "kappnav.subkind"
+
+// Resource describes the resource whose action config maps are resolved
+type Resource struct {
+	APIVersion      string
+	Kind            string
+	Subkind         string
+	Name            string
+	Namespace       string
+	Labels          map[string]string
+	Annotations     map[string]string
+	NamespaceLabels map[string]string
+	OwnerReferences []metav1.OwnerReference
+}
+
+// NewResource describes obj, whose type is given by apiVersion and kind.
+// namespaceLabels are the labels of the namespace of obj, which are only used
+// by mappings with a namespace selector.
+func NewResource(obj metav1.Object, apiVersion string, kind string, namespaceLabels map[string]string) *Resource {
+	return &Resource{
+		APIVersion:      apiVersion,
+		Kind:            kind,
+		Subkind:         obj.GetAnnotations()[SubkindAnnotation],
+		Name:            obj.GetName(),
+		Namespace:       obj.GetNamespace(),
+		Labels:          obj.GetLabels(),
+		Annotations:     obj.GetAnnotations(),
+		NamespaceLabels: namespaceLabels,
+		OwnerReferences: obj.GetOwnerReferences(),
+	}
+}
+
+// Candidate is an action config map name that a mapping resolved for a resource
+type Candidate struct {
+	// Mapname is the name of the action config map with the variables substituted
+	Mapname string
+	// KAM is the namespace/name of the KindActionMapping of the mapping
+	KAM string
+	// Index is the index of the mapping in the KindActionMapping
+	Index int
+	// Mapping is the mapping that matched the resource
+	Mapping kamv2.MappingConfiguration
+	// Precedence is the precedence of the KindActionMapping
+	Precedence int
+}
+
+// ConvertV1 converts v1 KindActionMappings to v2.
+func ConvertV1(kams []kamv1.KindActionMapping) ([]kamv2.KindActionMapping, error) {
+	var converted []kamv2.KindActionMapping
+	for i := range kams {
+		kam := kamv2.KindActionMapping{}
+		if err := kamv2.ConvertFromV1(&kams[i], &kam); err != nil {
+			return nil, fmt.Errorf("cannot convert KindActionMapping %s/%s: %v", kams[i].Namespace, kams[i].Name, err)
+		}
+		converted = append(converted, kam)
+	}
+	return converted, nil
+}
+
+// Resolve returns the action config map names that kams map resource to, in the
+// order in which they are used. The candidates are ordered by the priority of
+// their mapping, then by specificity (subkind and name, subkind, name, kind only),
+// then by the precedence of their KindActionMapping, and finally by their order in
+// the KindActionMappings. Each config map name is returned once.
+func Resolve(resource *Resource, kams []kamv2.KindActionMapping) ([]Candidate, error) {
+	var candidates []Candidate
+	for i := range kams {
+		kam := &kams[i]
+		for j := range kam.Spec.Mappings {
+			mapping := &kam.Spec.Mappings[j]
+			matched, err := Matches(mapping, resource)
+			if err != nil {
+				return nil, fmt.Errorf("mapping %d of KindActionMapping %s/%s: %v", j, kam.Namespace, kam.Name, err)
+			}
+			if !matched {
+				continue
+			}
+			candidates = append(candidates, Candidate{
+				Mapname:    Mapname(mapping.Mapname, resource),
+				KAM:        kam.Namespace + "/" + kam.Name,
+				Index:      j,
+				Mapping:    *mapping.DeepCopy(),
+				Precedence: kam.Spec.Precedence,
+			})
+		}
+	}
+	return orderCandidates(candidates), nil
+}
+
+// orderCandidates sorts candidates in the order documented by Resolve and drops
+// the candidates whose config map name is already used by an earlier candidate.
+func orderCandidates(candidates []Candidate) []Candidate {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := &candidates[i], &candidates[j]
+		if a.Mapping.Priority != b.Mapping.Priority {
+			return a.Mapping.Priority > b.Mapping.Priority
+		}
+		if specificity(&a.Mapping) != specificity(&b.Mapping) {
+			return specificity(&a.Mapping) > specificity(&b.Mapping)
+		}
+		if a.Precedence != b.Precedence {
+			return a.Precedence > b.Precedence
+		}
+		if a.KAM != b.KAM {
+			return a.KAM < b.KAM
+		}
+		return a.Index < b.Index
+	})
+
+	seen := make(map[string]bool)
+	ordered := candidates[:0]
+	for _, candidate := range candidates {
+		if !seen[candidate.Mapname] {
+			seen[candidate.Mapname] = true
+			ordered = append(ordered, candidate)
+		}
+	}
+	return ordered
+}
+
+// Matches returns whether mapping applies to resource. A mapping with a subkind
+// only applies to resources that have a subkind.
+func Matches(mapping *kamv2.MappingConfiguration, resource *Resource) (bool, error) {
+	mismatch, err := match(mapping, resource)
+	return len(mismatch) == 0 && err == nil, err
+}
+
+// match returns the first field of mapping that resource does not match, or an
+// empty string if mapping applies to resource.
+func match(mapping *kamv2.MappingConfiguration, resource *Resource) (string, error) {
+	if ok, err := matchGlob(mapping.APIVersion, resource.APIVersion); !ok || err != nil {
+		return "apiVersion", err
+	}
+	if ok, err := matchGlob(mapping.Kind, resource.Kind); !ok || err != nil {
+		return "kind", err
+	}
+	if len(mapping.Subkind) > 0 {
+		if len(resource.Subkind) == 0 {
+			return "subkind", nil
+		}
+		if ok, err := matchGlob(mapping.Subkind, resource.Subkind); !ok || err != nil {
+			return "subkind", err
+		}
+	}
+	if len(mapping.Name) > 0 {
+		if ok, err := matchGlob(mapping.Name, resource.Name); !ok || err != nil {
+			return "name", err
+		}
+	}
+	if len(mapping.NameRegex) > 0 {
+		re, err := regexp.Compile("^(?:" + mapping.NameRegex + ")$")
+		if err != nil {
+			return "nameRegex", fmt.Errorf("invalid nameRegex %q: %v", mapping.NameRegex, err)
+		}
+		if !re.MatchString(resource.Name) {
+			return "nameRegex", nil
+		}
+	}
+	if len(mapping.Owner) > 0 || len(mapping.OwnerAPI) > 0 || len(mapping.OwnerUID) > 0 {
+		if !matchOwner(mapping, resource.OwnerReferences) {
+			return "owner", nil
+		}
+	}
+	if ok, err := matchSelector("selector", mapping.Selector, resource.Labels); !ok || err != nil {
+		return "selector", err
+	}
+	if ok, err := matchSelector("annotationSelector", mapping.AnnotationSelector, resource.Annotations); !ok || err != nil {
+		return "annotationSelector", err
+	}
+	if ok, err := matchSelector("namespaceSelector", mapping.NamespaceSelector, resource.NamespaceLabels); !ok || err != nil {
+		return "namespaceSelector", err
+	}
+	return "", nil
+}
+
+// Mapname substitutes ${namespace}, ${kind}, ${subkind} and ${name} in mapname.
+// Kinds and subkinds are lower case in config map names.
+func Mapname(mapname string, resource *Resource) string {
+	return strings.NewReplacer(
+		"${namespace}", resource.Namespace,
+		"${kind}", strings.ToLower(resource.Kind),
+		"${subkind}", strings.ToLower(resource.Subkind),
+		"${name}", resource.Name,
+	).Replace(mapname)
+}
+
+// specificity ranks mappings that select a subkind above mappings that select
+// a name, which are ranked above mappings that select a kind only.
+func specificity(mapping *kamv2.MappingConfiguration) int {
+	specificity := 0
+	if len(mapping.Subkind) > 0 {
+		specificity += 2
+	}
+	if len(mapping.Name) > 0 || len(mapping.NameRegex) > 0 {
+		specificity++
+	}
+	return specificity
+}
+
+func matchGlob(pattern string, value string) (bool, error) {
+	matched, err := path.Match(pattern, value)
+	if err != nil {
+		return false, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+	}
+	return matched, nil
+}
+
+func matchOwner(mapping *kamv2.MappingConfiguration, owners []metav1.OwnerReference) bool {
+	for _, owner := range owners {
+		if len(mapping.Owner) > 0 && mapping.Owner != owner.Kind {
+			continue
+		}
+		if len(mapping.OwnerAPI) > 0 && mapping.OwnerAPI != owner.APIVersion {
+			continue
+		}
+		if len(mapping.OwnerUID) > 0 && mapping.OwnerUID != string(owner.UID) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func matchSelector(field string, selector *metav1.LabelSelector, values map[string]string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %v", field, err)
+	}
+	return s.Matches(labels.Set(values)), nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"github.com/robertkrimen/otto/ast"
+	"github.com/robertkrimen/otto/parser"
+)
+
+// StatusFunction is the function a status algorithm must declare
+const StatusFunction string = "getStatus"
+
+// ParseJavaScript parses JavaScript source and returns the names of the
+// functions it declares at the top level.
+func ParseJavaScript(name string, source string) ([]string, error) {
+	program, err := parser.ParseFile(nil, name, source, 0)
+	if err != nil {
+		return nil, err
+	}
+	var functions []string
+	for _, declaration := range program.DeclarationList {
+		if function, ok := declaration.(*ast.FunctionDeclaration); ok && function.Function.Name != nil {
+			functions = append(functions, function.Function.Name.Name)
+		}
+	}
+	return functions, nil
+}
+
+// checkSnippet checks that a snippet declares a single function
+func (l *linter) checkSnippet(key string, path string, source string) {
+	functions, err := ParseJavaScript(l.configMap.GetName()+":"+key+path, source)
+	if err != nil {
+		l.errorf(key, path, "JavaScript syntax error: %v", err)
+		return
+	}
+	if len(functions) != 1 {
+		l.errorf(key, path, "a snippet must declare exactly one function, found %d", len(functions))
+	}
+}
+
+// checkAlgorithm checks that a status algorithm declares the StatusFunction
+func (l *linter) checkAlgorithm(key string, source string) {
+	functions, err := ParseJavaScript(l.configMap.GetName()+":"+key, source)
+	if err != nil {
+		l.errorf(key, "", "JavaScript syntax error: %v", err)
+		return
+	}
+	for _, function := range functions {
+		if function == StatusFunction {
+			return
+		}
+	}
+	l.errorf(key, "", "the algorithm must declare a function %s(status)", StatusFunction)
+}
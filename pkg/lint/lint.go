@@ -0,0 +1,381 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lint checks the content of the kAppNav action, section and status
+// config maps. The JSON documents embedded in the maps are validated against the
+// structure expected by the kAppNav components, the ${...} references of the
+// url and command patterns are checked, and the JavaScript of snippets and status
+// algorithms is parsed.
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// MapTypeLabel is the label holding the type of a kAppNav config map
+const MapTypeLabel string = "kappnav.io/map-type"
+
+// MapType is the type of a kAppNav config map
+type MapType string
+
+const (
+	// MapTypeAction maps hold url-actions, cmd-actions, variables and snippets
+	MapTypeAction MapType = "action"
+	// MapTypeSections maps hold sections and section-datasources
+	MapTypeSections MapType = "sections"
+	// MapTypeStatus maps hold a status algorithm or fixed status values
+	MapTypeStatus MapType = "status"
+)
+
+// Severity ...
+type Severity string
+
+const (
+	// SeverityError the map does not work as intended
+	SeverityError Severity = "Error"
+	// SeverityWarning the map works but is likely wrong
+	SeverityWarning Severity = "Warning"
+)
+
+// Issue is a problem found in a config map
+type Issue struct {
+	// Map is the name of the config map
+	Map string
+	// Key is the key of the data section of the config map
+	Key string
+	// Path locates the problem in the JSON document of the key, e.g. [2].url-pattern
+	Path     string
+	Severity Severity
+	Message  string
+}
+
+func (i Issue) String() string {
+	location := i.Map
+	if len(i.Key) > 0 {
+		location += ":" + i.Key + i.Path
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Severity, location, i.Message)
+}
+
+// HasErrors returns whether any of the issues is an error
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+var nlsKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)+$`)
+
+// GetMapType returns the type of a config map from its MapTypeLabel, or from its
+// name when the label is not set. It returns an empty MapType for other maps.
+func GetMapType(configMap *corev1.ConfigMap) MapType {
+	switch MapType(configMap.Labels[MapTypeLabel]) {
+	case MapTypeAction:
+		return MapTypeAction
+	case MapTypeSections:
+		return MapTypeSections
+	case MapTypeStatus:
+		return MapTypeStatus
+	}
+	name := configMap.GetName()
+	switch {
+	case strings.Contains(name, ".actions."):
+		return MapTypeAction
+	case strings.Contains(name, ".sections."):
+		return MapTypeSections
+	case strings.Contains(name, ".status-mapping"):
+		return MapTypeStatus
+	}
+	return ""
+}
+
+// ConfigMap checks a kAppNav config map and returns the issues found, ordered by
+// key. Maps that are not action, section or status maps have no issues.
+func ConfigMap(configMap *corev1.ConfigMap) []Issue {
+	l := &linter{configMap: configMap}
+	switch GetMapType(configMap) {
+	case MapTypeAction:
+		l.lintActionMap()
+	case MapTypeSections:
+		l.lintSectionsMap()
+	case MapTypeStatus:
+		l.lintStatusMap()
+	}
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		return l.issues[i].Key < l.issues[j].Key
+	})
+	return l.issues
+}
+
+// linter collects the issues of a single config map
+type linter struct {
+	configMap *corev1.ConfigMap
+	issues    []Issue
+	// variables and snippets are the names defined by an action map
+	variables map[string]bool
+	snippets  map[string]bool
+}
+
+func (l *linter) report(severity Severity, key string, path string, format string, args ...interface{}) {
+	l.issues = append(l.issues, Issue{
+		Map:      l.configMap.GetName(),
+		Key:      key,
+		Path:     path,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) errorf(key string, path string, format string, args ...interface{}) {
+	l.report(SeverityError, key, path, format, args...)
+}
+
+func (l *linter) warningf(key string, path string, format string, args ...interface{}) {
+	l.report(SeverityWarning, key, path, format, args...)
+}
+
+func (l *linter) lintActionMap() {
+	data := l.configMap.Data
+	l.variables = make(map[string]bool)
+	l.snippets = make(map[string]bool)
+
+	// Collect the names first, as the actions and variables refer to them.
+	var variables, snippets map[string]interface{}
+	if value, ok := data["variables"]; ok {
+		variables = l.parseObject("variables", value)
+		for name := range variables {
+			l.variables[name] = true
+		}
+	}
+	if value, ok := data["snippets"]; ok {
+		snippets = l.parseObject("snippets", value)
+		for name := range snippets {
+			l.snippets[name] = true
+		}
+	}
+
+	for key, value := range data {
+		switch key {
+		case "url-actions":
+			l.lintActions(key, value, urlActionSchema)
+		case "cmd-actions":
+			l.lintActions(key, value, cmdActionSchema)
+		case "variables":
+			for _, name := range sortedKeys(variables) {
+				path := "." + name
+				pattern, ok := variables[name].(string)
+				if !ok {
+					l.errorf(key, path, "must be a string")
+					continue
+				}
+				l.checkReferences(key, path, pattern)
+			}
+		case "snippets":
+			for _, name := range sortedKeys(snippets) {
+				path := "." + name
+				source, ok := snippets[name].(string)
+				if !ok {
+					l.errorf(key, path, "must be a string")
+					continue
+				}
+				l.checkSnippet(key, path, source)
+			}
+		default:
+			l.warningf(key, "", "unknown key, action maps hold url-actions, cmd-actions, variables and snippets")
+		}
+	}
+}
+
+func (l *linter) lintActions(key string, value string, schema objectSchema) {
+	actions := l.parseArray(key, value)
+	names := make(map[string]bool)
+	for i, element := range actions {
+		path := fmt.Sprintf("[%d]", i)
+		action, ok := element.(map[string]interface{})
+		if !ok {
+			l.errorf(key, path, "must be an object")
+			continue
+		}
+		l.checkObject(key, path, action, schema)
+		if name, ok := action["name"].(string); ok {
+			if names[name] {
+				l.errorf(key, path+".name", "duplicate action name %q", name)
+			}
+			names[name] = true
+		}
+		for _, field := range []string{"url-pattern", "cmd-pattern"} {
+			if pattern, ok := action[field].(string); ok {
+				l.checkReferences(key, path+"."+field, pattern)
+			}
+		}
+	}
+}
+
+func (l *linter) lintSectionsMap() {
+	data := l.configMap.Data
+	datasources := make(map[string]bool)
+	if value, ok := data["section-datasources"]; ok {
+		for i, element := range l.parseArray("section-datasources", value) {
+			path := fmt.Sprintf("[%d]", i)
+			datasource, ok := element.(map[string]interface{})
+			if !ok {
+				l.errorf("section-datasources", path, "must be an object")
+				continue
+			}
+			l.checkObject("section-datasources", path, datasource, datasourceSchema)
+			if name, ok := datasource["name"].(string); ok {
+				datasources[name] = true
+			}
+		}
+	}
+	for key, value := range data {
+		switch key {
+		case "sections":
+			for i, element := range l.parseArray(key, value) {
+				path := fmt.Sprintf("[%d]", i)
+				section, ok := element.(map[string]interface{})
+				if !ok {
+					l.errorf(key, path, "must be an object")
+					continue
+				}
+				l.checkObject(key, path, section, sectionSchema)
+				if name, ok := section["datasource"].(string); ok && !datasources[name] {
+					l.errorf(key, path+".datasource", "datasource %q is not defined in section-datasources", name)
+				}
+			}
+		case "section-datasources":
+		default:
+			l.warningf(key, "", "unknown key, section maps hold sections and section-datasources")
+		}
+	}
+}
+
+func (l *linter) lintStatusMap() {
+	for key, value := range l.configMap.Data {
+		if key == "algorithm" {
+			l.checkAlgorithm(key, value)
+			continue
+		}
+		// The other keys hold a fixed status, e.g. exists in the unregistered map.
+		status := l.parseObject(key, value)
+		if status != nil {
+			l.checkObject(key, "", status, statusValueSchema)
+		}
+	}
+}
+
+// parseArray parses a JSON array, reporting an error if it is not one
+func (l *linter) parseArray(key string, value string) []interface{} {
+	var array []interface{}
+	l.unmarshal(key, value, &array, "array")
+	return array
+}
+
+// parseObject parses a JSON object, reporting an error if it is not one
+func (l *linter) parseObject(key string, value string) map[string]interface{} {
+	var object map[string]interface{}
+	l.unmarshal(key, value, &object, "object")
+	return object
+}
+
+func (l *linter) unmarshal(key string, value string, v interface{}, kind string) {
+	unquotedKeys, err := unmarshalLenient(value, v)
+	if err != nil {
+		l.errorf(key, "", "invalid JSON %s: %v", kind, err)
+	} else if unquotedKeys {
+		l.warningf(key, "", "JSON keys should be quoted")
+	}
+}
+
+// unmarshalLenient unmarshals JSON that may contain raw line breaks and tabs in
+// its strings and unquoted keys, which the kAppNav components accept. The shipped
+// maps spread JavaScript over several lines. It returns whether any key was unquoted.
+func unmarshalLenient(value string, v interface{}) (bool, error) {
+	var buf bytes.Buffer
+	unquotedKeys := false
+	inString, escaped := false, false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			case c == '\n':
+				buf.WriteString(`\n`)
+				continue
+			case c == '\r':
+				buf.WriteString(`\r`)
+				continue
+			case c == '\t':
+				buf.WriteString(`\t`)
+				continue
+			}
+			buf.WriteByte(c)
+			continue
+		}
+		if c == '"' {
+			inString = true
+			buf.WriteByte(c)
+			continue
+		}
+		if !isBareWordStart(c) {
+			buf.WriteByte(c)
+			continue
+		}
+		// A bare word is a literal such as true, or an unquoted key if a colon follows.
+		end := i
+		for end < len(value) && !strings.ContainsRune("{}[]:,\" \t\r\n", rune(value[end])) {
+			end++
+		}
+		next := end
+		for next < len(value) && strings.ContainsRune(" \t\r\n", rune(value[next])) {
+			next++
+		}
+		if next < len(value) && value[next] == ':' {
+			unquotedKeys = true
+			buf.WriteString(strconv.Quote(value[i:end]))
+		} else {
+			buf.WriteString(value[i:end])
+		}
+		i = end - 1
+	}
+	return unquotedKeys, json.Unmarshal(buf.Bytes(), v)
+}
+
+func isBareWordStart(c byte) bool {
+	return c == '_' || c == '$' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	var keys []string
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
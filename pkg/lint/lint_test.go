@@ -0,0 +1,257 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestConfigMap(name string, labels map[string]string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kappnav", Labels: labels},
+		Data:       data,
+	}
+}
+
+func TestGetMapType(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   MapType
+	}{
+		{name: "kappnav.actions.deployment", want: MapTypeAction},
+		{name: "kappnav.actions.deployment-liberty", want: MapTypeAction},
+		{name: "kappnav.sections.deployment", want: MapTypeSections},
+		{name: "kappnav.status-mapping.deployment", want: MapTypeStatus},
+		{name: "kappnav-config"},
+		{name: "kappnav.actions", want: ""},
+		{name: "custom", labels: map[string]string{MapTypeLabel: "status"}, want: MapTypeStatus},
+		{name: "kappnav.sections.deployment", labels: map[string]string{MapTypeLabel: "action"}, want: MapTypeAction},
+		{name: "kappnav.sections.deployment", labels: map[string]string{MapTypeLabel: "other"}, want: MapTypeSections},
+	}
+	for _, test := range tests {
+		if mapType := GetMapType(newTestConfigMap(test.name, test.labels, nil)); mapType != test.want {
+			t.Errorf("%s with labels %v: got map type %q, want %q", test.name, test.labels, mapType, test.want)
+		}
+	}
+}
+
+const validURLActions = `[
+  {
+    "name": "detail",
+    "text": "View Detail",
+    "text.nls": "action.url.deployment.detail.text",
+    "url-pattern": "${builtin.openshift-console-url}/k8s/ns/${resource.$.metadata.namespace}/deployments/${var.name}",
+    "open-window": "tab",
+    "menu-item": "true"
+  }
+]`
+
+const validCmdActions = `[
+  {
+    "name": "restart",
+    "text": "Restart",
+    "text.nls": "action.cmd.deployment.restart.text",
+    "image": "kappnav/actions:latest",
+    "cmd-pattern": "kubectl rollout restart deployment ${resource.$.metadata.name} ${snippet.flags(${var.name},${func.kubectlNamespace()})}",
+    "enablement-label": "kappnav.io/restartable"
+  }
+]`
+
+const validSnippets = `{
+  "flags": "function flags(name, namespace) {
+    return '-n ' + namespace;
+  }"
+}`
+
+const validSections = `[
+  {
+    "name": "labels",
+    "title": "Labels",
+    "title.nls": "section.labels.title",
+    "datasource": "labels"
+  }
+]`
+
+const validDatasources = `[
+  {
+    "name": "labels",
+    "type": "labels-annotations",
+    "label-prefixes": ["app.kubernetes.io/"]
+  }
+]`
+
+const validAlgorithm = `function getStatus(status) {
+  return JSON.stringify({ value: "Normal", flyover: "" });
+}`
+
+func TestConfigMap(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   map[string]string
+		labels map[string]string
+		// want are the keys, paths and messages of the issues, empty for a valid map
+		want []string
+		// wantWarnings are true if the issues are all warnings
+		wantWarnings bool
+	}{
+		{
+			name: "kappnav.actions.deployment",
+			data: map[string]string{
+				"url-actions": validURLActions,
+				"cmd-actions": validCmdActions,
+				"variables":   `{"name": "${resource.$.metadata.name}"}`,
+				"snippets":    validSnippets,
+			},
+		},
+		{
+			name: "kappnav.actions.deployment",
+			data: map[string]string{
+				"url-actions": `[{"name": "detail", "text": "View Detail", "text.nls": "Detail", "open-window": "popup"}]`,
+				"cmd-actions": `[{"name": "restart", "text": "Restart", "text.nls": "action.restart"}]`,
+			},
+			want: []string{
+				`cmd-actions[0]: missing required field "cmd-pattern"`,
+				`cmd-actions[0]: missing required field "image"`,
+				`url-actions[0]: missing field "url-pattern"`,
+				`url-actions[0].open-window: must be one of current, tab, window, not "popup"`,
+				`url-actions[0].text.nls: "Detail" is not a message key`,
+			},
+		},
+		{
+			name: "kappnav.actions.deployment",
+			data: map[string]string{
+				"url-actions": `[{"name": "detail", "text": "Detail", "text.nls": "action.detail", "url-pattern": "${var.host}/${snippet.path}/${resource.metadata.name}/${node.name}/${func.encode(x}"}]`,
+				"variables":   `{"name": "${builtin.console-url"}`,
+			},
+			want: []string{
+				`url-actions[0].url-pattern: variable "host" is not defined in variables`,
+				`url-actions[0].url-pattern: snippet "path" is not defined in snippets`,
+				`url-actions[0].url-pattern: snippet reference ${snippet.path} must be called with (...)`,
+				`url-actions[0].url-pattern: resource reference ${resource.metadata.name} must be a JSONPath starting with $`,
+				`url-actions[0].url-pattern: unknown reference prefix "node" in ${node.name}`,
+				`url-actions[0].url-pattern: unterminated argument list in reference ${func.encode(x}`,
+				`variables.name: unterminated reference at "${builtin.console-url"`,
+			},
+		},
+		{
+			name: "kappnav.actions.deployment",
+			data: map[string]string{
+				"snippets": `{"flags": "function flags(name) { return '-n ' + ; }", "two": "function a() {} function b() {}"}`,
+			},
+			want: []string{
+				`snippets.flags: JavaScript syntax error`,
+				`snippets.two: a snippet must declare exactly one function, found 2`,
+			},
+		},
+		{
+			name: "kappnav.actions.deployment",
+			data: map[string]string{
+				"url-actions": `[{name: "detail", text: "Detail", text.nls: "action.detail", url-pattern: "", target: "tab"}]`,
+			},
+			want: []string{
+				`url-actions: JSON keys should be quoted`,
+				`url-actions[0].target: unknown field`,
+			},
+			wantWarnings: true,
+		},
+		{
+			name: "kappnav.sections.deployment",
+			data: map[string]string{
+				"sections":            validSections,
+				"section-datasources": validDatasources,
+			},
+		},
+		{
+			name: "kappnav.sections.deployment",
+			data: map[string]string{
+				"sections":            `[{"name": "labels", "title": "Labels", "title.nls": "section.labels.title", "datasource": "annotations"}]`,
+				"section-datasources": `[{"name": "labels", "type": "labels", "labels": "app"}]`,
+			},
+			want: []string{
+				`section-datasources[0].labels: must be an array of strings`,
+				`section-datasources[0].type: must be one of labels-annotations, not "labels"`,
+				`sections[0].datasource: datasource "annotations" is not defined in section-datasources`,
+			},
+		},
+		{
+			name: "kappnav.status-mapping.deployment",
+			data: map[string]string{
+				"algorithm": validAlgorithm,
+				"exists":    `{"value": "Normal", "flyover.nls": ["status.normal.flyover", "deployment"]}`,
+			},
+		},
+		{
+			name: "kappnav.status-mapping.deployment",
+			data: map[string]string{
+				"algorithm": `function getStatus(status) { return {; }`,
+				"exists":    `{"flyover": "Normal"`,
+			},
+			want: []string{
+				`algorithm: JavaScript syntax error`,
+				`exists: invalid JSON object`,
+			},
+		},
+		{
+			name:   "custom",
+			labels: map[string]string{MapTypeLabel: "status"},
+			data: map[string]string{
+				"algorithm": `function status() { return ""; }`,
+				"exists":    `{"value": "", "flyover.nls": []}`,
+			},
+			want: []string{
+				`algorithm: the algorithm must declare a function getStatus(status)`,
+				`exists.flyover.nls: must start with a message key`,
+				`exists.value: must not be empty`,
+			},
+		},
+		{
+			name: "kappnav-config",
+			data: map[string]string{"url-actions": "not JSON"},
+		},
+	}
+	for _, test := range tests {
+		issues := ConfigMap(newTestConfigMap(test.name, test.labels, test.data))
+		var got []string
+		for _, issue := range issues {
+			got = append(got, issue.Key+issue.Path+": "+issue.Message)
+			if issue.Map != test.name {
+				t.Errorf("%s: got issue %s for map %s", test.name, issue, issue.Map)
+			}
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%s: got issues\n  %s\nwant\n  %s", test.name, strings.Join(got, "\n  "), strings.Join(test.want, "\n  "))
+			continue
+		}
+		for _, want := range test.want {
+			found := false
+			for _, issue := range got {
+				if strings.HasPrefix(issue, want) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("%s: the issues\n  %s\ndo not include %s", test.name, strings.Join(got, "\n  "), want)
+			}
+		}
+		if len(test.want) > 0 && HasErrors(issues) == test.wantWarnings {
+			t.Errorf("%s: got HasErrors %t for issues %v", test.name, HasErrors(issues), got)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// identifierPattern matches the names of builtins, variables, snippets and functions
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// checkReferences checks the ${...} references of a url, command or variable
+// pattern. A reference is one of
+//
+//	${resource.<JSONPath>}           a field of the resource, e.g. ${resource.$.metadata.name}
+//	${builtin.<name>}                a value of the builtin config map
+//	${var.<name>[,<default>]}        a variable of the map
+//	${snippet.<name>(<args>)}        the result of a snippet of the map
+//	${func.<name>(<args>)}           the result of a kAppNav function
+//
+// Arguments and defaults may contain further references.
+func (l *linter) checkReferences(key string, path string, pattern string) {
+	for i := 0; i < len(pattern); {
+		start := strings.Index(pattern[i:], "${")
+		if start < 0 {
+			return
+		}
+		start += i
+		end := matchingBrace(pattern, start+1)
+		if end < 0 {
+			l.errorf(key, path, "unterminated reference at %q", truncate(pattern[start:]))
+			return
+		}
+		inner := pattern[start+2 : end]
+		head := inner
+		if n := strings.IndexAny(inner, "(,"); n >= 0 {
+			head = inner[:n]
+		}
+		l.checkReference(key, path, head, inner[len(head):])
+		// The arguments and the default may hold nested references.
+		l.checkReferences(key, path, inner[len(head):])
+		i = end + 1
+	}
+}
+
+// checkReference checks a single reference, whose rest are the arguments or the
+// default that follow its head.
+func (l *linter) checkReference(key string, path string, head string, rest string) {
+	dot := strings.Index(head, ".")
+	if dot < 0 {
+		l.errorf(key, path, "reference ${%s} has no prefix, expected resource, builtin, var, snippet or func", head)
+		return
+	}
+	prefix, name := head[:dot], head[dot+1:]
+	isCall := strings.HasPrefix(rest, "(")
+	switch prefix {
+	case "resource":
+		if !strings.HasPrefix(name, "$") {
+			l.errorf(key, path, "resource reference ${%s} must be a JSONPath starting with $", head)
+		}
+		return
+	case "builtin":
+	case "var":
+		if !l.variables[name] {
+			l.errorf(key, path, "variable %q is not defined in variables", name)
+		}
+	case "snippet":
+		if !l.snippets[name] {
+			l.errorf(key, path, "snippet %q is not defined in snippets", name)
+		}
+		if !isCall {
+			l.errorf(key, path, "snippet reference ${%s} must be called with (...)", head)
+		}
+	case "func":
+		if !isCall {
+			l.errorf(key, path, "function reference ${%s} must be called with (...)", head)
+		}
+	default:
+		l.errorf(key, path, "unknown reference prefix %q in ${%s}, expected resource, builtin, var, snippet or func", prefix, head)
+		return
+	}
+	if !identifierPattern.MatchString(name) {
+		l.errorf(key, path, "invalid name %q in reference ${%s}", name, head)
+	}
+	if isCall && matchingParen(rest) < 0 {
+		l.errorf(key, path, "unterminated argument list in reference ${%s%s}", head, truncate(rest))
+	}
+}
+
+// matchingBrace returns the index of the '}' that closes the '{' at open, or -1
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// matchingParen returns the index of the ')' that closes the '(' that s starts with, or -1
+func matchingParen(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func truncate(s string) string {
+	if len(s) > 40 {
+		return s[:40] + "..."
+	}
+	return s
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// fieldType is the type of a field of a JSON object in a config map
+type fieldType int
+
+const (
+	// typeString any string
+	typeString fieldType = iota
+	// typeNonEmptyString a string that must not be empty
+	typeNonEmptyString
+	// typeBooleanString the string "true" or "false"
+	typeBooleanString
+	// typeNLSKey a dot separated message key
+	typeNLSKey
+	// typeNLSKeyOrMessage a message key, or an array of a message key and its arguments
+	typeNLSKeyOrMessage
+	// typeQualifiedName a label or annotation key
+	typeQualifiedName
+	// typeStringArray an array of strings
+	typeStringArray
+	// typeEnum one of the values of the field
+	typeEnum
+)
+
+// fieldSchema describes a field of a JSON object
+type fieldSchema struct {
+	fieldType fieldType
+	// required fields are reported as errors when missing
+	required bool
+	// recommended fields are reported as warnings when missing
+	recommended bool
+	values      []string
+}
+
+// objectSchema describes the fields of a JSON object. Fields that are not in
+// the schema are reported as warnings.
+type objectSchema map[string]fieldSchema
+
+var urlActionSchema = objectSchema{
+	"name":                  {fieldType: typeNonEmptyString, required: true},
+	"text":                  {fieldType: typeNonEmptyString, required: true},
+	"text.nls":              {fieldType: typeNLSKey, recommended: true},
+	"description":           {fieldType: typeString},
+	"description.nls":       {fieldType: typeNLSKey},
+	"url-pattern":           {fieldType: typeString, recommended: true},
+	"open-window":           {fieldType: typeEnum, values: []string{"current", "tab", "window"}},
+	"menu-item":             {fieldType: typeBooleanString},
+	"enablement-label":      {fieldType: typeQualifiedName},
+	"enablement-annotation": {fieldType: typeQualifiedName},
+}
+
+var cmdActionSchema = objectSchema{
+	"name":                  {fieldType: typeNonEmptyString, required: true},
+	"text":                  {fieldType: typeNonEmptyString, required: true},
+	"text.nls":              {fieldType: typeNLSKey, recommended: true},
+	"description":           {fieldType: typeString},
+	"description.nls":       {fieldType: typeNLSKey},
+	"image":                 {fieldType: typeNonEmptyString, required: true},
+	"cmd-pattern":           {fieldType: typeNonEmptyString, required: true},
+	"menu-item":             {fieldType: typeBooleanString},
+	"enablement-label":      {fieldType: typeQualifiedName},
+	"enablement-annotation": {fieldType: typeQualifiedName},
+}
+
+var sectionSchema = objectSchema{
+	"name":             {fieldType: typeNonEmptyString, required: true},
+	"title":            {fieldType: typeNonEmptyString, required: true},
+	"title.nls":        {fieldType: typeNLSKey, recommended: true},
+	"description":      {fieldType: typeString},
+	"description.nls":  {fieldType: typeNLSKey},
+	"datasource":       {fieldType: typeNonEmptyString, required: true},
+	"enablement-label": {fieldType: typeQualifiedName},
+}
+
+var datasourceSchema = objectSchema{
+	"name":                {fieldType: typeNonEmptyString, required: true},
+	"type":                {fieldType: typeEnum, required: true, values: []string{"labels-annotations"}},
+	"label-prefixes":      {fieldType: typeStringArray},
+	"annotation-prefixes": {fieldType: typeStringArray},
+	"labels":              {fieldType: typeStringArray},
+	"annotations":         {fieldType: typeStringArray},
+}
+
+var statusValueSchema = objectSchema{
+	"value":       {fieldType: typeNonEmptyString, required: true},
+	"flyover":     {fieldType: typeString},
+	"flyover.nls": {fieldType: typeNLSKeyOrMessage},
+}
+
+// checkObject checks the fields of object against schema
+func (l *linter) checkObject(key string, path string, object map[string]interface{}, schema objectSchema) {
+	var names []string
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field := schema[name]
+		if _, ok := object[name]; !ok {
+			if field.required {
+				l.errorf(key, path, "missing required field %q", name)
+			} else if field.recommended {
+				l.warningf(key, path, "missing field %q", name)
+			}
+		}
+	}
+	for _, name := range sortedKeys(object) {
+		field, ok := schema[name]
+		if !ok {
+			l.warningf(key, path+"."+name, "unknown field")
+			continue
+		}
+		if err := checkField(object[name], &field); err != nil {
+			l.errorf(key, path+"."+name, "%v", err)
+		}
+	}
+}
+
+func checkField(value interface{}, field *fieldSchema) error {
+	if field.fieldType == typeStringArray {
+		array, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("must be an array of strings")
+		}
+		for _, element := range array {
+			if _, ok := element.(string); !ok {
+				return fmt.Errorf("must be an array of strings")
+			}
+		}
+		return nil
+	}
+	if field.fieldType == typeNLSKeyOrMessage {
+		if array, ok := value.([]interface{}); ok {
+			if len(array) == 0 {
+				return fmt.Errorf("must start with a message key")
+			}
+			value = array[0]
+		}
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("must be a string")
+	}
+	switch field.fieldType {
+	case typeNonEmptyString:
+		if len(s) == 0 {
+			return fmt.Errorf("must not be empty")
+		}
+	case typeBooleanString:
+		if s != "true" && s != "false" {
+			return fmt.Errorf("must be \"true\" or \"false\", not %q", s)
+		}
+	case typeNLSKey, typeNLSKeyOrMessage:
+		if !nlsKeyPattern.MatchString(s) {
+			return fmt.Errorf("%q is not a message key, keys are dot separated words such as action.url.pod.detail.text", s)
+		}
+	case typeQualifiedName:
+		if errs := validation.IsQualifiedName(s); len(errs) > 0 {
+			return fmt.Errorf("%q is not a valid label or annotation key: %s", s, strings.Join(errs, "; "))
+		}
+	case typeEnum:
+		for _, v := range field.values {
+			if s == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s, not %q", strings.Join(field.values, ", "), s)
+	}
+	return nil
+}
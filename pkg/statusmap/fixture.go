@@ -0,0 +1,186 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Fixture is a set of cases for the algorithm of one status config map, e.g.
+//
+//	map: ../maps/status/configmap.status-mapping.deployment.yaml
+//	cases:
+//	- name: all replicas available
+//	  status: {replicas: 3, availableReplicas: 3}
+//	  expect:
+//	    value: Normal
+//	    flyover: "Desired: 3, Available: 3"
+//	    flyover.nls: [status.flyover.deployment, "3", "3"]
+type Fixture struct {
+	// Map is the file of the status config map, relative to the fixture file
+	Map   string `json:"map"`
+	Cases []Case `json:"cases"`
+	// file is the file the fixture was read from
+	file string
+}
+
+// Case is the status of a resource and the result expected from the algorithm
+type Case struct {
+	Name string `json:"name"`
+	// Status is the status section of the resource
+	Status json.RawMessage `json:"status"`
+	Expect Expectation     `json:"expect"`
+}
+
+// Expectation is the expected result of a case. Fields that are not set are
+// not checked.
+type Expectation struct {
+	Value      string   `json:"value,omitempty"`
+	Flyover    string   `json:"flyover,omitempty"`
+	FlyoverNLS []string `json:"flyover.nls,omitempty"`
+}
+
+// CaseResult is the outcome of a case
+type CaseResult struct {
+	Fixture string
+	Case    string
+	Result  *Result
+	// Failures are the differences from the expectation, or the error of the run
+	Failures []string
+}
+
+// Passed returns whether the case met its expectation
+func (r *CaseResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+func (r *CaseResult) String() string {
+	if r.Passed() {
+		return fmt.Sprintf("PASS %s: %s", r.Fixture, r.Case)
+	}
+	return fmt.Sprintf("FAIL %s: %s: %s", r.Fixture, r.Case, strings.Join(r.Failures, "; "))
+}
+
+// LoadFixture reads a fixture from a YAML or JSON file
+func LoadFixture(fileName string) (*Fixture, error) {
+	fData, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	fixture := &Fixture{}
+	if err := yaml.Unmarshal(fData, fixture); err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+	if len(fixture.Map) == 0 {
+		return nil, fmt.Errorf("%s: map is not set", fileName)
+	}
+	fixture.file = fileName
+	return fixture, nil
+}
+
+// MapFile returns the file of the status config map of the fixture
+func (f *Fixture) MapFile() string {
+	if filepath.IsAbs(f.Map) || len(f.file) == 0 {
+		return f.Map
+	}
+	return filepath.Join(filepath.Dir(f.file), f.Map)
+}
+
+// Run loads the algorithm of the fixture and runs its cases
+func (f *Fixture) Run() ([]CaseResult, error) {
+	algorithm, err := LoadFile(f.MapFile())
+	if err != nil {
+		return nil, err
+	}
+	return f.RunAlgorithm(algorithm), nil
+}
+
+// RunAlgorithm runs the cases of the fixture against an algorithm
+func (f *Fixture) RunAlgorithm(algorithm *Algorithm) []CaseResult {
+	var results []CaseResult
+	for i, c := range f.Cases {
+		name := c.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("case %d", i)
+		}
+		results = append(results, Check(algorithm, &c, f.file, name))
+	}
+	return results
+}
+
+// Check runs a single case against an algorithm
+func Check(algorithm *Algorithm, c *Case, fixture string, name string) CaseResult {
+	caseResult := CaseResult{Fixture: fixture, Case: name}
+	status := []byte(c.Status)
+	if len(status) == 0 {
+		status = []byte("{}")
+	}
+	result, err := algorithm.Run(status)
+	if err != nil {
+		caseResult.Failures = []string{err.Error()}
+		return caseResult
+	}
+	caseResult.Result = result
+	expect := &c.Expect
+	if len(expect.Value) > 0 && expect.Value != result.Value {
+		caseResult.Failures = append(caseResult.Failures, fmt.Sprintf("value is %q, expected %q", result.Value, expect.Value))
+	}
+	if len(expect.Flyover) > 0 && expect.Flyover != result.Flyover {
+		caseResult.Failures = append(caseResult.Failures, fmt.Sprintf("flyover is %q, expected %q", result.Flyover, expect.Flyover))
+	}
+	if expect.FlyoverNLS != nil && !reflect.DeepEqual(expect.FlyoverNLS, result.FlyoverNLS) {
+		caseResult.Failures = append(caseResult.Failures, fmt.Sprintf("flyover.nls is %q, expected %q", result.FlyoverNLS, expect.FlyoverNLS))
+	}
+	return caseResult
+}
+
+// TestingT is the subset of testing.T used by RunFixtures
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// RunFixtures runs the fixtures in the given files from a Go test and reports
+// every failed case as a test error, e.g.
+//
+//	func TestStatusAlgorithms(t *testing.T) {
+//		statusmap.RunFixtures(t, "testdata/deployment.yaml")
+//	}
+func RunFixtures(t TestingT, fileNames ...string) {
+	t.Helper()
+	for _, fileName := range fileNames {
+		fixture, err := LoadFixture(fileName)
+		if err != nil {
+			t.Errorf("%v", err)
+			continue
+		}
+		results, err := fixture.Run()
+		if err != nil {
+			t.Errorf("%s: %v", fileName, err)
+			continue
+		}
+		for i := range results {
+			if !results[i].Passed() {
+				t.Errorf("%s", results[i].String())
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statusmap runs the getStatus(status) algorithms of kAppNav status
+// config maps outside of the kappnav controller, so that they can be tested
+// against fixture status JSON before they are shipped.
+//
+// The algorithms run in an embedded JavaScript engine with no access to the
+// file system, the network or the process, and are interrupted when they run
+// longer than the timeout of the Algorithm.
+package statusmap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/kappnav/operator/pkg/lint"
+	"github.com/robertkrimen/otto"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// AlgorithmKey is the key of the algorithm in the data of a status config map
+const AlgorithmKey string = "algorithm"
+
+// DefaultTimeout is how long an algorithm may run before it is interrupted
+const DefaultTimeout = time.Second
+
+var errTimeout = errors.New("timed out")
+
+// Algorithm is the status algorithm of a status config map
+type Algorithm struct {
+	// Map is the name of the config map
+	Map    string
+	Source string
+	// Timeout is how long a single run may take, DefaultTimeout if zero
+	Timeout time.Duration
+}
+
+// Result is the status returned by an algorithm
+type Result struct {
+	Value   string `json:"value"`
+	Flyover string `json:"flyover,omitempty"`
+	// FlyoverNLS is the message key of the flyover followed by its arguments
+	FlyoverNLS []string `json:"flyover.nls,omitempty"`
+}
+
+// Load returns the algorithm of a status config map
+func Load(configMap *corev1.ConfigMap) (*Algorithm, error) {
+	source, ok := configMap.Data[AlgorithmKey]
+	if !ok {
+		return nil, fmt.Errorf("config map %s has no %s", configMap.GetName(), AlgorithmKey)
+	}
+	return &Algorithm{Map: configMap.GetName(), Source: source}, nil
+}
+
+// LoadFile returns the algorithm of the status config map in a YAML file
+func LoadFile(fileName string) (*Algorithm, error) {
+	fData, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := yaml.Unmarshal(fData, configMap); err != nil {
+		return nil, err
+	}
+	return Load(configMap)
+}
+
+// Run calls getStatus with the JSON of the status of a resource, as the kappnav
+// controller does, and returns the status it returns. Every run uses a new
+// JavaScript runtime, so runs do not share state.
+func (a *Algorithm) Run(status []byte) (result *Result, err error) {
+	timeout := a.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	vm := otto.New()
+	vm.Interrupt = make(chan func(), 1)
+	timer := time.AfterFunc(timeout, func() {
+		vm.Interrupt <- func() {
+			panic(errTimeout)
+		}
+	})
+	defer timer.Stop()
+	defer func() {
+		if caught := recover(); caught != nil {
+			if caught != errTimeout {
+				panic(caught)
+			}
+			result, err = nil, fmt.Errorf("%s: %s did not return within %s", a.Map, lint.StatusFunction, timeout)
+		}
+	}()
+
+	if _, err := vm.Run(a.Source); err != nil {
+		return nil, fmt.Errorf("%s: %v", a.Map, err)
+	}
+	value, err := vm.Call(lint.StatusFunction, nil, string(status))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s failed: %v", a.Map, lint.StatusFunction, err)
+	}
+	if !value.IsString() {
+		return nil, fmt.Errorf("%s: %s returned %s instead of a JSON string", a.Map, lint.StatusFunction, value.Class())
+	}
+	return parseResult(value.String())
+}
+
+// parseResult parses the JSON returned by an algorithm. The flyover.nls may be
+// a message key or an array of a message key and its arguments.
+func parseResult(s string) (*Result, error) {
+	var raw struct {
+		Value      *string     `json:"value"`
+		Flyover    string      `json:"flyover"`
+		FlyoverNLS interface{} `json:"flyover.nls"`
+	}
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return nil, fmt.Errorf("%s returned invalid JSON %q: %v", lint.StatusFunction, s, err)
+	}
+	if raw.Value == nil {
+		return nil, fmt.Errorf("%s returned no value: %s", lint.StatusFunction, s)
+	}
+	result := &Result{Value: *raw.Value, Flyover: raw.Flyover}
+	switch nls := raw.FlyoverNLS.(type) {
+	case nil:
+	case string:
+		result.FlyoverNLS = []string{nls}
+	case []interface{}:
+		for _, element := range nls {
+			result.FlyoverNLS = append(result.FlyoverNLS, fmt.Sprint(element))
+		}
+	case map[string]interface{}:
+		// An empty object is returned by algorithms that set no message
+		if len(nls) > 0 {
+			return nil, fmt.Errorf("%s returned an object as flyover.nls: %s", lint.StatusFunction, s)
+		}
+	default:
+		return nil, fmt.Errorf("%s returned an invalid flyover.nls: %s", lint.StatusFunction, s)
+	}
+	return result, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusmap
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestShippedFixtures runs the fixtures of the status algorithms shipped in
+// deploy/maps/status
+func TestShippedFixtures(t *testing.T) {
+	fileNames, err := filepath.Glob(filepath.Join("..", "..", "deploy", "status-fixtures", "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fileNames) == 0 {
+		t.Fatal("no fixtures found in deploy/status-fixtures")
+	}
+	RunFixtures(t, fileNames...)
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   *Result
+		err    string
+	}{
+		{
+			name:   "value and flyover",
+			source: `function getStatus(status) { return JSON.stringify({value: "Normal", flyover: JSON.parse(status).phase}); }`,
+			want:   &Result{Value: "Normal", Flyover: "Running"},
+		},
+		{
+			name:   "flyover.nls key",
+			source: `function getStatus(status) { return JSON.stringify({value: "Warning", "flyover.nls": "status.flyover.pod"}); }`,
+			want:   &Result{Value: "Warning", FlyoverNLS: []string{"status.flyover.pod"}},
+		},
+		{
+			name:   "object instead of string",
+			source: `function getStatus(status) { return {value: "Normal"}; }`,
+			err:    "returned Object instead of a JSON string",
+		},
+		{
+			name:   "undefined instead of string",
+			source: `function getStatus(status) {}`,
+			err:    "instead of a JSON string",
+		},
+		{
+			name:   "invalid JSON",
+			source: `function getStatus(status) { return "Normal"; }`,
+			err:    "returned invalid JSON",
+		},
+		{
+			name:   "no value",
+			source: `function getStatus(status) { return JSON.stringify({flyover: "Running"}); }`,
+			err:    "returned no value",
+		},
+		{
+			name:   "exception",
+			source: `function getStatus(status) { throw new Error("broken"); }`,
+			err:    "getStatus failed",
+		},
+		{
+			name:   "syntax error",
+			source: `function getStatus(status) {`,
+			err:    "test-map",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			algorithm := &Algorithm{Map: "test-map", Source: test.source}
+			result, err := algorithm.Run([]byte(`{"phase": "Running"}`))
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("got error %v, want an error containing %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run failed: %s", err)
+			}
+			if result.Value != test.want.Value || result.Flyover != test.want.Flyover ||
+				strings.Join(result.FlyoverNLS, ",") != strings.Join(test.want.FlyoverNLS, ",") {
+				t.Errorf("got %+v, want %+v", result, test.want)
+			}
+		})
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	algorithm := &Algorithm{
+		Map:     "test-map",
+		Source:  `function getStatus(status) { while (true) {} }`,
+		Timeout: 100 * time.Millisecond,
+	}
+	start := time.Now()
+	_, err := algorithm.Run([]byte("{}"))
+	if err == nil || !strings.Contains(err.Error(), "did not return within 100ms") {
+		t.Fatalf("got error %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("the algorithm was interrupted after %s", elapsed)
+	}
+
+	// The runtime of the interrupted run is not reused
+	algorithm.Source = `function getStatus(status) { return JSON.stringify({value: "Normal"}); }`
+	if _, err := algorithm.Run([]byte("{}")); err != nil {
+		t.Errorf("Run after a timeout failed: %s", err)
+	}
+}
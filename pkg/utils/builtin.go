@@ -0,0 +1,125 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// BuiltinConsoleURLKey is the key of the console URL in the builtin config map
+	BuiltinConsoleURLKey string = "openshift-console-url"
+	// BuiltinAdminConsoleURLKey is the key of the admin console URL in the builtin
+	// config map
+	BuiltinAdminConsoleURLKey string = "openshift-admin-console-url"
+)
+
+var (
+	// OCPConsoleConfigMap is the config map the console URL is read from on OCP
+	OCPConsoleConfigMap = client.ObjectKey{Namespace: "openshift-console", Name: "console-config"}
+	// OKDConsoleConfigMap is the config map the console URLs are read from on OKD
+	OKDConsoleConfigMap = client.ObjectKey{Namespace: "openshift-web-console", Name: "webconsole-config"}
+)
+
+// GetBuiltinKeyPolicy returns the policy used to maintain a key of the builtin
+// config map, FillIfEmpty unless the CR selects another one
+func GetBuiltinKeyPolicy(instance *kappnavv1.Kappnav, key string) kappnavv1.BuiltinKeyPolicy {
+	if instance.Spec.Builtin != nil {
+		if policy, ok := instance.Spec.Builtin.Policies[key]; ok && len(policy) > 0 {
+			return policy
+		}
+	}
+	return kappnavv1.BuiltinKeyPolicyFillIfEmpty
+}
+
+// GetBuiltinResyncPeriod returns how often the builtin config map of the CR is
+// refreshed, 0 if it is only refreshed when the CR or the console configuration
+// changes
+func GetBuiltinResyncPeriod(instance *kappnavv1.Kappnav) time.Duration {
+	if instance.Spec.Builtin == nil || instance.Spec.Builtin.ResyncPeriod == nil {
+		return 0
+	}
+	return instance.Spec.Builtin.ResyncPeriod.Duration
+}
+
+// isBuiltinValueNeeded returns true if a key of the builtin config map would be set
+// by setBuiltinValue, so that values that are expensive to compute are only
+// computed when needed
+func isBuiltinValueNeeded(builtinConfig *corev1.ConfigMap, instance *kappnavv1.Kappnav, key string) bool {
+	return len(builtinConfig.Data[key]) == 0 || GetBuiltinKeyPolicy(instance, key) == kappnavv1.BuiltinKeyPolicyAlwaysSync
+}
+
+// setBuiltinValue sets a key of the builtin config map according to its policy.
+// Empty values, e.g. console URLs that could not be determined, are not set.
+func setBuiltinValue(logger Logger, builtinConfig *corev1.ConfigMap, instance *kappnavv1.Kappnav, key string, value string) {
+	current := builtinConfig.Data[key]
+	if len(value) == 0 || current == value || !isBuiltinValueNeeded(builtinConfig, instance, key) {
+		return
+	}
+	if len(current) > 0 && logger.IsEnabled(LogTypeInfo) {
+		logger.Log(CallerName(), LogTypeInfo, fmt.Sprintf("Sync %s of config map %s from %s to %s", key, builtinConfig.GetName(), current, value), logName)
+	}
+	builtinConfig.Data[key] = value
+}
+
+// ConsoleConfigWatcher watches the config maps the console URLs of the builtin
+// config maps are read from. It is a manager runnable.
+type ConsoleConfigWatcher struct {
+	informers []toolscache.SharedIndexInformer
+}
+
+// NewConsoleConfigWatcher creates the informers of the OCP and OKD console config
+// maps. Only the console config maps are listed and watched, and the informers of
+// the platform that is not installed receive no events.
+func NewConsoleConfigWatcher(config *rest.Config) (*ConsoleConfigWatcher, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	watcher := &ConsoleConfigWatcher{}
+	for _, key := range []client.ObjectKey{OCPConsoleConfigMap, OKDConsoleConfigMap} {
+		lw := toolscache.NewListWatchFromClient(clientset.CoreV1().RESTClient(), "configmaps", key.Namespace,
+			fields.OneTermEqualSelector("metadata.name", key.Name))
+		watcher.informers = append(watcher.informers, toolscache.NewSharedIndexInformer(lw, &corev1.ConfigMap{}, 0, toolscache.Indexers{}))
+	}
+	return watcher, nil
+}
+
+// Start runs the informers until stop is closed
+func (w *ConsoleConfigWatcher) Start(stop <-chan struct{}) error {
+	for _, informer := range w.informers {
+		go informer.Run(stop)
+	}
+	<-stop
+	return nil
+}
+
+// Sources returns the sources of the events of the console config maps
+func (w *ConsoleConfigWatcher) Sources() []source.Source {
+	var sources []source.Source
+	for _, informer := range w.informers {
+		sources = append(sources, &source.Informer{Informer: informer})
+	}
+	return sources
+}
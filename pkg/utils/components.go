@@ -0,0 +1,337 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// ComponentRBAC is the service account and cluster role binding of the CR
+	ComponentRBAC string = "RBAC"
+	// ComponentNetworking is the UI service and its route or ingress
+	ComponentNetworking string = "Networking"
+	// ComponentMaps is the kappnav Application and the config maps of the CR
+	ComponentMaps string = "Maps"
+	// ComponentKAM is the default KindActionMapping
+	ComponentKAM string = "KAM"
+	// ComponentUI is the UI Deployment
+	ComponentUI string = "UI"
+	// ComponentController is the controller Deployment
+	ComponentController string = "Controller"
+	// ComponentMonitoring is the ServiceMonitors and PrometheusRule of the CR
+	ComponentMonitoring string = "Monitoring"
+	// ComponentExtensionContainers is the Deployments and Services of the extension
+	// containers
+	ComponentExtensionContainers string = "ExtensionContainers"
+)
+
+const (
+	// ComponentConditionTypeSuffix is appended to the name of a component to form
+	// the type of its condition
+	ComponentConditionTypeSuffix string = "Reconciled"
+	// ComponentReasonDependencyFailed is the reason of the condition of a component
+	// that was not reconciled because a component it depends on failed
+	ComponentReasonDependencyFailed string = "DependencyFailed"
+	// ComponentReasonPanic is the reason of the condition of a component whose
+	// reconciler panicked
+	ComponentReasonPanic string = "Panic"
+)
+
+// BuiltinComponents are the names of the components of the operator. Extensions
+// depend on all of them unless they declare their dependencies.
+var BuiltinComponents = []string{
+	ComponentRBAC,
+	ComponentNetworking,
+	ComponentMaps,
+	ComponentKAM,
+	ComponentUI,
+	ComponentController,
+	ComponentMonitoring,
+	ComponentExtensionContainers,
+}
+
+// ComponentReconciler reconciles one component of a Kappnav CR. The components
+// are reconciled in the order of their dependencies, and a component that fails
+// does not stop the components that do not depend on it. The outcome of every
+// component is recorded in its <Name>Reconciled condition.
+type ComponentReconciler interface {
+	// Name is the name of the component, unique among the components
+	Name() string
+	// DependsOn returns the names of the components that must be reconciled
+	// successfully before this component
+	DependsOn() []string
+	// Reconcile creates or updates the resources of the component. The returned
+	// result may request the CR to be reconciled again. The caller is responsible
+	// for writing the status.
+	Reconcile(logger Logger, ctx *ComponentContext) (reconcile.Result, error)
+}
+
+// ComponentContext is the state shared by the components during a reconcile
+type ComponentContext struct {
+	Request    reconcile.Request
+	Reconciler *ReconcilerBase
+	Instance   *kappnavv1.Kappnav
+	// Names are the names of the resources of the CR in its isolation mode
+	Names *InstanceNames
+	// KappnavURL is the URL of the UI, set by the networking component
+	KappnavURL string
+	// Deployments are the Deployments whose containers are recorded in the status
+	// of the CR. Components add the Deployments they reconcile.
+	Deployments []*appsv1.Deployment
+}
+
+// ComponentFunc is a ComponentReconciler implemented by a function
+type ComponentFunc struct {
+	ComponentName string
+	Dependencies  []string
+	ReconcileFunc func(logger Logger, ctx *ComponentContext) (reconcile.Result, error)
+}
+
+// Name ...
+func (c *ComponentFunc) Name() string {
+	return c.ComponentName
+}
+
+// DependsOn ...
+func (c *ComponentFunc) DependsOn() []string {
+	return c.Dependencies
+}
+
+// Reconcile ...
+func (c *ComponentFunc) Reconcile(logger Logger, ctx *ComponentContext) (reconcile.Result, error) {
+	return c.ReconcileFunc(logger, ctx)
+}
+
+// NewComponent returns a component reconciled by a function
+func NewComponent(name string, dependsOn []string, reconcileFunc func(logger Logger, ctx *ComponentContext) (reconcile.Result, error)) ComponentReconciler {
+	return &ComponentFunc{ComponentName: name, Dependencies: dependsOn, ReconcileFunc: reconcileFunc}
+}
+
+var (
+	// registeredComponentsMutex guards registeredComponents
+	registeredComponentsMutex sync.Mutex
+	// registeredComponents are the components added to the operator, in the order
+	// they were registered
+	registeredComponents []ComponentReconciler
+)
+
+// RegisterComponent adds a component to the components reconciled for every
+// Kappnav CR. Products embedding the operator call it from an init function of
+// their packages.
+func RegisterComponent(component ComponentReconciler) {
+	registeredComponentsMutex.Lock()
+	defer registeredComponentsMutex.Unlock()
+	registeredComponents = append(registeredComponents, component)
+}
+
+// GetRegisteredComponents returns the registered components in the order they
+// were registered
+func GetRegisteredComponents() []ComponentReconciler {
+	registeredComponentsMutex.Lock()
+	defer registeredComponentsMutex.Unlock()
+	return append([]ComponentReconciler(nil), registeredComponents...)
+}
+
+// GetComponentConditionType returns the type of the condition of a component
+func GetComponentConditionType(name string) kappnavv1.StatusConditionType {
+	return kappnavv1.StatusConditionType(name + ComponentConditionTypeSuffix)
+}
+
+// OrderComponents sorts the components so that every component comes after the
+// components it depends on. Independent components keep their order. Returns an
+// error if names are duplicated, a dependency is unknown or dependencies form a
+// cycle.
+func OrderComponents(components []ComponentReconciler) ([]ComponentReconciler, error) {
+	byName := make(map[string]ComponentReconciler)
+	for _, component := range components {
+		if _, ok := byName[component.Name()]; ok {
+			return nil, fmt.Errorf("component %s is registered more than once", component.Name())
+		}
+		byName[component.Name()] = component
+	}
+	for _, component := range components {
+		for _, dependency := range component.DependsOn() {
+			if _, ok := byName[dependency]; !ok {
+				return nil, fmt.Errorf("component %s depends on unknown component %s", component.Name(), dependency)
+			}
+		}
+	}
+
+	ordered := make([]ComponentReconciler, 0, len(components))
+	done := make(map[string]bool)
+	for len(ordered) < len(components) {
+		progress := false
+		for _, component := range components {
+			if done[component.Name()] || !containAllStrings(done, component.DependsOn()) {
+				continue
+			}
+			ordered = append(ordered, component)
+			done[component.Name()] = true
+			progress = true
+		}
+		if !progress {
+			var cycle []string
+			for _, component := range components {
+				if !done[component.Name()] {
+					cycle = append(cycle, component.Name())
+				}
+			}
+			return nil, fmt.Errorf("the dependencies of components %s form a cycle", strings.Join(cycle, ", "))
+		}
+	}
+	return ordered, nil
+}
+
+// ReconcileComponents reconciles the ordered components and records the outcome
+// of each in its condition. A component is skipped when a component it depends on
+// failed. Returns the merged results of the components and the errors of the
+// components that failed. The caller is responsible for writing the status.
+func ReconcileComponents(logger Logger, ctx *ComponentContext, components []ComponentReconciler) (reconcile.Result, map[string]error) {
+	var result reconcile.Result
+	failed := make(map[string]error)
+	for _, component := range components {
+		name := component.Name()
+		conditionType := GetComponentConditionType(name)
+
+		var failedDependencies []string
+		for _, dependency := range component.DependsOn() {
+			if _, ok := failed[dependency]; ok {
+				failedDependencies = append(failedDependencies, dependency)
+			}
+		}
+		if len(failedDependencies) > 0 {
+			err := fmt.Errorf("not reconciled because component %s failed", strings.Join(failedDependencies, ", "))
+			failed[name] = err
+			SetComponentCondition(ctx.Instance, conditionType, corev1.ConditionFalse, ComponentReasonDependencyFailed, err.Error())
+			if logger.IsEnabled(LogTypeWarning) {
+				logger.Log(CallerName(), LogTypeWarning, "Component "+name+" "+err.Error(), logName)
+			}
+			continue
+		}
+
+		componentResult, reason, err := reconcileComponent(logger, ctx, component)
+		if err != nil {
+			failed[name] = err
+			SetComponentCondition(ctx.Instance, conditionType, corev1.ConditionFalse, reason, err.Error())
+			if logger.IsEnabled(LogTypeError) {
+				logger.Log(CallerName(), LogTypeError, fmt.Sprintf("Failed to reconcile component %s, Error: %s", name, err), logName)
+			}
+			continue
+		}
+		SetComponentCondition(ctx.Instance, conditionType, corev1.ConditionTrue, "", "")
+		result = mergeResults(result, componentResult)
+	}
+	return result, failed
+}
+
+// reconcileComponent reconciles a component in its own span, turning a panic of
+// the component into an error so that the other components are still reconciled
+func reconcileComponent(logger Logger, ctx *ComponentContext, component ComponentReconciler) (result reconcile.Result, reason string, err error) {
+	logger, span := StartSpan(logger, "ReconcileComponent", TraceAttributeComponent.String(component.Name()))
+	start := time.Now()
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("component %s panicked: %v", component.Name(), p)
+			reason = ComponentReasonPanic
+			if logger.IsEnabled(LogTypeError) {
+				logger.Log(CallerName(), LogTypeError, ErrorWithStack(err.Error()), logName)
+			}
+		}
+		ObserveReconcileStep("component-"+strings.ToLower(component.Name()), start, err)
+		EndSpan(span, err)
+	}()
+	if logger.IsEnabled(LogTypeDebug) {
+		logger.Log(CallerName(), LogTypeDebug, "Reconcile component "+component.Name(), logName)
+	}
+	result, err = component.Reconcile(logger, ctx)
+	if err != nil {
+		reason = string(apierrors.ReasonForError(err))
+	}
+	return result, reason, err
+}
+
+// SetComponentCondition sets a condition of the CR, keeping its times when
+// nothing has changed
+func SetComponentCondition(instance *kappnavv1.Kappnav, conditionType kappnavv1.StatusConditionType,
+	status corev1.ConditionStatus, reason string, message string) {
+	now := metav1.Now()
+	condition := kappnavv1.StatusCondition{
+		LastTransitionTime: &now,
+		LastUpdateTime:     now,
+		Reason:             reason,
+		Message:            message,
+		Status:             status,
+		Type:               conditionType,
+	}
+	if old := GetCondition(conditionType, &instance.Status); old != nil && old.Status == status {
+		condition.LastTransitionTime = old.LastTransitionTime
+		if old.Reason == reason && old.Message == message {
+			condition.LastUpdateTime = old.LastUpdateTime
+		}
+	}
+	SetCondition(condition, &instance.Status)
+}
+
+// GetComponentsError returns the error recorded in the Reconciled condition when
+// components failed. The error of a single failed component is returned as is,
+// so that its reason is kept.
+func GetComponentsError(components []ComponentReconciler, failed map[string]error) error {
+	var messages []string
+	var last error
+	for _, component := range components {
+		err, ok := failed[component.Name()]
+		if !ok {
+			continue
+		}
+		last = err
+		messages = append(messages, component.Name()+": "+err.Error())
+	}
+	switch len(messages) {
+	case 0:
+		return nil
+	case 1:
+		return last
+	default:
+		return fmt.Errorf("%d components failed: %s", len(messages), strings.Join(messages, "; "))
+	}
+}
+
+// mergeResults returns a result that requeues as soon as either result requests
+func mergeResults(a reconcile.Result, b reconcile.Result) reconcile.Result {
+	result := reconcile.Result{Requeue: a.Requeue || b.Requeue, RequeueAfter: a.RequeueAfter}
+	if b.RequeueAfter > 0 && (result.RequeueAfter == 0 || b.RequeueAfter < result.RequeueAfter) {
+		result.RequeueAfter = b.RequeueAfter
+	}
+	return result
+}
+
+func containAllStrings(set map[string]bool, values []string) bool {
+	for _, value := range values {
+		if !set[value] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestComponent(name string, dependsOn ...string) ComponentReconciler {
+	return NewComponent(name, dependsOn, nil)
+}
+
+func TestOrderComponents(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []ComponentReconciler
+		want       []string
+		wantErr    string
+	}{
+		{
+			name:       "independent components keep their order",
+			components: []ComponentReconciler{newTestComponent("b"), newTestComponent("a"), newTestComponent("c")},
+			want:       []string{"b", "a", "c"},
+		},
+		{
+			name: "dependencies come first",
+			components: []ComponentReconciler{
+				newTestComponent("ui", "rbac", "maps"),
+				newTestComponent("maps", "rbac"),
+				newTestComponent("rbac"),
+				newTestComponent("extension", "ui"),
+			},
+			want: []string{"rbac", "maps", "ui", "extension"},
+		},
+		{
+			name:       "unknown dependency",
+			components: []ComponentReconciler{newTestComponent("ui", "rbac")},
+			wantErr:    "component ui depends on unknown component rbac",
+		},
+		{
+			name:       "duplicate name",
+			components: []ComponentReconciler{newTestComponent("ui"), newTestComponent("ui")},
+			wantErr:    "component ui is registered more than once",
+		},
+		{
+			name: "cycle",
+			components: []ComponentReconciler{
+				newTestComponent("rbac"),
+				newTestComponent("a", "c"),
+				newTestComponent("b", "a"),
+				newTestComponent("c", "b", "rbac"),
+			},
+			wantErr: "the dependencies of components a, b, c form a cycle",
+		},
+		{
+			name:       "self dependency",
+			components: []ComponentReconciler{newTestComponent("a", "a")},
+			wantErr:    "the dependencies of components a form a cycle",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ordered, err := OrderComponents(test.components)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("got error %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OrderComponents failed: %s", err)
+			}
+			var names []string
+			for _, component := range ordered {
+				names = append(names, component.Name())
+			}
+			if !reflect.DeepEqual(names, test.want) {
+				t.Errorf("got order %v, want %v", names, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2019 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// CRDReadinessCheckName is the name of the readiness check of the CRD bootstrap
+	CRDReadinessCheckName string = "crds"
+
+	crdBootstrapInitialDelay = 2 * time.Second
+	crdBootstrapMaxDelay     = 5 * time.Minute
+)
+
+// CRDBootstrap is a manager Runnable that applies the CRDs shipped in the image,
+// retrying with exponential backoff until all of them are established. Failures
+// are logged and recorded as events on the event object, which is usually the
+// operator pod.
+type CRDBootstrap struct {
+	logger      Logger
+	manager     *CRDManager
+	recorder    record.EventRecorder
+	eventObject runtime.Object
+
+	mutex   sync.RWMutex
+	ready   bool
+	lastErr error
+}
+
+// NewCRDBootstrap creates a CRDBootstrap. eventObject may be nil, in which case
+// no events are recorded.
+func NewCRDBootstrap(logger Logger, manager *CRDManager, recorder record.EventRecorder, eventObject runtime.Object) *CRDBootstrap {
+	return &CRDBootstrap{
+		logger:      logger,
+		manager:     manager,
+		recorder:    recorder,
+		eventObject: eventObject,
+	}
+}
+
+// Start applies the CRDs until it succeeds or stop is closed. It never returns an
+// error, as that would stop the manager.
+func (b *CRDBootstrap) Start(stop <-chan struct{}) error {
+	delay := crdBootstrapInitialDelay
+	for {
+		err := b.manager.Apply(b.logger, false)
+		b.setResult(err)
+		if err == nil {
+			if b.logger.IsEnabled(LogTypeInfo) {
+				b.logger.Log(CallerName(), LogTypeInfo, "All CRDs are established", logName)
+			}
+			b.recordEvent("Normal", "CRDsEstablished", "All CRDs shipped with the operator are established")
+			return nil
+		}
+		if crdErrors, ok := err.(CRDErrors); ok {
+			for _, f := range crdErrors {
+				b.recordEvent("Warning", "CRDFailed", fmt.Sprintf("Failed to apply CRD %s: %s", f.Name, f.Err))
+			}
+		} else {
+			b.recordEvent("Warning", "CRDFailed", err.Error())
+		}
+		if b.logger.IsEnabled(LogTypeWarning) {
+			b.logger.Log(CallerName(), LogTypeWarning, fmt.Sprintf("CRD bootstrap failed, retrying in %s, Error: %s", delay, err), logName)
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > crdBootstrapMaxDelay {
+			delay = crdBootstrapMaxDelay
+		}
+	}
+}
+
+// Check returns nil once all CRDs are established, otherwise the last failure.
+func (b *CRDBootstrap) Check() error {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if b.ready {
+		return nil
+	}
+	if b.lastErr != nil {
+		return b.lastErr
+	}
+	return errors.New("CRD bootstrap is in progress")
+}
+
+func (b *CRDBootstrap) setResult(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.ready = err == nil
+	b.lastErr = err
+}
+
+func (b *CRDBootstrap) recordEvent(eventType string, reason string, message string) {
+	if b.eventObject != nil {
+		b.recorder.Event(b.eventObject, eventType, reason, message)
+	}
+}
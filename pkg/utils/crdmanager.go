@@ -0,0 +1,540 @@
+/*
+Copyright 2019 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// CRDVersionAnnotation records the version of the CRD definition applied by the operator
+	CRDVersionAnnotation string = "kappnav.operator.kappnav.io/crd-version"
+	// CRDFieldManager is the field manager used when applying CRDs
+	CRDFieldManager string = "kappnav-operator"
+
+	apiextensionsGroup    = "apiextensions.k8s.io"
+	apiextensionsV1       = apiextensionsGroup + "/v1"
+	apiextensionsV1beta1  = apiextensionsGroup + "/v1beta1"
+	applyPatchType        = types.PatchType("application/apply-patch+yaml")
+	crdEstablishedTimeout = 60 * time.Second
+)
+
+var (
+	conversionWebhookMutex  sync.RWMutex
+	conversionWebhookServed bool
+)
+
+// SetConversionWebhookServed records whether the operator serves the CRD conversion
+// webhook, which requires a serving certificate
+func SetConversionWebhookServed(served bool) {
+	conversionWebhookMutex.Lock()
+	defer conversionWebhookMutex.Unlock()
+	conversionWebhookServed = served
+}
+
+// IsConversionWebhookServed returns true if the operator serves the CRD conversion
+// webhook
+func IsConversionWebhookServed() bool {
+	conversionWebhookMutex.RLock()
+	defer conversionWebhookMutex.RUnlock()
+	return conversionWebhookServed
+}
+
+// CRDManager installs and upgrades the CRDs shipped in a directory of the image.
+// Each file may contain an apiextensions.k8s.io/v1 or v1beta1 definition; it is
+// converted to the newest version served by the cluster before it is applied.
+type CRDManager struct {
+	r         *ReconcilerBase
+	dir       string
+	version   string
+	namespace string
+}
+
+// NewCRDManager creates a CRDManager for the CRDs in dir. version is the version
+// of the definitions used when a file does not carry a CRDVersionAnnotation.
+// namespace is the namespace of the operator, which serves the conversion webhooks;
+// the namespace in the files is kept when it is empty.
+func NewCRDManager(r *ReconcilerBase, dir string, version string, namespace string) *CRDManager {
+	return &CRDManager{r: r, dir: dir, version: version, namespace: namespace}
+}
+
+// Apply creates every CRD in the directory and updates the existing ones whose
+// shipped version is newer than the version recorded on the cluster object, or
+// all of them if force is true. It then waits for each CRD to be established.
+// Failures of individual CRDs do not stop the others and are returned together
+// as CRDErrors.
+func (m *CRDManager) Apply(logger Logger, force bool) error {
+	files, err := ioutil.ReadDir(m.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %s", m.dir, err)
+	}
+	apiVersion, err := m.getServerAPIVersion()
+	if err != nil {
+		return err
+	}
+	var failures CRDErrors
+	for _, file := range files {
+		fileName := filepath.Join(m.dir, file.Name())
+		if file.IsDir() || !(strings.HasSuffix(fileName, ".yaml") || strings.HasSuffix(fileName, ".yml")) {
+			continue
+		}
+		if logger.IsEnabled(LogTypeDebug) {
+			logger.Log(CallerName(), LogTypeDebug, "Read file from the image: "+fileName, logName)
+		}
+		name, err := m.applyFile(logger, fileName, apiVersion, force)
+		if err != nil {
+			if logger.IsEnabled(LogTypeError) {
+				logger.Log(CallerName(), LogTypeError, fmt.Sprintf("Failed to apply CRD from file: %s, error: %s", fileName, err), logName)
+			}
+			if len(name) == 0 {
+				name = file.Name()
+			}
+			failures = append(failures, CRDError{Name: name, Err: err})
+		}
+	}
+	if len(failures) > 0 {
+		return failures
+	}
+	return nil
+}
+
+// CRDError is the failure to apply a single CRD.
+type CRDError struct {
+	Name string
+	Err  error
+}
+
+// CRDErrors lists the CRDs that could not be applied.
+type CRDErrors []CRDError
+
+func (e CRDErrors) Error() string {
+	var failures []string
+	for _, f := range e {
+		failures = append(failures, fmt.Sprintf("%s: %s", f.Name, f.Err))
+	}
+	return "failed to apply CRDs: " + strings.Join(failures, "; ")
+}
+
+// applyFile applies the CRD in fileName and returns its name.
+func (m *CRDManager) applyFile(logger Logger, fileName string, apiVersion string, force bool) (string, error) {
+	fData, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return "", err
+	}
+	crd := &unstructured.Unstructured{}
+	if err = yaml.Unmarshal(fData, &crd.Object); err != nil {
+		return "", err
+	}
+	name := crd.GetName()
+	if crd.GetKind() != "CustomResourceDefinition" || len(name) == 0 {
+		return name, fmt.Errorf("not a CustomResourceDefinition")
+	}
+	if !IsConversionWebhookServed() {
+		disableConversionWebhook(crd.Object)
+	}
+	if err = convertCRD(crd.Object, apiVersion); err != nil {
+		return name, err
+	}
+	if len(m.namespace) > 0 {
+		setConversionServiceNamespace(crd.Object, m.namespace)
+	}
+	crd.SetAPIVersion(apiVersion)
+
+	annotations := crd.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	shippedVersion := annotations[CRDVersionAnnotation]
+	if len(shippedVersion) == 0 {
+		shippedVersion = m.version
+		annotations[CRDVersionAnnotation] = shippedVersion
+		crd.SetAnnotations(annotations)
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetAPIVersion(apiVersion)
+	existing.SetKind("CustomResourceDefinition")
+	reader, err := m.r.GetAPIReader()
+	if err != nil {
+		return name, err
+	}
+	err = reader.Get(context.TODO(), client.ObjectKey{Name: name}, existing)
+	exists := err == nil
+	if err != nil && !apierrors.IsNotFound(err) {
+		return name, err
+	}
+	if exists && !force && !isNewerVersion(shippedVersion, existing.GetAnnotations()[CRDVersionAnnotation]) &&
+		getConversionStrategy(existing.Object) == getConversionStrategy(crd.Object) {
+		if logger.IsEnabled(LogTypeDebug) {
+			logger.Log(CallerName(), LogTypeDebug, fmt.Sprintf("CRD %s is up to date at version %s", name, shippedVersion), logName)
+		}
+	} else {
+		if err = m.serverSideApply(crd, apiVersion); err != nil {
+			if !apierrors.IsUnsupportedMediaType(err) {
+				return name, err
+			}
+			// The cluster does not support server-side apply.
+			if exists {
+				crd.SetResourceVersion(existing.GetResourceVersion())
+				err = m.r.GetClient().Update(context.TODO(), crd)
+			} else {
+				err = m.r.GetClient().Create(context.TODO(), crd)
+			}
+			if err != nil {
+				return name, err
+			}
+		}
+		if logger.IsEnabled(LogTypeInfo) {
+			logger.Log(CallerName(), LogTypeInfo, fmt.Sprintf("Applied CRD %s at version %s", name, shippedVersion), logName)
+		}
+	}
+	return name, m.waitForEstablished(name, apiVersion)
+}
+
+// serverSideApply applies the CRD with the operator as field manager, taking
+// ownership of fields that were set by earlier releases.
+func (m *CRDManager) serverSideApply(crd *unstructured.Unstructured, apiVersion string) error {
+	cs, err := apiextensionsclient.NewForConfig(m.r.restConfig)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(crd.Object)
+	if err != nil {
+		return err
+	}
+	return cs.ApiextensionsV1beta1().RESTClient().Patch(applyPatchType).
+		AbsPath("/apis", apiVersion, "customresourcedefinitions", crd.GetName()).
+		Param("fieldManager", CRDFieldManager).
+		Param("force", "true").
+		Body(data).
+		Do().
+		Error()
+}
+
+// waitForEstablished waits until the CRD reports the Established condition.
+func (m *CRDManager) waitForEstablished(name string, apiVersion string) error {
+	reader, err := m.r.GetAPIReader()
+	if err != nil {
+		return err
+	}
+	err = wait.PollImmediate(time.Second, crdEstablishedTimeout, func() (bool, error) {
+		crd := &unstructured.Unstructured{}
+		crd.SetAPIVersion(apiVersion)
+		crd.SetKind("CustomResourceDefinition")
+		if err := reader.Get(context.TODO(), client.ObjectKey{Name: name}, crd); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if ok && condition["type"] == "Established" && condition["status"] == "True" {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("CRD was not established within %s", crdEstablishedTimeout)
+	}
+	return err
+}
+
+// getServerAPIVersion returns the newest apiextensions version served by the cluster.
+func (m *CRDManager) getServerAPIVersion() (string, error) {
+	supported, err := m.r.IsGroupVersionSupported(NewLogger(), apiextensionsV1)
+	if err != nil {
+		return "", err
+	}
+	if supported {
+		return apiextensionsV1, nil
+	}
+	return apiextensionsV1beta1, nil
+}
+
+// isNewerVersion returns true if shipped is newer than installed. Versions are
+// compared numerically by dot separated component; versions that cannot be
+// compared are treated as newer whenever they differ.
+func isNewerVersion(shipped string, installed string) bool {
+	if len(installed) == 0 {
+		return true
+	}
+	if shipped == installed {
+		return false
+	}
+	s := strings.Split(strings.TrimPrefix(shipped, "v"), ".")
+	i := strings.Split(strings.TrimPrefix(installed, "v"), ".")
+	for n := 0; n < len(s) || n < len(i); n++ {
+		var sv, iv int
+		var err error
+		if n < len(s) {
+			if sv, err = strconv.Atoi(s[n]); err != nil {
+				return true
+			}
+		}
+		if n < len(i) {
+			if iv, err = strconv.Atoi(i[n]); err != nil {
+				return true
+			}
+		}
+		if sv != iv {
+			return sv > iv
+		}
+	}
+	return false
+}
+
+// convertCRD converts the CRD object in place to the given apiextensions version.
+func convertCRD(crd map[string]interface{}, apiVersion string) error {
+	from, _, _ := unstructured.NestedString(crd, "apiVersion")
+	if from == apiVersion {
+		return nil
+	}
+	switch {
+	case from == apiextensionsV1beta1 && apiVersion == apiextensionsV1:
+		return convertCRDToV1(crd)
+	case from == apiextensionsV1 && apiVersion == apiextensionsV1beta1:
+		return convertCRDToV1beta1(crd)
+	}
+	return fmt.Errorf("cannot convert CRD from %s to %s", from, apiVersion)
+}
+
+// convertCRDToV1 moves the top-level schema, subresources and printer columns of
+// a v1beta1 CRD into its versions, as required by apiextensions.k8s.io/v1.
+func convertCRDToV1(crd map[string]interface{}) error {
+	spec, ok := crd["spec"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("CRD has no spec")
+	}
+	versions, _, _ := unstructured.NestedSlice(spec, "versions")
+	if len(versions) == 0 {
+		name, _, _ := unstructured.NestedString(spec, "version")
+		versions = []interface{}{map[string]interface{}{"name": name, "served": true, "storage": true}}
+	}
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid version in CRD")
+		}
+		if _, ok := version["schema"]; !ok {
+			if validation, ok := spec["validation"]; ok {
+				version["schema"] = deepCopyJSONValue(validation)
+			} else {
+				version["schema"] = map[string]interface{}{
+					"openAPIV3Schema": map[string]interface{}{
+						"type":                                 "object",
+						"x-kubernetes-preserve-unknown-fields": true,
+					},
+				}
+			}
+		}
+		// v1 requires a structural schema with a type at the root.
+		if root, ok, _ := unstructured.NestedMap(version, "schema", "openAPIV3Schema"); ok {
+			if _, ok := root["type"]; !ok {
+				root["type"] = "object"
+				unstructured.SetNestedMap(version, root, "schema", "openAPIV3Schema")
+			}
+		}
+		if _, ok := version["subresources"]; !ok {
+			if subresources, ok := spec["subresources"]; ok {
+				version["subresources"] = deepCopyJSONValue(subresources)
+			}
+		}
+		columns, ok := version["additionalPrinterColumns"]
+		if !ok {
+			columns, ok = spec["additionalPrinterColumns"]
+			columns = deepCopyJSONValue(columns)
+		}
+		if ok {
+			version["additionalPrinterColumns"] = renamePrinterColumnPath(columns, "JSONPath", "jsonPath")
+		}
+	}
+	unstructured.SetNestedSlice(spec, versions, "versions")
+	delete(spec, "version")
+	delete(spec, "validation")
+	delete(spec, "subresources")
+	delete(spec, "additionalPrinterColumns")
+	delete(spec, "preserveUnknownFields")
+
+	if strategy, _, _ := unstructured.NestedString(spec, "conversion", "strategy"); strategy == "Webhook" {
+		conversion, _, _ := unstructured.NestedMap(spec, "conversion")
+		webhook := map[string]interface{}{}
+		if clientConfig, ok := conversion["webhookClientConfig"]; ok {
+			webhook["clientConfig"] = clientConfig
+		}
+		reviewVersions, ok := conversion["conversionReviewVersions"]
+		if !ok {
+			reviewVersions = []interface{}{"v1beta1"}
+		}
+		webhook["conversionReviewVersions"] = reviewVersions
+		spec["conversion"] = map[string]interface{}{
+			"strategy": "Webhook",
+			"webhook":  webhook,
+		}
+	}
+	crd["apiVersion"] = apiextensionsV1
+	return nil
+}
+
+// convertCRDToV1beta1 converts a v1 CRD for clusters that predate apiextensions.k8s.io/v1.
+// Per-version settings that are identical for all versions are moved to the top level,
+// as v1beta1 rejects identical per-version values.
+func convertCRDToV1beta1(crd map[string]interface{}) error {
+	spec, ok := crd["spec"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("CRD has no spec")
+	}
+	versions, _, _ := unstructured.NestedSlice(spec, "versions")
+	if len(versions) == 0 {
+		return fmt.Errorf("CRD has no versions")
+	}
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid version in CRD")
+		}
+		if columns, ok := version["additionalPrinterColumns"]; ok {
+			version["additionalPrinterColumns"] = renamePrinterColumnPath(columns, "jsonPath", "JSONPath")
+		}
+	}
+	topLevel := map[string]string{
+		"schema":                   "validation",
+		"subresources":             "subresources",
+		"additionalPrinterColumns": "additionalPrinterColumns",
+	}
+	for field, specField := range topLevel {
+		first := versions[0].(map[string]interface{})[field]
+		identical := first != nil
+		for _, v := range versions[1:] {
+			if !reflect.DeepEqual(first, v.(map[string]interface{})[field]) {
+				identical = false
+			}
+		}
+		if identical {
+			spec[specField] = first
+			for _, v := range versions {
+				delete(v.(map[string]interface{}), field)
+			}
+		}
+	}
+	unstructured.SetNestedSlice(spec, versions, "versions")
+
+	if strategy, _, _ := unstructured.NestedString(spec, "conversion", "strategy"); strategy == "Webhook" {
+		webhook, _, _ := unstructured.NestedMap(spec, "conversion", "webhook")
+		conversion := map[string]interface{}{"strategy": "Webhook"}
+		if clientConfig, ok := webhook["clientConfig"]; ok {
+			conversion["webhookClientConfig"] = clientConfig
+		}
+		if reviewVersions, ok := webhook["conversionReviewVersions"]; ok {
+			conversion["conversionReviewVersions"] = reviewVersions
+		}
+		spec["conversion"] = conversion
+	}
+	crd["apiVersion"] = apiextensionsV1beta1
+	return nil
+}
+
+// disableConversionWebhook replaces the Webhook conversion strategy of a v1 or v1beta1
+// CRD with None and stops serving the versions other than the storage version, as
+// the API server cannot convert objects between them without the webhook.
+func disableConversionWebhook(crd map[string]interface{}) {
+	if getConversionStrategy(crd) != "Webhook" {
+		return
+	}
+	unstructured.SetNestedMap(crd, map[string]interface{}{"strategy": "None"}, "spec", "conversion")
+	versions, _, _ := unstructured.NestedSlice(crd, "spec", "versions")
+	for _, v := range versions {
+		if version, ok := v.(map[string]interface{}); ok && version["storage"] != true {
+			version["served"] = false
+		}
+	}
+	unstructured.SetNestedSlice(crd, versions, "spec", "versions")
+}
+
+// getConversionStrategy returns the conversion strategy of a v1 or v1beta1 CRD.
+func getConversionStrategy(crd map[string]interface{}) string {
+	strategy, _, _ := unstructured.NestedString(crd, "spec", "conversion", "strategy")
+	if len(strategy) == 0 {
+		return "None"
+	}
+	return strategy
+}
+
+// setConversionServiceNamespace sets the namespace of the conversion webhook Service
+// of a v1 or v1beta1 CRD.
+func setConversionServiceNamespace(crd map[string]interface{}, namespace string) {
+	for _, path := range [][]string{
+		{"spec", "conversion", "webhook", "clientConfig", "service"},
+		{"spec", "conversion", "webhookClientConfig", "service"},
+	} {
+		if _, ok, _ := unstructured.NestedMap(crd, path...); ok {
+			unstructured.SetNestedField(crd, namespace, append(path, "namespace")...)
+		}
+	}
+}
+
+func renamePrinterColumnPath(columns interface{}, from string, to string) interface{} {
+	list, ok := columns.([]interface{})
+	if !ok {
+		return columns
+	}
+	for _, c := range list {
+		if column, ok := c.(map[string]interface{}); ok {
+			if path, ok := column[from]; ok {
+				column[to] = path
+				delete(column, from)
+			}
+		}
+	}
+	return list
+}
+
+// deepCopyJSONValue copies maps and slices produced by JSON or YAML decoding.
+func deepCopyJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			copied[key] = deepCopyJSONValue(val)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, val := range v {
+			copied[i] = deepCopyJSONValue(val)
+		}
+		return copied
+	default:
+		return v
+	}
+}
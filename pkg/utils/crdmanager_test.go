@@ -0,0 +1,174 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestIsNewerVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		shipped   string
+		installed string
+		want      bool
+	}{
+		{name: "equal", shipped: "0.8.0", installed: "0.8.0", want: false},
+		{name: "equal with prefix", shipped: "v0.8.0", installed: "0.8.0", want: false},
+		{name: "older", shipped: "0.7.1", installed: "0.8.0", want: false},
+		{name: "newer patch", shipped: "0.8.1", installed: "0.8.0", want: true},
+		{name: "newer minor compared numerically", shipped: "0.10.0", installed: "0.9.0", want: true},
+		{name: "newer with more components", shipped: "0.8.0.1", installed: "0.8.0", want: true},
+		{name: "same with fewer components", shipped: "0.8", installed: "0.8.0", want: false},
+		{name: "missing annotation", shipped: "0.8.0", installed: "", want: true},
+		{name: "malformed installed", shipped: "0.8.0", installed: "dev", want: true},
+		{name: "malformed shipped", shipped: "0.8.0-rc1", installed: "0.8.0", want: true},
+		{name: "malformed equal", shipped: "dev", installed: "dev", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isNewerVersion(test.shipped, test.installed); got != test.want {
+				t.Errorf("isNewerVersion(%q, %q) = %t, want %t", test.shipped, test.installed, got, test.want)
+			}
+		})
+	}
+}
+
+const testV1beta1CRD = `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: kappnavs.kappnav.operator.kappnav.io
+spec:
+  group: kappnav.operator.kappnav.io
+  names:
+    kind: Kappnav
+    plural: kappnavs
+  scope: Namespaced
+  validation:
+    openAPIV3Schema:
+      type: object
+      properties:
+        spec:
+          type: object
+  subresources:
+    status: {}
+  additionalPrinterColumns:
+  - name: URL
+    type: string
+    JSONPath: .status.url
+  conversion:
+    strategy: Webhook
+    webhookClientConfig:
+      service:
+        name: kappnav-operator-webhook
+        namespace: kappnav
+        path: /convert
+    conversionReviewVersions:
+    - v1beta1
+  versions:
+  - name: v1
+    served: true
+    storage: true
+  - name: v2
+    served: true
+    storage: false
+`
+
+func unmarshalCRD(t *testing.T, data []byte) map[string]interface{} {
+	crd := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &crd); err != nil {
+		t.Fatalf("failed to unmarshal the CRD: %s", err)
+	}
+	return crd
+}
+
+func TestConvertCRDToV1(t *testing.T) {
+	crd := unmarshalCRD(t, []byte(testV1beta1CRD))
+	if err := convertCRD(crd, apiextensionsV1); err != nil {
+		t.Fatalf("convertCRD failed: %s", err)
+	}
+	spec := crd["spec"].(map[string]interface{})
+	for _, field := range []string{"validation", "subresources", "additionalPrinterColumns"} {
+		if _, ok := spec[field]; ok {
+			t.Errorf("the v1 CRD has the top-level field %s", field)
+		}
+	}
+	for _, v := range spec["versions"].([]interface{}) {
+		version := v.(map[string]interface{})
+		if _, ok := version["schema"]; !ok {
+			t.Errorf("version %s has no schema", version["name"])
+		}
+		columns := version["additionalPrinterColumns"].([]interface{})
+		if path := columns[0].(map[string]interface{})["jsonPath"]; path != ".status.url" {
+			t.Errorf("version %s has printer column path %v, want .status.url", version["name"], path)
+		}
+	}
+	webhook := spec["conversion"].(map[string]interface{})["webhook"].(map[string]interface{})
+	if _, ok := webhook["clientConfig"]; !ok {
+		t.Error("the conversion webhook has no client config")
+	}
+}
+
+func TestConvertCRDRoundTrip(t *testing.T) {
+	original := unmarshalCRD(t, []byte(testV1beta1CRD))
+	crd := unmarshalCRD(t, []byte(testV1beta1CRD))
+	if err := convertCRD(crd, apiextensionsV1); err != nil {
+		t.Fatalf("convertCRD to v1 failed: %s", err)
+	}
+	if err := convertCRD(crd, apiextensionsV1beta1); err != nil {
+		t.Fatalf("convertCRD to v1beta1 failed: %s", err)
+	}
+	if !reflect.DeepEqual(original, crd) {
+		t.Errorf("the round trip changed the CRD:\ngot  %v\nwant %v", crd, original)
+	}
+}
+
+func TestConvertShippedCRDsRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("../../deploy/crds/*_crd.yaml")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no CRDs found: %v", err)
+	}
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatalf("failed to read the CRD: %s", err)
+			}
+			original := unmarshalCRD(t, data)
+			crd := unmarshalCRD(t, data)
+			if err := convertCRD(crd, apiextensionsV1beta1); err != nil {
+				t.Fatalf("convertCRD to v1beta1 failed: %s", err)
+			}
+			if err := convertCRD(crd, apiextensionsV1); err != nil {
+				t.Fatalf("convertCRD to v1 failed: %s", err)
+			}
+			if !reflect.DeepEqual(original, crd) {
+				t.Errorf("the round trip changed the CRD:\ngot  %v\nwant %v", crd, original)
+			}
+		})
+	}
+}
+
+func TestConvertCRDUnsupportedVersion(t *testing.T) {
+	crd := unmarshalCRD(t, []byte(testV1beta1CRD))
+	if err := convertCRD(crd, "apiextensions.k8s.io/v2"); err == nil {
+		t.Error("convertCRD converted to an unknown version")
+	}
+}
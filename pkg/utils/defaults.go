@@ -48,6 +48,7 @@ func getDefaults() (*kappnavv1.Kappnav, error) {
 	if err != nil {
 		return nil, err
 	}
+	setRelatedImageDefaults(defaults)
 	return defaults, nil
 }
 
@@ -99,12 +100,33 @@ func setExtensionContainerDefaults(instance *kappnavv1.Kappnav, defaults *kappna
 
 func setContainerDefaults(containerConfig *kappnavv1.KappnavContainerConfiguration,
 	defaultContainerConfig *kappnavv1.KappnavContainerConfiguration) {
+	// The default digest applies unless the CR selects its own tag or digest, so that
+	// a CR that only overrides the repository, e.g. with a mirror, pulls the default
+	// image from it.
+	if len(containerConfig.Tag) == 0 && len(containerConfig.Digest) == 0 {
+		containerConfig.Digest = defaultContainerConfig.Digest
+	}
 	if len(containerConfig.Repository) == 0 {
 		containerConfig.Repository = defaultContainerConfig.Repository
 	}
 	if len(containerConfig.Tag) == 0 {
 		containerConfig.Tag = defaultContainerConfig.Tag
 	}
+	if len(containerConfig.Target) == 0 {
+		containerConfig.Target = defaultContainerConfig.Target
+	}
+	if containerConfig.Ports == nil {
+		containerConfig.Ports = defaultContainerConfig.Ports
+	}
+	if containerConfig.Service == nil {
+		containerConfig.Service = defaultContainerConfig.Service
+	}
+	if containerConfig.ReadinessProbe == nil {
+		containerConfig.ReadinessProbe = defaultContainerConfig.ReadinessProbe
+	}
+	if containerConfig.LivenessProbe == nil {
+		containerConfig.LivenessProbe = defaultContainerConfig.LivenessProbe
+	}
 	if containerConfig.Resources == nil {
 		containerConfig.Resources = defaultContainerConfig.Resources
 	} else {
@@ -143,6 +165,9 @@ func setImageDefaults(instance *kappnavv1.Kappnav, defaults *kappnavv1.Kappnav)
 		if image.PullSecrets == nil {
 			image.PullSecrets = defaults.Spec.Image.PullSecrets
 		}
+		if len(image.Registry) == 0 {
+			image.Registry = defaults.Spec.Image.Registry
+		}
 	}
 }
 
@@ -0,0 +1,226 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// ExtensionContainerLabel is set on the Deployments and Services of the
+	// extension containers to the key of the container
+	ExtensionContainerLabel string = "kappnav.io/extension-container"
+)
+
+// GetExtensionContainerName converts an extension container key such as
+// "appNavInv" into the name of its container, app-nav-inv.
+func GetExtensionContainerName(key string) string {
+	var b strings.Builder
+	for i, c := range key {
+		if unicode.IsUpper(c) && i > 0 {
+			b.WriteRune('-')
+		}
+		b.WriteRune(unicode.ToLower(c))
+	}
+	return b.String()
+}
+
+// GetExtensionContainerResourceName returns the name of the Deployment and the
+// Service of an extension container
+func GetExtensionContainerResourceName(instance *kappnavv1.Kappnav, key string) string {
+	return instance.GetName() + "-" + GetExtensionContainerName(key)
+}
+
+// GetExtensionContainerKeys returns the sorted keys of the extension containers
+// deployed to target. The oauth-proxy is managed with the UI and is never
+// returned.
+func GetExtensionContainerKeys(instance *kappnavv1.Kappnav, target kappnavv1.ExtensionContainerTarget) []string {
+	var keys []string
+	for key, containerConfig := range instance.Spec.ExtensionContainers {
+		if key == OAuthProxyContainerConfigKey || containerConfig == nil || containerConfig.Target != target {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// IsExtensionContainerServiceEnabled returns true if the ports of an extension
+// container are exposed in a Service
+func IsExtensionContainerServiceEnabled(containerConfig *kappnavv1.KappnavContainerConfiguration) bool {
+	return containerConfig != nil && len(containerConfig.Target) > 0 &&
+		containerConfig.Service != nil && containerConfig.Service.Enabled && len(containerConfig.Ports) > 0
+}
+
+// addTargetedExtensionContainers adds the extension containers targeted at the
+// UI or controller pod to the containers of the pod
+func addTargetedExtensionContainers(containers []corev1.Container, existingContainers []corev1.Container,
+	instance *kappnavv1.Kappnav, target kappnavv1.ExtensionContainerTarget) []corev1.Container {
+	for _, key := range GetExtensionContainerKeys(instance, target) {
+		containers = append(containers, *createExtensionContainer(key, existingContainers, instance))
+	}
+	return containers
+}
+
+// CreateExtensionContainerDeploymentContainers returns the containers of the
+// Deployment of an extension container
+func CreateExtensionContainerDeploymentContainers(existingContainers []corev1.Container, instance *kappnavv1.Kappnav, key string) []corev1.Container {
+	return []corev1.Container{*createExtensionContainer(key, existingContainers, instance)}
+}
+
+// CreateExtensionContainerVolumes returns the volumes of the Deployment of an
+// extension container
+func CreateExtensionContainerVolumes(instance *kappnavv1.Kappnav) []corev1.Volume {
+	var volumes []corev1.Volume
+	if trustedCAVolume := createTrustedCAVolume(instance); trustedCAVolume != nil {
+		volumes = append(volumes, *trustedCAVolume)
+	}
+	return volumes
+}
+
+// CustomizeExtensionContainerDeployment labels the Deployment of an extension
+// container
+func CustomizeExtensionContainerDeployment(deploy *appsv1.Deployment, instance *kappnavv1.Kappnav, key string) {
+	CustomizeDeployment(deploy, instance)
+	deploy.Labels[ExtensionContainerLabel] = key
+}
+
+// CustomizeExtensionContainerService exposes the ports of an extension container
+// in the pod the container runs in
+func CustomizeExtensionContainerService(service *corev1.Service, instance *kappnavv1.Kappnav, key string) {
+	containerConfig := instance.Spec.ExtensionContainers[key]
+	CustomizeService(service, instance, containerConfig.Service.Annotations)
+	service.Labels[ExtensionContainerLabel] = key
+
+	service.Spec.Type = containerConfig.Service.Type
+	if len(service.Spec.Type) == 0 {
+		service.Spec.Type = corev1.ServiceTypeClusterIP
+	}
+	var ports []corev1.ServicePort
+	for _, port := range containerConfig.Ports {
+		servicePort := corev1.ServicePort{
+			Name:       port.Name,
+			Port:       port.Port,
+			TargetPort: intstr.FromString(port.Name),
+			Protocol:   getExtensionContainerProtocol(port),
+		}
+		// Keep the node port allocated to the Service
+		for _, existing := range service.Spec.Ports {
+			if existing.Name == port.Name && service.Spec.Type != corev1.ServiceTypeClusterIP {
+				servicePort.NodePort = existing.NodePort
+			}
+		}
+		ports = append(ports, servicePort)
+	}
+	service.Spec.Ports = ports
+
+	deploymentName := GetExtensionContainerResourceName(instance, key)
+	switch containerConfig.Target {
+	case kappnavv1.ExtensionContainerTargetUI:
+		deploymentName = instance.GetName() + "-ui"
+	case kappnavv1.ExtensionContainerTargetController:
+		deploymentName = instance.GetName() + "-controller"
+	}
+	service.Spec.Selector = map[string]string{
+		"app.kubernetes.io/component": deploymentName,
+	}
+}
+
+// GetExtensionContainerSelector returns the selector of the Deployments and
+// Services of the extension containers of a CR
+func GetExtensionContainerSelector(instance *kappnavv1.Kappnav) (labels.Selector, error) {
+	return labels.Parse("app.kubernetes.io/instance=" + instance.GetName() +
+		",app.kubernetes.io/managed-by=kappnav-operator," + ExtensionContainerLabel)
+}
+
+// createExtensionContainer creates an extension container with the same image,
+// environment, trusted CA, proxy, resource and security handling as the
+// kAppNav containers
+func createExtensionContainer(key string, existingContainers []corev1.Container, instance *kappnavv1.Kappnav) *corev1.Container {
+	name := GetExtensionContainerName(key)
+	var existingEnv []corev1.EnvVar
+	for _, c := range existingContainers {
+		if c.Name == name {
+			existingEnv = c.Env
+		}
+	}
+	containerConfig := instance.Spec.ExtensionContainers[key].DeepCopy()
+	// Extension containers without defaults may leave the resources unset
+	if containerConfig.Resources == nil || (containerConfig.Resources.Enabled &&
+		(containerConfig.Resources.Requests == nil || containerConfig.Resources.Limits == nil)) {
+		containerConfig.Resources = &kappnavv1.KappnavResourceConstraints{}
+	}
+	return createContainer(name, instance, containerConfig, existingEnv,
+		createExtensionContainerProbe(containerConfig.ReadinessProbe),
+		createExtensionContainerProbe(containerConfig.LivenessProbe),
+		createExtensionContainerPorts(containerConfig), nil, nil)
+}
+
+func createExtensionContainerPorts(containerConfig *kappnavv1.KappnavContainerConfiguration) []corev1.ContainerPort {
+	var ports []corev1.ContainerPort
+	for _, port := range containerConfig.Ports {
+		ports = append(ports, corev1.ContainerPort{
+			ContainerPort: port.Port,
+			Name:          port.Name,
+			Protocol:      getExtensionContainerProtocol(port),
+		})
+	}
+	return ports
+}
+
+func getExtensionContainerProtocol(port kappnavv1.KappnavContainerPort) corev1.Protocol {
+	if len(port.Protocol) == 0 {
+		return corev1.ProtocolTCP
+	}
+	return port.Protocol
+}
+
+func createExtensionContainerProbe(probe *kappnavv1.KappnavContainerProbe) *corev1.Probe {
+	if probe == nil {
+		return nil
+	}
+	handler := corev1.Handler{
+		TCPSocket: &corev1.TCPSocketAction{
+			Port: intstr.FromInt(int(probe.Port)),
+		},
+	}
+	if len(probe.Path) > 0 {
+		scheme := probe.Scheme
+		if len(scheme) == 0 {
+			scheme = corev1.URISchemeHTTP
+		}
+		handler = corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:   probe.Path,
+				Scheme: scheme,
+				Port:   intstr.FromInt(int(probe.Port)),
+			},
+		}
+	}
+	return &corev1.Probe{
+		Handler:             handler,
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		FailureThreshold:    probe.FailureThreshold,
+	}
+}
@@ -13,7 +13,238 @@ limitations under the License.
 
 package utils
 
-// NewKappnavExtension ...
-func NewKappnavExtension() KappnavExtension {
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// ExtensionTargetUI is the UI Deployment of a CR
+	ExtensionTargetUI string = "ui"
+	// ExtensionTargetController is the controller Deployment of a CR
+	ExtensionTargetController string = "controller"
+	// ExtensionsFinalizer holds the deletion of a CR until the deletion hooks of the
+	// extensions have run
+	ExtensionsFinalizer string = "kappnav.operator.kappnav.io/extensions"
+)
+
+// A KappnavExtension may implement any of the following interfaces to extend the
+// operator further. The operator checks for them when the extension is registered.
+
+// WatchExtension is implemented by extensions that manage resources of types the
+// operator does not watch. A change to a resource of these types that is owned by
+// a Kappnav CR reconciles the CR.
+type WatchExtension interface {
+	WatchedTypes() []runtime.Object
+}
+
+// PodContribution is what an extension adds to the pod of a Deployment
+type PodContribution struct {
+	// Containers are added to the pod
+	Containers []corev1.Container
+	// Volumes are added to the pod
+	Volumes []corev1.Volume
+	// VolumeMounts are added to the kAppNav containers of the pod
+	VolumeMounts []corev1.VolumeMount
+}
+
+// PodExtension is implemented by extensions that add containers or volumes to the
+// UI and controller pods
+type PodExtension interface {
+	// PodContribution returns what the extension adds to the pod of a Deployment,
+	// ExtensionTargetUI or ExtensionTargetController
+	PodContribution(instance *kappnavv1.Kappnav, target string) PodContribution
+}
+
+// MapDataExtension is implemented by extensions that provide data to the
+// templates of the action, section and status config maps
+type MapDataExtension interface {
+	// MapTemplateData returns the data available to the templates as
+	// .Extensions.<name of the extension>
+	MapTemplateData(instance *kappnavv1.Kappnav) map[string]interface{}
+}
+
+// DeletionExtension is implemented by extensions that must clean up when a CR is
+// deleted, e.g. resources that cannot be owned by the CR because they are cluster
+// scoped or in another namespace. The CR is deleted once the hooks of all the
+// extensions have succeeded.
+type DeletionExtension interface {
+	Delete(logger Logger, r *ReconcilerBase, instance *kappnavv1.Kappnav) error
+}
+
+// ConditionExtension is implemented by extensions that record conditions in the
+// status of the CR. The conditions are set after the components are reconciled.
+// Their times are managed by the operator.
+type ConditionExtension interface {
+	Conditions(logger Logger, r *ReconcilerBase, instance *kappnavv1.Kappnav) []kappnavv1.StatusCondition
+}
+
+// MapTemplateData is the data the templates of the config maps are executed
+// against. The fields of the CR are addressed as before, e.g. .Spec.Env.KubeEnv.
+type MapTemplateData struct {
+	*kappnavv1.Kappnav
+	// Extensions is the data of each extension implementing MapDataExtension, by
+	// name of the extension
+	Extensions map[string]map[string]interface{}
+}
+
+// registeredExtension is an extension and the name it was registered with
+type registeredExtension struct {
+	name      string
+	extension KappnavExtension
+}
+
+var (
+	// registeredExtensionsMutex guards registeredExtensions
+	registeredExtensionsMutex sync.Mutex
+	// registeredExtensions are the extensions in the order they were registered
+	registeredExtensions []registeredExtension
+)
+
+// RegisterKappnavExtension registers an extension as a component named name. The
+// extension is reconciled after the components it depends on, or after all the
+// builtin components when no dependencies are given. Any number of extensions can
+// be registered, typically from init functions.
+func RegisterKappnavExtension(name string, extension KappnavExtension, dependsOn ...string) {
+	if len(dependsOn) == 0 {
+		dependsOn = BuiltinComponents
+	}
+	registeredExtensionsMutex.Lock()
+	registeredExtensions = append(registeredExtensions, registeredExtension{name: name, extension: extension})
+	registeredExtensionsMutex.Unlock()
+	RegisterComponent(NewComponent(name, dependsOn, func(logger Logger, ctx *ComponentContext) (reconcile.Result, error) {
+		return extension.ReconcileAdditionalResources(logger, ctx.Request, ctx.Reconciler, ctx.Instance)
+	}))
+}
+
+// UnregisterKappnavExtension removes the extension and the component registered
+// with name, e.g. to undo the registration of a test
+func UnregisterKappnavExtension(name string) {
+	registeredExtensionsMutex.Lock()
+	var extensions []registeredExtension
+	for _, registered := range registeredExtensions {
+		if registered.name != name {
+			extensions = append(extensions, registered)
+		}
+	}
+	registeredExtensions = extensions
+	registeredExtensionsMutex.Unlock()
+
+	registeredComponentsMutex.Lock()
+	defer registeredComponentsMutex.Unlock()
+	var components []ComponentReconciler
+	for _, component := range registeredComponents {
+		if component.Name() != name {
+			components = append(components, component)
+		}
+	}
+	registeredComponents = components
+}
+
+// getRegisteredExtensions returns the registered extensions in the order they
+// were registered
+func getRegisteredExtensions() []registeredExtension {
+	registeredExtensionsMutex.Lock()
+	defer registeredExtensionsMutex.Unlock()
+	return append([]registeredExtension(nil), registeredExtensions...)
+}
+
+// GetExtensionWatchedTypes returns the types watched for the extensions
+func GetExtensionWatchedTypes() []runtime.Object {
+	var types []runtime.Object
+	for _, registered := range getRegisteredExtensions() {
+		if e, ok := registered.extension.(WatchExtension); ok {
+			types = append(types, e.WatchedTypes()...)
+		}
+	}
+	return types
+}
+
+// getExtensionPodContribution merges what the extensions add to the pod of a
+// Deployment
+func getExtensionPodContribution(instance *kappnavv1.Kappnav, target string) PodContribution {
+	var merged PodContribution
+	for _, registered := range getRegisteredExtensions() {
+		if e, ok := registered.extension.(PodExtension); ok {
+			contribution := e.PodContribution(instance, target)
+			merged.Containers = append(merged.Containers, contribution.Containers...)
+			merged.Volumes = append(merged.Volumes, contribution.Volumes...)
+			merged.VolumeMounts = append(merged.VolumeMounts, contribution.VolumeMounts...)
+		}
+	}
+	return merged
+}
+
+// addExtensionContainers adds the containers of the extensions to the kAppNav
+// containers of a pod and mounts their volumes in the kAppNav containers
+func addExtensionContainers(containers []corev1.Container, instance *kappnavv1.Kappnav, target string) []corev1.Container {
+	contribution := getExtensionPodContribution(instance, target)
+	for i := range containers {
+		containers[i].VolumeMounts = append(containers[i].VolumeMounts, contribution.VolumeMounts...)
+	}
+	return append(containers, contribution.Containers...)
+}
+
+// GetMapTemplateData returns the data the templates of the config maps are
+// executed against
+func GetMapTemplateData(instance *kappnavv1.Kappnav) *MapTemplateData {
+	data := &MapTemplateData{Kappnav: instance, Extensions: make(map[string]map[string]interface{})}
+	for _, registered := range getRegisteredExtensions() {
+		if e, ok := registered.extension.(MapDataExtension); ok {
+			data.Extensions[registered.name] = e.MapTemplateData(instance)
+		}
+	}
+	return data
+}
+
+// HasDeletionExtensions returns true if an extension has a deletion hook
+func HasDeletionExtensions() bool {
+	for _, registered := range getRegisteredExtensions() {
+		if _, ok := registered.extension.(DeletionExtension); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RunDeletionExtensions runs the deletion hooks of all the extensions. Returns an
+// error naming the extensions whose hook failed.
+func RunDeletionExtensions(logger Logger, r *ReconcilerBase, instance *kappnavv1.Kappnav) error {
+	var messages []string
+	for _, registered := range getRegisteredExtensions() {
+		e, ok := registered.extension.(DeletionExtension)
+		if !ok {
+			continue
+		}
+		if err := e.Delete(logger, r, instance); err != nil {
+			if logger.IsEnabled(LogTypeError) {
+				logger.Log(CallerName(), LogTypeError, fmt.Sprintf("Deletion hook of extension %s failed, Error: %s", registered.name, err), logName)
+			}
+			messages = append(messages, registered.name+": "+err.Error())
+		}
+	}
+	if len(messages) > 0 {
+		return fmt.Errorf("deletion hooks failed: %s", strings.Join(messages, "; "))
+	}
 	return nil
 }
+
+// SetExtensionConditions sets the conditions of the extensions in the status of
+// the CR. The caller is responsible for writing the status.
+func SetExtensionConditions(logger Logger, r *ReconcilerBase, instance *kappnavv1.Kappnav) {
+	for _, registered := range getRegisteredExtensions() {
+		e, ok := registered.extension.(ConditionExtension)
+		if !ok {
+			continue
+		}
+		for _, condition := range e.Conditions(logger, r, instance) {
+			SetComponentCondition(instance, condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// testExtension records its calls and implements MapDataExtension and
+// DeletionExtension
+type testExtension struct {
+	reconciled int
+	deleted    int
+	deleteErr  error
+	data       map[string]interface{}
+}
+
+func (e *testExtension) ReconcileAdditionalResources(logger Logger, request reconcile.Request, r *ReconcilerBase, instance *kappnavv1.Kappnav) (reconcile.Result, error) {
+	e.reconciled++
+	return reconcile.Result{Requeue: true}, nil
+}
+
+func (e *testExtension) MapTemplateData(instance *kappnavv1.Kappnav) map[string]interface{} {
+	return e.data
+}
+
+func (e *testExtension) Delete(logger Logger, r *ReconcilerBase, instance *kappnavv1.Kappnav) error {
+	e.deleted++
+	return e.deleteErr
+}
+
+// plainExtension implements none of the optional extension interfaces
+type plainExtension struct{}
+
+func (e *plainExtension) ReconcileAdditionalResources(logger Logger, request reconcile.Request, r *ReconcilerBase, instance *kappnavv1.Kappnav) (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+// resetRegistrations clears the registered extensions and components for the
+// duration of a test
+func resetRegistrations(t *testing.T) {
+	registeredExtensionsMutex.Lock()
+	registeredComponentsMutex.Lock()
+	extensions, components := registeredExtensions, registeredComponents
+	registeredExtensions, registeredComponents = nil, nil
+	registeredComponentsMutex.Unlock()
+	registeredExtensionsMutex.Unlock()
+	t.Cleanup(func() {
+		registeredExtensionsMutex.Lock()
+		registeredComponentsMutex.Lock()
+		registeredExtensions, registeredComponents = extensions, components
+		registeredComponentsMutex.Unlock()
+		registeredExtensionsMutex.Unlock()
+	})
+}
+
+func TestRegisterKappnavExtension(t *testing.T) {
+	resetRegistrations(t)
+	extension := &testExtension{}
+	RegisterKappnavExtension("Test", extension)
+	RegisterKappnavExtension("Plain", &plainExtension{}, ComponentUI, "Test")
+
+	components := GetRegisteredComponents()
+	if len(components) != 2 {
+		t.Fatalf("got %d registered components, want 2", len(components))
+	}
+	if name := components[0].Name(); name != "Test" {
+		t.Errorf("got component %s, want Test", name)
+	}
+	if dependsOn := components[0].DependsOn(); !reflect.DeepEqual(dependsOn, BuiltinComponents) {
+		t.Errorf("got dependencies %v, want the builtin components %v", dependsOn, BuiltinComponents)
+	}
+	if dependsOn := components[1].DependsOn(); !reflect.DeepEqual(dependsOn, []string{ComponentUI, "Test"}) {
+		t.Errorf("got dependencies %v, want [%s Test]", dependsOn, ComponentUI)
+	}
+
+	ctx := &ComponentContext{Instance: &kappnavv1.Kappnav{}}
+	result, err := components[0].Reconcile(NewLogger(), ctx)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %s", err)
+	}
+	if extension.reconciled != 1 || !result.Requeue {
+		t.Errorf("the component did not reconcile the extension: reconciled %d times, result %+v", extension.reconciled, result)
+	}
+
+	registered := getRegisteredExtensions()
+	if len(registered) != 2 || registered[0].name != "Test" || registered[1].name != "Plain" {
+		t.Errorf("got registered extensions %+v, want Test and Plain in order", registered)
+	}
+	if !HasDeletionExtensions() {
+		t.Error("HasDeletionExtensions returned false with a deletion extension registered")
+	}
+}
+
+func TestHasDeletionExtensionsWithoutHooks(t *testing.T) {
+	resetRegistrations(t)
+	RegisterKappnavExtension("Plain", &plainExtension{})
+	if HasDeletionExtensions() {
+		t.Error("HasDeletionExtensions returned true without a deletion extension registered")
+	}
+}
+
+func TestGetMapTemplateData(t *testing.T) {
+	resetRegistrations(t)
+	RegisterKappnavExtension("Test", &testExtension{data: map[string]interface{}{"budget": "instance-ui"}})
+	RegisterKappnavExtension("Plain", &plainExtension{})
+
+	instance := &kappnavv1.Kappnav{ObjectMeta: metav1.ObjectMeta{Name: "instance"}}
+	data := GetMapTemplateData(instance)
+	if data.Kappnav != instance {
+		t.Error("the template data does not embed the CR")
+	}
+	want := map[string]map[string]interface{}{"Test": {"budget": "instance-ui"}}
+	if !reflect.DeepEqual(data.Extensions, want) {
+		t.Errorf("got extension data %v, want %v", data.Extensions, want)
+	}
+}
+
+func TestRunDeletionExtensions(t *testing.T) {
+	resetRegistrations(t)
+	succeeding := &testExtension{}
+	failing := &testExtension{deleteErr: errors.New("cluster role is still bound")}
+	RegisterKappnavExtension("Succeeding", succeeding)
+	RegisterKappnavExtension("Failing", failing)
+	RegisterKappnavExtension("Plain", &plainExtension{})
+
+	err := RunDeletionExtensions(NewLogger(), &ReconcilerBase{}, &kappnavv1.Kappnav{})
+	if err == nil || !strings.Contains(err.Error(), "Failing: cluster role is still bound") {
+		t.Fatalf("got error %v, want the error of the failing hook", err)
+	}
+	if strings.Contains(err.Error(), "Succeeding") {
+		t.Errorf("the error %s names a hook that succeeded", err)
+	}
+	if succeeding.deleted != 1 || failing.deleted != 1 {
+		t.Errorf("got %d and %d runs of the hooks, want 1 each", succeeding.deleted, failing.deleted)
+	}
+
+	failing.deleteErr = nil
+	if err := RunDeletionExtensions(NewLogger(), &ReconcilerBase{}, &kappnavv1.Kappnav{}); err != nil {
+		t.Errorf("RunDeletionExtensions failed: %s", err)
+	}
+}
+
+func TestUnregisterKappnavExtension(t *testing.T) {
+	resetRegistrations(t)
+	RegisterKappnavExtension("Test", &testExtension{})
+	RegisterKappnavExtension("Plain", &plainExtension{})
+	UnregisterKappnavExtension("Test")
+
+	if registered := getRegisteredExtensions(); len(registered) != 1 || registered[0].name != "Plain" {
+		t.Errorf("got registered extensions %+v, want Plain", registered)
+	}
+	if components := GetRegisteredComponents(); len(components) != 1 || components[0].Name() != "Plain" {
+		t.Errorf("got %d registered components, want Plain", len(components))
+	}
+	if HasDeletionExtensions() {
+		t.Error("HasDeletionExtensions returned true after the deletion extension was unregistered")
+	}
+}
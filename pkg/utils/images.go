@@ -0,0 +1,115 @@
+/*
+Copyright 2019 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"os"
+	"strings"
+	"unicode"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+)
+
+const (
+	// RelatedImageEnvVarPrefix is the prefix of the operator environment variables
+	// that OLM uses to pass (possibly mirrored) image references to the operator.
+	RelatedImageEnvVarPrefix string = "RELATED_IMAGE_"
+	// RelatedImageAPIEnvVarName ...
+	RelatedImageAPIEnvVarName string = RelatedImageEnvVarPrefix + "API"
+	// RelatedImageUIEnvVarName ...
+	RelatedImageUIEnvVarName string = RelatedImageEnvVarPrefix + "UI"
+	// RelatedImageControllerEnvVarName ...
+	RelatedImageControllerEnvVarName string = RelatedImageEnvVarPrefix + "CONTROLLER"
+	// defaultImageTag is the tag of an image reference without a tag or digest
+	defaultImageTag kappnavv1.Tag = "latest"
+)
+
+// GetImageReference returns the image reference for the container configuration.
+// The digest takes precedence over the tag, and the registry host of the repository
+// is replaced when a global registry is configured.
+func GetImageReference(instance *kappnavv1.Kappnav, containerConfig *kappnavv1.KappnavContainerConfiguration) string {
+	repository := string(containerConfig.Repository)
+	if instance.Spec.Image != nil && len(instance.Spec.Image.Registry) > 0 {
+		repository = rewriteRegistry(repository, instance.Spec.Image.Registry)
+	}
+	if len(containerConfig.Digest) > 0 {
+		return repository + "@" + string(containerConfig.Digest)
+	}
+	if len(containerConfig.Tag) == 0 {
+		return repository
+	}
+	return repository + ":" + string(containerConfig.Tag)
+}
+
+// rewriteRegistry replaces the registry host of the repository with registry,
+// or prefixes the repository with registry when it has no registry host.
+func rewriteRegistry(repository string, registry string) string {
+	registry = strings.TrimSuffix(registry, "/")
+	parts := strings.SplitN(repository, "/", 2)
+	if len(parts) == 2 && isRegistryHost(parts[0]) {
+		return registry + "/" + parts[1]
+	}
+	return registry + "/" + repository
+}
+
+// isRegistryHost follows the docker convention: the first path component of a
+// repository is a registry host if it contains a '.' or a ':' or is localhost.
+func isRegistryHost(component string) bool {
+	return strings.ContainsAny(component, ".:") || component == "localhost"
+}
+
+// parseImageReference splits an image reference into its repository, tag and digest.
+// A reference with neither a tag nor a digest has the tag latest, as in docker.
+func parseImageReference(image string) (kappnavv1.Repository, kappnavv1.Tag, kappnavv1.Digest) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return kappnavv1.Repository(image[:i]), "", kappnavv1.Digest(image[i+1:])
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return kappnavv1.Repository(image[:i]), kappnavv1.Tag(image[i+1:]), ""
+	}
+	return kappnavv1.Repository(image), defaultImageTag, ""
+}
+
+// relatedImageEnvVarName converts an extension container key such as "oauthProxy"
+// into the name of its related image environment variable, RELATED_IMAGE_OAUTH_PROXY.
+func relatedImageEnvVarName(key string) string {
+	var b strings.Builder
+	b.WriteString(RelatedImageEnvVarPrefix)
+	for i, c := range key {
+		if unicode.IsUpper(c) && i > 0 {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(c))
+	}
+	return b.String()
+}
+
+// setRelatedImageDefaults replaces the default images with the images passed
+// to the operator through RELATED_IMAGE_* environment variables.
+func setRelatedImageDefaults(defaults *kappnavv1.Kappnav) {
+	setRelatedImage(defaults.Spec.AppNavAPI, RelatedImageAPIEnvVarName)
+	setRelatedImage(defaults.Spec.AppNavUI, RelatedImageUIEnvVarName)
+	setRelatedImage(defaults.Spec.AppNavController, RelatedImageControllerEnvVarName)
+	for key, containerConfig := range defaults.Spec.ExtensionContainers {
+		setRelatedImage(containerConfig, relatedImageEnvVarName(key))
+	}
+}
+
+func setRelatedImage(containerConfig *kappnavv1.KappnavContainerConfiguration, envVarName string) {
+	image := os.Getenv(envVarName)
+	if containerConfig == nil || len(image) == 0 {
+		return
+	}
+	containerConfig.Repository, containerConfig.Tag, containerConfig.Digest = parseImageReference(image)
+}
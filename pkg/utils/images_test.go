@@ -0,0 +1,101 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+)
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		image      string
+		repository kappnavv1.Repository
+		tag        kappnavv1.Tag
+		digest     kappnavv1.Digest
+	}{
+		{image: "quay.io/kappnav/ui:0.1.0", repository: "quay.io/kappnav/ui", tag: "0.1.0"},
+		{image: "quay.io/kappnav/ui@sha256:0123", repository: "quay.io/kappnav/ui", digest: "sha256:0123"},
+		{image: "quay.io/kappnav/ui", repository: "quay.io/kappnav/ui", tag: "latest"},
+		{image: "kappnav/ui", repository: "kappnav/ui", tag: "latest"},
+		{image: "nginx:1.17", repository: "nginx", tag: "1.17"},
+		{image: "registry.example.com:5000/kappnav/ui", repository: "registry.example.com:5000/kappnav/ui", tag: "latest"},
+		{image: "registry.example.com:5000/kappnav/ui:0.1.0", repository: "registry.example.com:5000/kappnav/ui", tag: "0.1.0"},
+		{image: "localhost:5000/ui@sha256:0123", repository: "localhost:5000/ui", digest: "sha256:0123"},
+	}
+	for _, test := range tests {
+		t.Run(test.image, func(t *testing.T) {
+			repository, tag, digest := parseImageReference(test.image)
+			if repository != test.repository || tag != test.tag || digest != test.digest {
+				t.Errorf("got %q, %q, %q, want %q, %q, %q", repository, tag, digest, test.repository, test.tag, test.digest)
+			}
+		})
+	}
+}
+
+func TestRewriteRegistry(t *testing.T) {
+	tests := []struct {
+		repository string
+		registry   string
+		want       string
+	}{
+		{repository: "quay.io/kappnav/ui", registry: "mirror.example.com", want: "mirror.example.com/kappnav/ui"},
+		{repository: "registry.example.com:5000/kappnav/ui", registry: "mirror.example.com/", want: "mirror.example.com/kappnav/ui"},
+		{repository: "localhost/kappnav/ui", registry: "mirror.example.com:5000", want: "mirror.example.com:5000/kappnav/ui"},
+		{repository: "kappnav/ui", registry: "mirror.example.com", want: "mirror.example.com/kappnav/ui"},
+		{repository: "nginx", registry: "mirror.example.com", want: "mirror.example.com/nginx"},
+	}
+	for _, test := range tests {
+		t.Run(test.repository, func(t *testing.T) {
+			if got := rewriteRegistry(test.repository, test.registry); got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRelatedImageEnvVarName(t *testing.T) {
+	tests := map[string]string{
+		"oauthProxy": "RELATED_IMAGE_OAUTH_PROXY",
+		"appNavInv":  "RELATED_IMAGE_APP_NAV_INV",
+		"extension":  "RELATED_IMAGE_EXTENSION",
+		"Extension":  "RELATED_IMAGE_EXTENSION",
+	}
+	for key, want := range tests {
+		if got := relatedImageEnvVarName(key); got != want {
+			t.Errorf("got %s for %s, want %s", got, key, want)
+		}
+	}
+}
+
+func TestGetImageReference(t *testing.T) {
+	instance := &kappnavv1.Kappnav{Spec: kappnavv1.KappnavSpec{Image: &kappnavv1.KappnavImageConfiguration{}}}
+	tests := []struct {
+		name   string
+		config kappnavv1.KappnavContainerConfiguration
+		want   string
+	}{
+		{name: "tag", config: kappnavv1.KappnavContainerConfiguration{Repository: "kappnav/ui", Tag: "0.1.0"}, want: "kappnav/ui:0.1.0"},
+		{name: "digest", config: kappnavv1.KappnavContainerConfiguration{Repository: "kappnav/ui", Tag: "0.1.0", Digest: "sha256:0123"}, want: "kappnav/ui@sha256:0123"},
+		{name: "no tag", config: kappnavv1.KappnavContainerConfiguration{Repository: "kappnav/ui"}, want: "kappnav/ui"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := GetImageReference(instance, &test.config); got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
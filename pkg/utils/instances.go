@@ -0,0 +1,186 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"net/http"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// InstanceNamespaceLabel is set on the cluster scoped resources of a CR to the
+	// namespace of the CR. app.kubernetes.io/instance holds the name of the CR.
+	InstanceNamespaceLabel string = "kappnav.io/instance-namespace"
+	// InstanceConflictReason is the reason of the Reconciled condition of a CR that
+	// is refused because it would take over the resources of another CR
+	InstanceConflictReason metav1.StatusReason = "InstanceConflict"
+)
+
+const (
+	// ApplicationNameEnvVarName passes the name of the kappnav Application to the
+	// kAppNav containers
+	ApplicationNameEnvVarName string = "KAPPNAV_APPLICATION_NAME"
+	// BuiltinConfigMapEnvVarName passes the name of the builtin config map
+	BuiltinConfigMapEnvVarName string = "KAPPNAV_BUILTIN_CONFIG_MAP"
+	// KappnavConfigMapEnvVarName passes the name of the kappnav-config config map
+	KappnavConfigMapEnvVarName string = "KAPPNAV_CONFIG_MAP"
+	// ConfigMapPrefixEnvVarName passes the prefix of the names of the action,
+	// section and status config maps
+	ConfigMapPrefixEnvVarName string = "KAPPNAV_CONFIG_MAP_PREFIX"
+	// KAMNameEnvVarName passes the name of the default KindActionMapping
+	KAMNameEnvVarName string = "KAPPNAV_KAM_NAME"
+)
+
+// InstanceNames are the names of the resources of a CR that have well-known
+// names in the Shared isolation mode
+type InstanceNames struct {
+	// Application is the name of the kappnav Application
+	Application string
+	// BuiltinConfigMap is the name of the builtin config map
+	BuiltinConfigMap string
+	// KappnavConfigMap is the name of the kappnav-config config map
+	KappnavConfigMap string
+	// ConfigMapPrefix prefixes the names of the action, section and status config
+	// maps shipped in the image
+	ConfigMapPrefix string
+	// KAM is the name of the default KindActionMapping
+	KAM string
+	// ClusterRoleBinding is the name of the cluster role binding
+	ClusterRoleBinding string
+}
+
+// ConfigMapName returns the name of a shipped action, section or status config
+// map of the CR
+func (n *InstanceNames) ConfigMapName(name string) string {
+	return n.ConfigMapPrefix + name
+}
+
+// IsInstanceIsolated returns true if the names of the resources of the CR are
+// prefixed with the name of the CR
+func IsInstanceIsolated(instance *kappnavv1.Kappnav) bool {
+	return instance.Spec.Isolation == kappnavv1.IsolationModeInstance
+}
+
+// GetInstanceNames returns the names of the resources of the CR in its isolation
+// mode
+func GetInstanceNames(instance *kappnavv1.Kappnav) *InstanceNames {
+	return GetInstanceNamesForMode(instance, instance.Spec.Isolation)
+}
+
+// GetInstanceNamesForMode returns the names of the resources of the CR in an
+// isolation mode. The Shared mode is used unless Instance is given.
+func GetInstanceNamesForMode(instance *kappnavv1.Kappnav, mode kappnavv1.IsolationMode) *InstanceNames {
+	names := &InstanceNames{
+		Application:        "kappnav",
+		BuiltinConfigMap:   "builtin",
+		KappnavConfigMap:   "kappnav-config",
+		KAM:                "default",
+		ClusterRoleBinding: instance.GetName() + "-" + instance.GetNamespace() + "-crb",
+	}
+	if mode == kappnavv1.IsolationModeInstance {
+		names.Application = instance.GetName() + "-" + names.Application
+		names.BuiltinConfigMap = instance.GetName() + "-" + names.BuiltinConfigMap
+		names.KappnavConfigMap = instance.GetName() + "-" + names.KappnavConfigMap
+		names.ConfigMapPrefix = instance.GetName() + "."
+		names.KAM = instance.GetName() + "-" + names.KAM
+	}
+	return names
+}
+
+// GetUnusedInstanceNames returns the names of the resources of the CR in the
+// isolation mode it does not use, which are left over when the mode changes
+func GetUnusedInstanceNames(instance *kappnavv1.Kappnav) *InstanceNames {
+	if IsInstanceIsolated(instance) {
+		return GetInstanceNamesForMode(instance, kappnavv1.IsolationModeShared)
+	}
+	return GetInstanceNamesForMode(instance, kappnavv1.IsolationModeInstance)
+}
+
+// NewInstanceConflictError returns the error of a CR that conflicts with another
+func NewInstanceConflictError(message string) error {
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusConflict,
+		Reason:  InstanceConflictReason,
+		Message: message,
+	}}
+}
+
+// CheckInstanceConflicts returns an InstanceConflict error if the CR would take
+// over the resources of another CR: a CR in the Shared mode conflicts with the
+// older CRs in the Shared mode of its namespace, and any CR conflicts with a CR
+// of another namespace whose cluster role binding has the same name.
+func CheckInstanceConflicts(logger Logger, r *ReconcilerBase, instance *kappnavv1.Kappnav) error {
+	if !IsInstanceIsolated(instance) {
+		instances := &kappnavv1.KappnavList{}
+		err := r.GetClient().List(logger.Context(), &client.ListOptions{Namespace: instance.GetNamespace()}, instances)
+		if err != nil {
+			return err
+		}
+		for i := range instances.Items {
+			other := &instances.Items[i]
+			if other.GetUID() == instance.GetUID() || other.GetDeletionTimestamp() != nil ||
+				IsInstanceIsolated(other) || !isOlderInstance(other, instance) {
+				continue
+			}
+			return NewInstanceConflictError(fmt.Sprintf("Kappnav %s already uses the shared resource names of namespace %s, "+
+				"set spec.isolation to %s to run more than one Kappnav in a namespace",
+				other.GetName(), instance.GetNamespace(), kappnavv1.IsolationModeInstance))
+		}
+	}
+
+	names := GetInstanceNames(instance)
+	crb := &rbacv1.ClusterRoleBinding{}
+	err := r.GetClient().Get(logger.Context(), client.ObjectKey{Name: names.ClusterRoleBinding}, crb)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	namespace, ok := crb.Labels[InstanceNamespaceLabel]
+	if ok && (namespace != instance.GetNamespace() || crb.Labels["app.kubernetes.io/instance"] != instance.GetName()) {
+		return NewInstanceConflictError(fmt.Sprintf("ClusterRoleBinding %s belongs to Kappnav %s in namespace %s",
+			names.ClusterRoleBinding, crb.Labels["app.kubernetes.io/instance"], namespace))
+	}
+	return nil
+}
+
+// isOlderInstance returns true if a was created before b. CRs created in the same
+// second are ordered by name.
+func isOlderInstance(a *kappnavv1.Kappnav, b *kappnavv1.Kappnav) bool {
+	aTime, bTime := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if !aTime.Equal(&bTime) {
+		return aTime.Before(&bTime)
+	}
+	return a.GetName() < b.GetName()
+}
+
+// addInstanceNamesEnv passes the names of the resources of the CR to a container
+func addInstanceNamesEnv(container *corev1.Container, instance *kappnavv1.Kappnav) {
+	names := GetInstanceNames(instance)
+	container.Env = append(container.Env,
+		corev1.EnvVar{Name: ApplicationNameEnvVarName, Value: names.Application},
+		corev1.EnvVar{Name: BuiltinConfigMapEnvVarName, Value: names.BuiltinConfigMap},
+		corev1.EnvVar{Name: KappnavConfigMapEnvVarName, Value: names.KappnavConfigMap},
+		corev1.EnvVar{Name: ConfigMapPrefixEnvVarName, Value: names.ConfigMapPrefix},
+		corev1.EnvVar{Name: KAMNameEnvVarName, Value: names.KAM})
+}
@@ -0,0 +1,379 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// ManagedByLabel is set by GetLabels on all the resources created by the
+	// operator
+	ManagedByLabel string = "app.kubernetes.io/managed-by"
+	// ManagedByOperator is the value of ManagedByLabel
+	ManagedByOperator string = "kappnav-operator"
+	// defaultCacheResync is the resync period of the informers when the manager
+	// does not set one, the default of controller-runtime
+	defaultCacheResync = 10 * time.Hour
+)
+
+// GetManagedBySelector returns the selector of the resources created by the
+// operator
+func GetManagedBySelector() labels.Selector {
+	return labels.SelectorFromSet(labels.Set{ManagedByLabel: ManagedByOperator})
+}
+
+// GetLabelFilteredTypes returns the types of the secondary resources that are
+// only cached when they have the managed-by label of the operator. These types
+// are plentiful in a cluster, the operator would otherwise cache all of them.
+// Cluster scoped types are only cached when all namespaces are watched.
+func GetLabelFilteredTypes() []runtime.Object {
+	types := []runtime.Object{
+		&appsv1.Deployment{},
+		// The ReplicaSets of the Deployments carry the labels of their pod template
+		&appsv1.ReplicaSet{},
+		&corev1.ConfigMap{},
+		&corev1.Secret{},
+		&corev1.Service{},
+		&corev1.ServiceAccount{},
+		&corev1.Pod{},
+	}
+	if IsClusterWatched() {
+		types = append(types, &rbacv1.ClusterRoleBinding{})
+	}
+	return types
+}
+
+// NewLabelFilteredCacheFunc returns a function creating a cache that holds only
+// the objects of the given types matching selector, in the given namespaces or in
+// all namespaces when none are given. The objects of the other types are cached
+// by the cache newCache creates. Unstructured objects of the given types are read
+// from the API server, as they would otherwise be cached without the selector.
+func NewLabelFilteredCacheFunc(newCache manager.NewCacheFunc, namespaces []string, selector labels.Selector, types []runtime.Object) manager.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		delegate, err := newCache(config, opts)
+		if err != nil {
+			return nil, err
+		}
+		reader, err := client.New(config, client.Options{Scheme: opts.Scheme, Mapper: opts.Mapper})
+		if err != nil {
+			return nil, err
+		}
+		resync := defaultCacheResync
+		if opts.Resync != nil {
+			resync = *opts.Resync
+		}
+		c := &labelFilteredCache{
+			Cache:     delegate,
+			reader:    reader,
+			scheme:    opts.Scheme,
+			informers: make(map[schema.GroupVersionKind]*filteredInformers),
+		}
+		for _, obj := range types {
+			gvk, err := apiutil.GVKForObject(obj, opts.Scheme)
+			if err != nil {
+				return nil, err
+			}
+			informers, err := newFilteredInformers(config, opts, gvk, obj, namespaces, selector, resync)
+			if err != nil {
+				return nil, err
+			}
+			c.informers[gvk] = informers
+		}
+		return c, nil
+	}
+}
+
+// labelFilteredCache is a cache holding only the objects with given labels for a
+// set of types, and delegating the other types to another cache
+type labelFilteredCache struct {
+	cache.Cache
+	// reader reads the unstructured objects of the filtered types from the API
+	// server
+	reader    client.Reader
+	scheme    *runtime.Scheme
+	informers map[schema.GroupVersionKind]*filteredInformers
+}
+
+var _ cache.Cache = &labelFilteredCache{}
+
+// GetInformer returns the informer of the filtered objects of a filtered type, or
+// the informer of the delegate cache
+func (c *labelFilteredCache) GetInformer(obj runtime.Object) (toolscache.SharedIndexInformer, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetInformerForKind(gvk)
+}
+
+// GetInformerForKind returns the informer of the filtered objects of a filtered
+// kind, or the informer of the delegate cache
+func (c *labelFilteredCache) GetInformerForKind(gvk schema.GroupVersionKind) (toolscache.SharedIndexInformer, error) {
+	if informers, ok := c.informers[gvk]; ok {
+		return informers.informer(), nil
+	}
+	return c.Cache.GetInformerForKind(gvk)
+}
+
+// Start starts the filtered informers and the delegate cache, and blocks until
+// stop is closed
+func (c *labelFilteredCache) Start(stop <-chan struct{}) error {
+	for _, informers := range c.informers {
+		for _, informer := range informers.informers {
+			go informer.Run(stop)
+		}
+	}
+	return c.Cache.Start(stop)
+}
+
+// WaitForCacheSync waits until the filtered informers and the delegate cache are
+// synced
+func (c *labelFilteredCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	var synced []toolscache.InformerSynced
+	for _, informers := range c.informers {
+		for _, informer := range informers.informers {
+			synced = append(synced, informer.HasSynced)
+		}
+	}
+	if !toolscache.WaitForCacheSync(stop, synced...) {
+		return false
+	}
+	return c.Cache.WaitForCacheSync(stop)
+}
+
+// IndexField adds an index to the delegate cache. The filtered types cannot be
+// indexed.
+func (c *labelFilteredCache) IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.informers[gvk]; ok {
+		return fmt.Errorf("kind %s is filtered by labels and cannot be indexed", gvk.Kind)
+	}
+	return c.Cache.IndexField(obj, field, extractValue)
+}
+
+// Get reads an object of a filtered type from the filtered informers, or from the
+// API server when it is unstructured, and the other objects from the delegate
+// cache
+func (c *labelFilteredCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	informers, ok := c.informers[gvk]
+	if !ok {
+		return c.Cache.Get(ctx, key, obj)
+	}
+	if _, isUnstructured := obj.(*unstructured.Unstructured); isUnstructured {
+		return c.reader.Get(ctx, key, obj)
+	}
+	return informers.get(key, obj)
+}
+
+// List lists the objects of a filtered type from the filtered informers, or from
+// the API server when they are unstructured, and the other objects from the
+// delegate cache
+func (c *labelFilteredCache) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(list, c.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	informers, ok := c.informers[gvk]
+	if !ok {
+		return c.Cache.List(ctx, opts, list)
+	}
+	if _, isUnstructured := list.(*unstructured.UnstructuredList); isUnstructured {
+		return c.reader.List(ctx, opts, list)
+	}
+	return informers.list(opts, list)
+}
+
+// filteredInformers are the informers of the filtered objects of a type, one per
+// namespace or a single one for all namespaces
+type filteredInformers struct {
+	gvk      schema.GroupVersionKind
+	resource schema.GroupResource
+	// namespaces are the namespaces in the order they were given, "" for all
+	// namespaces
+	namespaces []string
+	informers  map[string]toolscache.SharedIndexInformer
+}
+
+// newFilteredInformers creates the informers of the objects of a type matching
+// selector, in each of the namespaces or in all namespaces when none are given.
+// Cluster scoped types have a single informer.
+func newFilteredInformers(config *rest.Config, opts cache.Options, gvk schema.GroupVersionKind, obj runtime.Object,
+	namespaces []string, selector labels.Selector, resync time.Duration) (*filteredInformers, error) {
+	mapping, err := opts.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	restClient, err := apiutil.RESTClientForGVK(gvk, config, serializer.NewCodecFactory(opts.Scheme))
+	if err != nil {
+		return nil, err
+	}
+	namespaced := mapping.Scope.Name() != meta.RESTScopeNameRoot
+	if !namespaced || len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+
+	informers := &filteredInformers{
+		gvk:        gvk,
+		resource:   mapping.Resource.GroupResource(),
+		namespaces: namespaces,
+		informers:  make(map[string]toolscache.SharedIndexInformer),
+	}
+	for _, namespace := range namespaces {
+		namespace := namespace
+		lw := &toolscache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = selector.String()
+				list, err := opts.Scheme.New(listGVK)
+				if err != nil {
+					return nil, err
+				}
+				err = restClient.Get().NamespaceIfScoped(namespace, namespaced).Resource(mapping.Resource.Resource).
+					VersionedParams(&options, metav1.ParameterCodec).Do().Into(list)
+				return list, err
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = selector.String()
+				options.Watch = true
+				return restClient.Get().NamespaceIfScoped(namespace, namespaced).Resource(mapping.Resource.Resource).
+					VersionedParams(&options, metav1.ParameterCodec).Watch()
+			},
+		}
+		informers.informers[namespace] = toolscache.NewSharedIndexInformer(lw, obj.DeepCopyObject(), resync,
+			toolscache.Indexers{toolscache.NamespaceIndex: toolscache.MetaNamespaceIndexFunc})
+	}
+	return informers, nil
+}
+
+// informer returns the informer delivering the events of all the namespaces
+func (f *filteredInformers) informer() toolscache.SharedIndexInformer {
+	if len(f.informers) == 1 {
+		return f.informers[f.namespaces[0]]
+	}
+	return &multiNamespaceInformer{SharedIndexInformer: f.informers[f.namespaces[0]], informers: f.informers}
+}
+
+// namespaceInformer returns the informer holding the objects of a namespace
+func (f *filteredInformers) namespaceInformer(namespace string) (toolscache.SharedIndexInformer, error) {
+	if informer, ok := f.informers[""]; ok {
+		return informer, nil
+	}
+	informer, ok := f.informers[namespace]
+	if !ok {
+		return nil, fmt.Errorf("namespace %s is not watched, the watched namespaces are %s", namespace, strings.Join(f.namespaces, ","))
+	}
+	return informer, nil
+}
+
+// get copies the object with the given key into obj
+func (f *filteredInformers) get(key client.ObjectKey, obj runtime.Object) error {
+	informer, err := f.namespaceInformer(key.Namespace)
+	if err != nil {
+		return err
+	}
+	storeKey := key.Name
+	if len(key.Namespace) > 0 {
+		storeKey = key.Namespace + "/" + key.Name
+	}
+	item, exists, err := informer.GetIndexer().GetByKey(storeKey)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apierrors.NewNotFound(f.resource, key.Name)
+	}
+	cached, ok := item.(runtime.Object)
+	if !ok {
+		return fmt.Errorf("cache contained %T, which is not an Object", item)
+	}
+	outVal := reflect.ValueOf(obj)
+	objVal := reflect.ValueOf(cached.DeepCopyObject())
+	if !objVal.Type().AssignableTo(outVal.Type()) {
+		return fmt.Errorf("cache had type %s, but %s was asked for", objVal.Type(), outVal.Type())
+	}
+	reflect.Indirect(outVal).Set(reflect.Indirect(objVal))
+	obj.GetObjectKind().SetGroupVersionKind(f.gvk)
+	return nil
+}
+
+// list copies the objects matching opts into list
+func (f *filteredInformers) list(opts *client.ListOptions, list runtime.Object) error {
+	var items []interface{}
+	if opts != nil && len(opts.Namespace) > 0 {
+		informer, err := f.namespaceInformer(opts.Namespace)
+		if err != nil {
+			return err
+		}
+		if items, err = informer.GetIndexer().ByIndex(toolscache.NamespaceIndex, opts.Namespace); err != nil {
+			return err
+		}
+	} else {
+		for _, namespace := range f.namespaces {
+			items = append(items, f.informers[namespace].GetIndexer().List()...)
+		}
+	}
+
+	selector := labels.Everything()
+	if opts != nil && opts.LabelSelector != nil {
+		selector = opts.LabelSelector
+	}
+	objects := make([]runtime.Object, 0, len(items))
+	for _, item := range items {
+		cached, ok := item.(runtime.Object)
+		if !ok {
+			return fmt.Errorf("cache contained %T, which is not an Object", item)
+		}
+		metaObj, err := meta.Accessor(cached)
+		if err != nil {
+			return err
+		}
+		if !selector.Matches(labels.Set(metaObj.GetLabels())) {
+			continue
+		}
+		objects = append(objects, cached.DeepCopyObject())
+	}
+	return meta.SetList(list, objects)
+}
@@ -21,45 +21,54 @@ A new function package to manage logging in App Navigator Golang components
 package utils
 
 import (
-        "fmt"
-        "time" 
-        "encoding/json"
+	"context"
+	"io"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
 /*LogLevel values of LogLevel. LogLevel is what user requests*/
-type LogLevel int 
+type LogLevel int
+
 const (
-        // LogLevelNone request no trace
-        LogLevelNone  LogLevel = 0  
-        // LogLevelWarning request warning trace
-        LogLevelWarning LogLevel = 1
-        // LogLevelError request error trace
-        LogLevelError LogLevel = 2
-        // LogLevelInfo request info trace
-        LogLevelInfo LogLevel = 3
-        // LogLevelDebug request debug trace
-        LogLevelDebug LogLevel = 4
-        // LogLevelEntry request entry trace
-        LogLevelEntry LogLevel = 5
-        // LogLevelAll request all traces
+	// LogLevelNone request no trace
+	LogLevelNone LogLevel = 0
+	// LogLevelWarning request warning trace
+	LogLevelWarning LogLevel = 1
+	// LogLevelError request error trace
+	LogLevelError LogLevel = 2
+	// LogLevelInfo request info trace
+	LogLevelInfo LogLevel = 3
+	// LogLevelDebug request debug trace
+	LogLevelDebug LogLevel = 4
+	// LogLevelEntry request entry trace
+	LogLevelEntry LogLevel = 5
+	// LogLevelAll request all traces
 	LogLevelAll LogLevel = 6
 )
 
 /*LogType values of LogType. LogType is how code categorizes log message*/
-type LogType int 
+type LogType int
+
 const (
-        // LogTypeEntry entry trace type
-        LogTypeEntry LogType = 0  
-        // LogTypeExit exit trace type
-        LogTypeExit LogType = 1
-        // LogTypeInfo info trace type
-        LogTypeInfo LogType = 2
-        // LogTypeWarning warning trace type
-        LogTypeWarning LogType = 3
-        // LogTypeError error trace type
-        LogTypeError LogType = 4
-        // LogTypeDebug debug trace type
-        LogTypeDebug LogType = 5
+	// LogTypeEntry entry trace type
+	LogTypeEntry LogType = 0
+	// LogTypeExit exit trace type
+	LogTypeExit LogType = 1
+	// LogTypeInfo info trace type
+	LogTypeInfo LogType = 2
+	// LogTypeWarning warning trace type
+	LogTypeWarning LogType = 3
+	// LogTypeError error trace type
+	LogTypeError LogType = 4
+	// LogTypeDebug debug trace type
+	LogTypeDebug LogType = 5
 )
 
 /*logType array*/
@@ -72,156 +81,229 @@ var logTypes = [6]string{
 	"debug",
 }
 
-/*logLevel array*/ 
+/*logLevel array*/
 var logLevels = [7]string{
-        "LogLevelNone",
-        "LogLevelWarning", 
-        "LogLevelError", 
-	"LogLevelInfo", 
-	"LogLevelDebug", 
-	"LogLevelEntry", 
-        "LogLevelAll",       
-} 
-
-/*Logger interfaces*/ 
+	"LogLevelNone",
+	"LogLevelWarning",
+	"LogLevelError",
+	"LogLevelInfo",
+	"LogLevelDebug",
+	"LogLevelEntry",
+	"LogLevelAll",
+}
+
+// enabledLogTypes maps each LogLevel to the log types it enables
+//
+//	Log Level | Enabled Log Types
+//	----------+----------------------------------------
+//	none      |  none
+//	error     |  error
+//	warning   |  error, warning
+//	info      |  error, warning, info
+//	debug     |  error, warning, info, debug
+//	entry     |  error, warning, info, entry, exit, debug
+//	all       |  error, warning, info, entry, exit, debug
+var enabledLogTypes = [7][6]bool{
+	LogLevelNone:    {},
+	LogLevelWarning: {LogTypeWarning: true, LogTypeError: true},
+	LogLevelError:   {LogTypeError: true},
+	LogLevelInfo:    {LogTypeInfo: true, LogTypeWarning: true, LogTypeError: true},
+	LogLevelDebug:   {LogTypeInfo: true, LogTypeWarning: true, LogTypeError: true, LogTypeDebug: true},
+	LogLevelEntry:   {true, true, true, true, true, true},
+	LogLevelAll:     {true, true, true, true, true, true},
+}
+
+// logLevel is the process-wide log level shared by all loggers
+var logLevel = int32(LogLevelInfo)
+
+// sinkLevel is the level of the zap sink returned by NewLogSink. It follows the
+// log level so that the debug, entry and exit messages reach the output.
+var sinkLevel = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+/*Logger interfaces. A Logger is a logr.Logger, so it can be passed to code that logs with logr, and its messages are written to the controller-runtime log sink.*/
 type Logger interface {
-        SetLogLevel(logLevel LogLevel)       
-        Log(callerName string, logType LogType, logData string, loggerName string)
-        IsEnabled(logType LogType) bool       
+	logr.Logger
+	SetLogLevel(logLevel LogLevel)
+	Log(callerName string, logType LogType, logData string, loggerName string)
+	IsEnabled(logType LogType) bool
+	// WithFields returns a Logger that adds the key/value pairs to every message,
+	// e.g. the namespace and name of the resource being reconciled
+	WithFields(keysAndValues ...interface{}) Logger
+	// Context returns the context of the operation being logged, which carries the
+	// current trace span, see StartSpan
+	Context() context.Context
+	// WithContext returns a Logger for the operation of the context. Its messages
+	// carry the trace and span IDs of the span of the context.
+	WithContext(ctx context.Context) Logger
 }
 
-/*NewLogger create new Logger*/ 
-func NewLogger(enableJSON bool) Logger {             
-        l := &loggerImpl{}      
-        l.enableJSONLog = enableJSON  //true: log in JSON format, false: log in plain text
-	// Set default log level
-	l.SetLogLevel(LogLevelInfo)  
-	return l
+/*NewLogger create new Logger. All loggers share the process-wide log level.*/
+func NewLogger() Logger {
+	return &loggerImpl{}
 }
 
-type loggerImpl struct {
-	LogLevel LogLevel
-        LogTypeEnabled [6]bool
-        enableJSONLog bool
+/*NewLogSink create the zap logr.Logger the operator writes its log to, in JSON. Set it with logf.SetLogger. Its level follows the process-wide log level.*/
+func NewLogSink(destWriter io.Writer) logr.Logger {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(destWriter), sinkLevel)
+	return zapr.NewLogger(zap.New(core, zap.AddStacktrace(zapcore.ErrorLevel)))
 }
 
-//Message JSON structure 
-type Message struct {
-        Level string `json:"level"`
-        Timestamp float64 `json:"ts"`
-        Logger string `json:"logger"`
-        Caller string `json:"caller"`
-        Msg string `json:"msg"`
+/*GetLogLevel return the process-wide log level*/
+func GetLogLevel() LogLevel {
+	return LogLevel(atomic.LoadInt32(&logLevel))
 }
 
-/*Log write log entry to stdout. 
-   Use getLogMessage func to format message 
-*/ 
-func (logger *loggerImpl) Log(callerName string, logType LogType, logData string, logName string) {
-        if logger.enableJSONLog {
-                logger.logInJSON(callerName, logType, logData, logName)
-        } else {
-                logger.logInPlainText(callerName, logType, logData, logName)
-        }     
+type loggerImpl struct {
+	name   string
+	fields []interface{}
+	ctx    context.Context
 }
 
-//logInPlainText log message in plain text
-func (logger *loggerImpl) logInPlainText(callerName string, logType LogType, logData string, logName string) {
-	str := "[" + time.Now().Format(time.RFC3339) + " " + logTypes[logType] + " " + logName + " " + callerName + "] " + logger.getLogMessage(logType, logData) 
-	fmt.Println(str)
+/*sink return the controller-runtime logger the messages are written to*/
+func (logger *loggerImpl) sink(loggerName string) logr.Logger {
+	var sink logr.Logger = logf.Log
+	if len(logger.name) > 0 {
+		sink = sink.WithName(logger.name)
+	}
+	if len(loggerName) > 0 {
+		sink = sink.WithName(loggerName)
+	}
+	if len(logger.fields) > 0 {
+		sink = sink.WithValues(logger.fields...)
+	}
+	if spanContext := trace.SpanContextFromContext(logger.Context()); spanContext.IsValid() {
+		sink = sink.WithValues("traceID", spanContext.TraceID().String(), "spanID", spanContext.SpanID().String())
+	}
+	return sink
 }
 
-//logInJSON log message in JSON format
-func (logger *loggerImpl) logInJSON(callerName string, logType LogType, logData string, logName string) {               
-        log := Message{
-                Level: logTypes[logType],
-                Timestamp: FormatTimestamp(time.Now()),  //timestamp in unix seconds float format
-                Logger: logName,
-                Caller: callerName,
-                Msg: logger.getLogMessage(logType, logData),                                      
-        }          
-        data, _ := json.Marshal(log)
-        // Convert bytes to string
-        str := string(data)
-        fmt.Println(str)
+/*Log write log entry to the controller-runtime log sink with the caller and the log type as fields. Errors are logged with a stack trace, debug messages at V(1) and entry and exit messages at V(2).*/
+func (logger *loggerImpl) Log(callerName string, logType LogType, logData string, loggerName string) {
+	logger.log(loggerName, logType, logData, "caller", callerName)
 }
 
+func (logger *loggerImpl) log(loggerName string, logType LogType, msg string, keysAndValues ...interface{}) {
+	sink := logger.sink(loggerName)
+	keysAndValues = append(keysAndValues, "type", logTypes[logType])
+	switch logType {
+	case LogTypeError:
+		sink.Error(nil, msg, keysAndValues...)
+	case LogTypeDebug:
+		sink.V(1).Info(msg, keysAndValues...)
+	case LogTypeEntry, LogTypeExit:
+		sink.V(2).Info(msg, keysAndValues...)
+	default:
+		sink.Info(msg, keysAndValues...)
+	}
+}
 
 /*isEnabled guard function to test if desired logType is enabled */
-func (logger *loggerImpl) IsEnabled(logType LogType) bool {       
-       return logger.LogTypeEnabled[logType]
+func (logger *loggerImpl) IsEnabled(logType LogType) bool {
+	return enabledLogTypes[GetLogLevel()][logType]
 }
 
-/*getLogMessage return log message as string in format:
-  logData
-*/
-func (logger *loggerImpl) getLogMessage(logType LogType, logData string) string {
-        var msg string
-        //print stack when logType is ERROR
-	if logType == LogTypeError {
-		msg = ErrorWithStack(logData)
-	} else {
-		msg = logData
+/*SetLogLevel set the process-wide log level to specified value, see enabledLogTypes for the log types each level enables.*/
+func (logger *loggerImpl) SetLogLevel(level LogLevel) {
+	if level < LogLevelNone || level > LogLevelAll {
+		return
 	}
-	return msg
-}
-
-/*setLogTypes set log types */
-func (logger *loggerImpl) setLogTypes(value bool) {
-        for index, t := range logTypes {
-                if (logger.IsEnabled(LogTypeDebug)) {
-                        logger.Log(CallerName(), LogTypeDebug, fmt.Sprintf("Set log type %s to %t", t, value), "utils")
-                }
-                logger.LogTypeEnabled[index] = value
-        }    
-}
-
-/*SetLogLevel set global log level to specified value 
-   set IsEnabled based on specified LogLevel as follows: 
-   
-   Log Level	| Enabled Log Types
-   -------------+----------------------------------------
-   none	        |  set all to false 
-   error	|  error
-   warning	|  error, warning
-   info	        |  error, warning, info
-   debug	|  error, warning, info, debug
-   entry	|  error, warning, info, entry, exit, debug
-   all	        |  error, warning, info, entry, exit, debug
-*/
-func (logger *loggerImpl) SetLogLevel(logLevel LogLevel) {      
-        if (logger.IsEnabled(LogTypeInfo)) {
-                logger.Log(CallerName(), LogTypeInfo, "Logging level is set to " + logLevels[logLevel], "utils");
-        }
-        logger.setLogTypes(false)   
-
-        switch logLevel { 
-        case LogLevelNone:
-                break           
-        case LogLevelError:
-                logger.LogTypeEnabled[LogTypeError] = true 
-                break
-        case LogLevelWarning:
-                logger.LogTypeEnabled[LogTypeError] = true 
-                logger.LogTypeEnabled[LogTypeWarning] = true 
-                break
-        case LogLevelInfo:
-                logger.LogTypeEnabled[LogTypeError] = true 
-                logger.LogTypeEnabled[LogTypeWarning] = true 
-                logger.LogTypeEnabled[LogTypeInfo] = true 
-                break
-        case LogLevelDebug:
-                logger.LogTypeEnabled[LogTypeError] = true 
-                logger.LogTypeEnabled[LogTypeWarning] = true 
-                logger.LogTypeEnabled[LogTypeInfo] = true 
-                logger.LogTypeEnabled[LogTypeDebug] = true 
-                break
-        case LogLevelEntry:
-                logger.setLogTypes(true)
-                break
-        case LogLevelAll:
-                logger.setLogTypes(true)  
-                break             
-        }
-        
+	old := LogLevel(atomic.SwapInt32(&logLevel, int32(level)))
+	switch level {
+	case LogLevelDebug:
+		sinkLevel.SetLevel(zapcore.DebugLevel)
+	case LogLevelEntry, LogLevelAll:
+		sinkLevel.SetLevel(zapcore.DebugLevel - 1)
+	default:
+		sinkLevel.SetLevel(zapcore.InfoLevel)
+	}
+	if old != level && logger.IsEnabled(LogTypeInfo) {
+		logger.Log(CallerName(), LogTypeInfo, "Logging level is set to "+logLevels[level], "utils")
+	}
+}
+
+/*WithFields return a Logger that adds the key/value pairs to every message*/
+func (logger *loggerImpl) WithFields(keysAndValues ...interface{}) Logger {
+	fields := make([]interface{}, 0, len(logger.fields)+len(keysAndValues))
+	fields = append(fields, logger.fields...)
+	fields = append(fields, keysAndValues...)
+	return &loggerImpl{name: logger.name, fields: fields, ctx: logger.ctx}
+}
+
+/*Context return the context of the operation being logged, context.Background() if none was set*/
+func (logger *loggerImpl) Context() context.Context {
+	if logger.ctx == nil {
+		return context.Background()
+	}
+	return logger.ctx
+}
+
+/*WithContext return a Logger for the operation of the context*/
+func (logger *loggerImpl) WithContext(ctx context.Context) Logger {
+	return &loggerImpl{name: logger.name, fields: logger.fields, ctx: ctx}
+}
+
+// Info implements logr.Logger, logging an info message
+func (logger *loggerImpl) Info(msg string, keysAndValues ...interface{}) {
+	if logger.IsEnabled(LogTypeInfo) {
+		logger.log("", LogTypeInfo, msg, keysAndValues...)
+	}
+}
+
+// Enabled implements logr.Logger
+func (logger *loggerImpl) Enabled() bool {
+	return logger.IsEnabled(LogTypeInfo)
+}
+
+// Error implements logr.Logger, logging an error message
+func (logger *loggerImpl) Error(err error, msg string, keysAndValues ...interface{}) {
+	if logger.IsEnabled(LogTypeError) {
+		if err != nil {
+			keysAndValues = append(keysAndValues, "error", err.Error())
+		}
+		logger.log("", LogTypeError, msg, keysAndValues...)
+	}
+}
+
+// V implements logr.Logger. V(0) logs info messages, V(1) debug messages and
+// higher levels entry and exit messages.
+func (logger *loggerImpl) V(level int) logr.InfoLogger {
+	switch {
+	case level <= 0:
+		return &infoLogger{logger: logger, logType: LogTypeInfo}
+	case level == 1:
+		return &infoLogger{logger: logger, logType: LogTypeDebug}
+	default:
+		return &infoLogger{logger: logger, logType: LogTypeEntry}
+	}
+}
+
+// WithValues implements logr.Logger
+func (logger *loggerImpl) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return logger.WithFields(keysAndValues...)
+}
+
+// WithName implements logr.Logger
+func (logger *loggerImpl) WithName(name string) logr.Logger {
+	if len(logger.name) > 0 {
+		name = logger.name + "." + name
+	}
+	return &loggerImpl{name: name, fields: logger.fields, ctx: logger.ctx}
+}
+
+/*infoLogger logs the messages of a logr verbosity level as one log type*/
+type infoLogger struct {
+	logger  *loggerImpl
+	logType LogType
+}
+
+func (l *infoLogger) Info(msg string, keysAndValues ...interface{}) {
+	if l.Enabled() {
+		l.logger.log("", l.logType, msg, keysAndValues...)
+	}
+}
+
+func (l *infoLogger) Enabled() bool {
+	return l.logger.IsEnabled(l.logType)
 }
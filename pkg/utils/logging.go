@@ -0,0 +1,247 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LoggingComponentOperator ...
+	LoggingComponentOperator string = "operator"
+	// LoggingComponentAPIs ...
+	LoggingComponentAPIs string = "apis"
+	// LoggingComponentUI ...
+	LoggingComponentUI string = "ui"
+	// LoggingComponentController ...
+	LoggingComponentController string = "controller"
+)
+
+const (
+	// LoggingVolumeName ...
+	LoggingVolumeName string = "logging"
+	// LoggingVolumeMountPath is where the logging ConfigMap is mounted in the containers
+	LoggingVolumeMountPath string = "/etc/kappnav/logging"
+	// LoggingPackagesKeySuffix is appended to a component to form the key of the
+	// log levels of its packages in the logging ConfigMap
+	LoggingPackagesKeySuffix string = ".packages"
+	// LoggingDirEnvVarName is the directory the logging ConfigMap is mounted in
+	LoggingDirEnvVarName string = "KAPPNAV_LOGGING_DIR"
+	// LoggingComponentEnvVarName is the component whose keys the container reads
+	LoggingComponentEnvVarName string = "KAPPNAV_LOGGING_COMPONENT"
+	// LogLevelEnvVarName is the log level of the container when the levels are
+	// propagated through the environment
+	LogLevelEnvVarName string = "KAPPNAV_LOG_LEVEL"
+	// LogPackagesEnvVarName is the log levels of the packages of the container when
+	// the levels are propagated through the environment
+	LogPackagesEnvVarName string = "KAPPNAV_LOG_PACKAGES"
+)
+
+// LoggingComponents are the components whose log level is set in the CR
+var LoggingComponents = []string{
+	LoggingComponentOperator,
+	LoggingComponentAPIs,
+	LoggingComponentUI,
+	LoggingComponentController,
+}
+
+// containerLoggingComponents maps a container to the component it logs as
+var containerLoggingComponents = map[string]string{
+	APIContainerName:        LoggingComponentAPIs,
+	UIContainerName:         LoggingComponentUI,
+	ControllerContainerName: LoggingComponentController,
+}
+
+// GetLoggingConfigMapName returns the name of the ConfigMap holding the log levels
+func GetLoggingConfigMapName(instance *kappnavv1.Kappnav) string {
+	return instance.GetName() + "-" + LoggingVolumeName
+}
+
+// GetLoggingPropagation returns how the log levels reach the containers
+func GetLoggingPropagation(instance *kappnavv1.Kappnav) kappnavv1.LoggingPropagation {
+	options := instance.Spec.LoggingOptions
+	if options == nil || len(options.Propagation) == 0 {
+		return kappnavv1.LoggingPropagationConfigMap
+	}
+	return options.Propagation
+}
+
+// UpdateDebugLoggingStatus records the debug logging requested in the spec in the
+// status. A new period starts when the request changes. Returns how long the
+// current period has left, or zero if no period is active.
+func UpdateDebugLoggingStatus(instance *kappnavv1.Kappnav, now time.Time) time.Duration {
+	var debug *kappnavv1.KappnavDebugLogging
+	if instance.Spec.LoggingOptions != nil {
+		debug = instance.Spec.LoggingOptions.Debug
+	}
+	if debug == nil || debug.Duration.Duration <= 0 {
+		instance.Status.DebugLogging = nil
+		return 0
+	}
+	level := debug.Level
+	if len(level) == 0 {
+		level = "debug"
+	}
+	// The times are stored to the second, so the period is too
+	duration := debug.Duration.Duration.Truncate(time.Second)
+	status := instance.Status.DebugLogging
+	if status == nil || status.Level != level || !equalStrings(status.Components, debug.Components) ||
+		status.ExpiryTime.Sub(status.StartTime.Time) != duration {
+		start := now.Truncate(time.Second)
+		status = &kappnavv1.DebugLoggingStatus{
+			Components: debug.Components,
+			Level:      level,
+			StartTime:  metav1.NewTime(start),
+			ExpiryTime: metav1.NewTime(start.Add(duration)),
+		}
+		instance.Status.DebugLogging = status
+	}
+	remaining := status.ExpiryTime.Sub(now)
+	status.Active = remaining > 0
+	if !status.Active {
+		return 0
+	}
+	return remaining
+}
+
+// GetEffectiveLogLevels returns the log level of each component, which is the
+// level of the logging field unless an active debug period raises it
+func GetEffectiveLogLevels(instance *kappnavv1.Kappnav) map[string]kappnavv1.LogLevel {
+	levels := make(map[string]kappnavv1.LogLevel)
+	for _, component := range LoggingComponents {
+		if level := instance.Spec.Logging[component]; len(level) > 0 {
+			levels[component] = level
+		}
+	}
+	status := instance.Status.DebugLogging
+	if status != nil && status.Active {
+		components := status.Components
+		if len(components) == 0 {
+			components = LoggingComponents
+		}
+		for _, component := range components {
+			levels[component] = status.Level
+		}
+	}
+	return levels
+}
+
+// getPackageLogLevels returns the log levels of the packages of a component as a
+// comma separated list of package=level
+func getPackageLogLevels(instance *kappnavv1.Kappnav, component string) string {
+	if instance.Spec.LoggingOptions == nil {
+		return ""
+	}
+	packages := instance.Spec.LoggingOptions.Packages[component]
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	entries := make([]string, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, name+"="+string(packages[name]))
+	}
+	return strings.Join(entries, ",")
+}
+
+// CustomizeLoggingConfigMap writes the effective log level of every component and
+// the log levels of their packages to the logging ConfigMap
+func CustomizeLoggingConfigMap(configMap *corev1.ConfigMap, instance *kappnavv1.Kappnav) {
+	configMap.Labels = GetLabels(instance, configMap.Labels, &configMap.ObjectMeta, "")
+	levels := GetEffectiveLogLevels(instance)
+	data := make(map[string]string)
+	for _, component := range LoggingComponents {
+		if level, ok := levels[component]; ok {
+			data[component] = string(level)
+		}
+		if packages := getPackageLogLevels(instance, component); len(packages) > 0 {
+			data[component+LoggingPackagesKeySuffix] = packages
+		}
+	}
+	configMap.Data = data
+}
+
+func createLoggingVolume(instance *kappnavv1.Kappnav) *corev1.Volume {
+	if GetLoggingPropagation(instance) != kappnavv1.LoggingPropagationConfigMap {
+		return nil
+	}
+	return &corev1.Volume{
+		Name: instance.Name + "-" + LoggingVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: GetLoggingConfigMapName(instance),
+				},
+			},
+		},
+	}
+}
+
+// addLogging passes the log levels to the container. A mounted ConfigMap is
+// updated in place, so the container can pick up changes without a restart.
+// Log levels in the environment roll the Deployment when they change.
+func addLogging(container *corev1.Container, instance *kappnavv1.Kappnav) {
+	component, ok := containerLoggingComponents[container.Name]
+	if !ok {
+		return
+	}
+	if GetLoggingPropagation(instance) == kappnavv1.LoggingPropagationConfigMap {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      instance.Name + "-" + LoggingVolumeName,
+			MountPath: LoggingVolumeMountPath,
+			ReadOnly:  true,
+		})
+		container.Env = append(container.Env,
+			corev1.EnvVar{
+				Name:  LoggingDirEnvVarName,
+				Value: LoggingVolumeMountPath,
+			},
+			corev1.EnvVar{
+				Name:  LoggingComponentEnvVarName,
+				Value: component,
+			})
+		return
+	}
+	if level, ok := GetEffectiveLogLevels(instance)[component]; ok {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  LogLevelEnvVarName,
+			Value: string(level),
+		})
+	}
+	if packages := getPackageLogLevels(instance, component); len(packages) > 0 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  LogPackagesEnvVarName,
+			Value: packages,
+		})
+	}
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"sync"
+	"time"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The operator metrics are registered with the controller-runtime registry, so
+// they are served on the metrics endpoint of the manager together with the
+// controller-runtime metrics.
+var (
+	reconcileStepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kappnav_reconcile_step_duration_seconds",
+		Help:    "Duration of the steps of a Kappnav reconcile by step and result",
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"step", "result"})
+
+	reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kappnav_reconcile_total",
+		Help: "Number of Kappnav reconciles by result",
+	}, []string{"result"})
+
+	reconciled = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kappnav_reconciled",
+		Help: "Whether the last reconcile of a Kappnav CR succeeded (1) or failed (0)",
+	}, []string{"namespace", "instance"})
+
+	configMaps = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kappnav_config_maps",
+		Help: "Number of action, sections and status config maps by map type",
+	}, []string{"namespace", "map_type"})
+
+	driftTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kappnav_drift_corrections_total",
+		Help: "Number of existing resources the operator changed because they differed from the desired state",
+	}, []string{"kind"})
+
+	componentReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kappnav_component_ready",
+		Help: "Whether all replicas of a kAppNav container are ready and run the desired image (1) or not (0)",
+	}, []string{"namespace", "instance", "deployment", "component"})
+
+	certificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kappnav_certificate_expiry_timestamp_seconds",
+		Help: "Time the certificate in a secret of a Kappnav CR expires, in seconds since the epoch",
+	}, []string{"namespace", "instance", "secret"})
+)
+
+const (
+	// MetricsResultSuccess ...
+	MetricsResultSuccess string = "success"
+	// MetricsResultError ...
+	MetricsResultError string = "error"
+)
+
+var (
+	// instanceComponentsMutex guards instanceComponents
+	instanceComponentsMutex sync.Mutex
+	// instanceComponents are the label sets of the component gauges of each
+	// instance, so that they can be removed with the instance
+	instanceComponents = make(map[string][]prometheus.Labels)
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileStepDuration, reconcileTotal, reconciled,
+		configMaps, driftTotal, componentReady, certificateExpiry)
+}
+
+func metricsResult(err error) string {
+	if err != nil {
+		return MetricsResultError
+	}
+	return MetricsResultSuccess
+}
+
+// ObserveReconcileStep records the duration and result of a reconcile step that
+// started at start
+func ObserveReconcileStep(step string, start time.Time, err error) {
+	reconcileStepDuration.WithLabelValues(step, metricsResult(err)).Observe(time.Since(start).Seconds())
+}
+
+// RecordReconcileResult records the outcome of a reconcile of the CR
+func RecordReconcileResult(instance *kappnavv1.Kappnav, err error) {
+	reconcileTotal.WithLabelValues(metricsResult(err)).Inc()
+	value := 1.0
+	if err != nil {
+		value = 0
+	}
+	reconciled.WithLabelValues(instance.GetNamespace(), instance.GetName()).Set(value)
+}
+
+// SetConfigMapCount records the number of config maps of a map type in a namespace
+func SetConfigMapCount(namespace string, mapType string, count int) {
+	configMaps.WithLabelValues(namespace, mapType).Set(float64(count))
+}
+
+// recordDrift counts an update of an existing resource of the kind
+func recordDrift(kind string) {
+	driftTotal.WithLabelValues(kind).Inc()
+}
+
+// setComponentMetrics records the readiness of the components of the CR,
+// removing the gauges of components that no longer exist
+func setComponentMetrics(instance *kappnavv1.Kappnav, components []kappnavv1.ComponentStatus) {
+	key := instance.GetNamespace() + "/" + instance.GetName()
+	var current []prometheus.Labels
+	for _, component := range components {
+		labels := prometheus.Labels{
+			"namespace":  instance.GetNamespace(),
+			"instance":   instance.GetName(),
+			"deployment": component.Deployment,
+			"component":  component.Name,
+		}
+		value := 0.0
+		if isComponentReady(&component) {
+			value = 1
+		}
+		componentReady.With(labels).Set(value)
+		current = append(current, labels)
+	}
+
+	instanceComponentsMutex.Lock()
+	defer instanceComponentsMutex.Unlock()
+	for _, old := range instanceComponents[key] {
+		if !containLabels(current, old) {
+			componentReady.Delete(old)
+		}
+	}
+	instanceComponents[key] = current
+}
+
+// setCertificateExpiry records the expiry of the certificate in a secret of the CR
+func setCertificateExpiry(instance *kappnavv1.Kappnav, secret string, notAfter time.Time) {
+	certificateExpiry.WithLabelValues(instance.GetNamespace(), instance.GetName(), secret).Set(float64(notAfter.Unix()))
+}
+
+// DeleteInstanceMetrics removes the metrics of a CR that no longer exists
+func DeleteInstanceMetrics(namespace string, name string) {
+	reconciled.DeleteLabelValues(namespace, name)
+	certificateExpiry.DeleteLabelValues(namespace, name, name+"-"+OAuthVolumeName)
+	instanceComponentsMutex.Lock()
+	defer instanceComponentsMutex.Unlock()
+	key := namespace + "/" + name
+	for _, labels := range instanceComponents[key] {
+		componentReady.Delete(labels)
+	}
+	delete(instanceComponents, key)
+}
+
+func isComponentReady(component *kappnavv1.ComponentStatus) bool {
+	return component.RolloutState == kappnavv1.RolloutStateComplete &&
+		component.ReadyReplicas >= component.Replicas &&
+		component.UpToDatePods >= component.Replicas
+}
+
+func containLabels(array []prometheus.Labels, labels prometheus.Labels) bool {
+	for _, a := range array {
+		if a["deployment"] == labels["deployment"] && a["component"] == labels["component"] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// MonitoringGroupVersion is the API of the Prometheus operator
+	MonitoringGroupVersion string = "monitoring.coreos.com/v1"
+	// MetricsServiceNameSuffix is appended to the name of a component to form the
+	// name of its metrics Service
+	MetricsServiceNameSuffix string = "metrics"
+	// MetricsPortName is the name of the metrics port of the metrics Services
+	MetricsPortName string = "metrics"
+	// ControllerMetricsPort is the port the controller serves its metrics on
+	ControllerMetricsPort int32 = 9090
+	// DefaultCertificateExpiryDays ...
+	DefaultCertificateExpiryDays int32 = 30
+)
+
+// MetricsComponent is a kAppNav component scraped by a ServiceMonitor
+type MetricsComponent struct {
+	// Name is the name of the component, appended to the name of the CR to form the
+	// names of its Service and ServiceMonitor
+	Name   string
+	Port   int32
+	Scheme string
+	Path   string
+	// Deployment is the Deployment whose pods serve the metrics, suffixed to the
+	// name of the CR. The pods of all Deployments of the CR when empty.
+	Deployment string
+}
+
+// MetricsComponents are the components scraped when monitoring is enabled. The
+// API container runs in both the UI and the controller pods.
+var MetricsComponents = []MetricsComponent{
+	{Name: "api", Port: 9443, Scheme: "https", Path: "/metrics"},
+	{Name: "ui", Port: 3000, Scheme: "http", Path: "/metrics", Deployment: "ui"},
+	{Name: "controller", Port: ControllerMetricsPort, Scheme: "http", Path: "/metrics", Deployment: "controller"},
+}
+
+// IsMonitoringEnabled returns true if ServiceMonitors are requested in the CR
+func IsMonitoringEnabled(instance *kappnavv1.Kappnav) bool {
+	return instance.Spec.Monitoring != nil && instance.Spec.Monitoring.Enabled
+}
+
+// IsAlertingEnabled returns true if the PrometheusRule is requested in the CR
+func IsAlertingEnabled(instance *kappnavv1.Kappnav) bool {
+	if !IsMonitoringEnabled(instance) {
+		return false
+	}
+	alerts := instance.Spec.Monitoring.Alerts
+	return alerts == nil || *alerts
+}
+
+// GetMetricsServiceName returns the name of the metrics Service of a component
+func GetMetricsServiceName(instance *kappnavv1.Kappnav, component *MetricsComponent) string {
+	return instance.GetName() + "-" + component.Name + "-" + MetricsServiceNameSuffix
+}
+
+// GetServiceMonitorName returns the name of the ServiceMonitor of a component
+func GetServiceMonitorName(instance *kappnavv1.Kappnav, component *MetricsComponent) string {
+	return instance.GetName() + "-" + component.Name
+}
+
+// GetPrometheusRuleName returns the name of the PrometheusRule of the CR
+func GetPrometheusRuleName(instance *kappnavv1.Kappnav) string {
+	return instance.GetName() + "-alerts"
+}
+
+// getMonitoringLabels returns the labels of the monitoring resources
+func getMonitoringLabels(instance *kappnavv1.Kappnav, existingLabels map[string]string, component *metav1.ObjectMeta) map[string]string {
+	labels := GetLabels(instance, existingLabels, component, "")
+	for key, value := range instance.Spec.Monitoring.Labels {
+		labels[key] = value
+	}
+	return labels
+}
+
+// CustomizeMetricsService ...
+func CustomizeMetricsService(service *corev1.Service, instance *kappnavv1.Kappnav, component *MetricsComponent) {
+	service.Labels = GetLabels(instance, service.Labels, &service.ObjectMeta, "")
+	selector := map[string]string{
+		"app.kubernetes.io/instance": instance.GetName(),
+	}
+	if len(component.Deployment) > 0 {
+		selector["app.kubernetes.io/component"] = instance.GetName() + "-" + component.Deployment
+	}
+	service.Spec.Selector = selector
+	service.Spec.Type = corev1.ServiceTypeClusterIP
+	service.Spec.Ports = []corev1.ServicePort{
+		{
+			Name:       MetricsPortName,
+			Port:       component.Port,
+			TargetPort: intstr.FromInt(int(component.Port)),
+			Protocol:   corev1.ProtocolTCP,
+		},
+	}
+}
+
+// CustomizeServiceMonitor ...
+func CustomizeServiceMonitor(serviceMonitor *monitoringv1.ServiceMonitor, instance *kappnavv1.Kappnav, component *MetricsComponent) {
+	serviceMonitor.Labels = getMonitoringLabels(instance, serviceMonitor.Labels, &serviceMonitor.ObjectMeta)
+	endpoint := monitoringv1.Endpoint{
+		Port:     MetricsPortName,
+		Path:     component.Path,
+		Scheme:   component.Scheme,
+		Interval: instance.Spec.Monitoring.Interval,
+	}
+	if component.Scheme == "https" {
+		// The API serves a certificate that is not signed by a CA known to Prometheus
+		endpoint.TLSConfig = &monitoringv1.TLSConfig{InsecureSkipVerify: true}
+	}
+	serviceMonitor.Spec = monitoringv1.ServiceMonitorSpec{
+		Endpoints: []monitoringv1.Endpoint{endpoint},
+		Selector: metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"app.kubernetes.io/instance":  instance.GetName(),
+				"app.kubernetes.io/component": GetMetricsServiceName(instance, component),
+			},
+		},
+		NamespaceSelector: monitoringv1.NamespaceSelector{
+			MatchNames: []string{instance.GetNamespace()},
+		},
+	}
+}
+
+// CustomizePrometheusRule writes the default alerts of the CR. The alerts are based
+// on the metrics of the operator.
+func CustomizePrometheusRule(rule *monitoringv1.PrometheusRule, instance *kappnavv1.Kappnav) {
+	rule.Labels = getMonitoringLabels(instance, rule.Labels, &rule.ObjectMeta)
+	days := instance.Spec.Monitoring.CertificateExpiryDays
+	if days <= 0 {
+		days = DefaultCertificateExpiryDays
+	}
+	selector := fmt.Sprintf(`namespace="%s",instance="%s"`, instance.GetNamespace(), instance.GetName())
+	rule.Spec = monitoringv1.PrometheusRuleSpec{
+		Groups: []monitoringv1.RuleGroup{
+			{
+				Name: "kappnav.rules",
+				Rules: []monitoringv1.Rule{
+					{
+						Alert: "KappnavDeploymentUnavailable",
+						Expr:  intstr.FromString(fmt.Sprintf("min by (namespace, instance, deployment) (kappnav_component_ready{%s}) == 0", selector)),
+						For:   "10m",
+						Labels: map[string]string{
+							"severity": "critical",
+						},
+						Annotations: map[string]string{
+							"message": "Deployment {{ $labels.deployment }} of kAppNav {{ $labels.namespace }}/{{ $labels.instance }} has not been ready for 10 minutes.",
+						},
+					},
+					{
+						Alert: "KappnavReconcileFailing",
+						Expr:  intstr.FromString(fmt.Sprintf("kappnav_reconciled{%s} == 0", selector)),
+						For:   "15m",
+						Labels: map[string]string{
+							"severity": "warning",
+						},
+						Annotations: map[string]string{
+							"message": "The operator has failed to reconcile kAppNav {{ $labels.namespace }}/{{ $labels.instance }} for 15 minutes, see the Reconciled condition of the Kappnav CR.",
+						},
+					},
+					{
+						Alert: "KappnavCertificateExpiring",
+						Expr:  intstr.FromString(fmt.Sprintf("kappnav_certificate_expiry_timestamp_seconds{%s} - time() < %d * 86400", selector, days)),
+						For:   "1h",
+						Labels: map[string]string{
+							"severity": "warning",
+						},
+						Annotations: map[string]string{
+							"message": fmt.Sprintf("The certificate in secret {{ $labels.secret }} of kAppNav {{ $labels.namespace }}/{{ $labels.instance }} expires in less than %d days.", days),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// RecordCertificateExpiry records the expiry of the UI serving certificate for
+// the certificate alert. Secrets without a certificate are ignored. The secret is
+// created by the service CA without the managed-by label of the operator, so it
+// is read from the API server.
+func RecordCertificateExpiry(logger Logger, r *ReconcilerBase, instance *kappnavv1.Kappnav) {
+	name := instance.GetName() + "-" + OAuthVolumeName
+	secret := &corev1.Secret{}
+	reader, err := r.GetAPIReader()
+	if err == nil {
+		err = reader.Get(logger.Context(), client.ObjectKey{Namespace: instance.GetNamespace(), Name: name}, secret)
+	}
+	if err != nil {
+		if logger.IsEnabled(LogTypeDebug) {
+			logger.Log(CallerName(), LogTypeDebug, fmt.Sprintf("Could not retrieve secret %s for the certificate expiry, Error: %s ", name, err), logName)
+		}
+		return
+	}
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil {
+		return
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		if logger.IsEnabled(LogTypeWarning) {
+			logger.Log(CallerName(), LogTypeWarning, fmt.Sprintf("Could not parse the certificate in secret %s, Error: %s ", name, err), logName)
+		}
+		return
+	}
+	setCertificateExpiry(instance, name, certificate.NotAfter)
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// NewMultiNamespaceCacheFunc returns a function creating a cache of the objects
+// of a set of namespaces, made of one cache per namespace. Cluster scoped objects
+// cannot be cached with namespaced permissions, they are read from the API server
+// and cannot be watched.
+func NewMultiNamespaceCacheFunc(namespaces []string) manager.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		caches := make(map[string]cache.Cache)
+		for _, namespace := range namespaces {
+			opts.Namespace = namespace
+			c, err := cache.New(config, opts)
+			if err != nil {
+				return nil, err
+			}
+			caches[namespace] = c
+		}
+		reader, err := client.New(config, client.Options{Scheme: opts.Scheme, Mapper: opts.Mapper})
+		if err != nil {
+			return nil, err
+		}
+		return &multiNamespaceCache{
+			namespaces:    namespaces,
+			caches:        caches,
+			clusterReader: reader,
+			scheme:        opts.Scheme,
+			mapper:        opts.Mapper,
+		}, nil
+	}
+}
+
+// multiNamespaceCache is a cache of the objects of a set of namespaces
+type multiNamespaceCache struct {
+	// namespaces are the namespaces in the order they were given
+	namespaces []string
+	caches     map[string]cache.Cache
+	// clusterReader reads the cluster scoped objects from the API server
+	clusterReader client.Reader
+	scheme        *runtime.Scheme
+	mapper        meta.RESTMapper
+}
+
+var _ cache.Cache = &multiNamespaceCache{}
+
+// GetInformer returns an informer delivering the events of the object type in
+// all the namespaces
+func (c *multiNamespaceCache) GetInformer(obj runtime.Object) (toolscache.SharedIndexInformer, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetInformerForKind(gvk)
+}
+
+// GetInformerForKind returns an informer delivering the events of the kind in
+// all the namespaces
+func (c *multiNamespaceCache) GetInformerForKind(gvk schema.GroupVersionKind) (toolscache.SharedIndexInformer, error) {
+	clusterScoped, err := c.isClusterScoped(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if clusterScoped {
+		return nil, fmt.Errorf("cluster scoped kind %s cannot be watched in namespaces %s", gvk.Kind, strings.Join(c.namespaces, ","))
+	}
+	informers := make(map[string]toolscache.SharedIndexInformer)
+	for namespace, nsCache := range c.caches {
+		informer, err := nsCache.GetInformerForKind(gvk)
+		if err != nil {
+			return nil, err
+		}
+		informers[namespace] = informer
+	}
+	return &multiNamespaceInformer{SharedIndexInformer: informers[c.namespaces[0]], informers: informers}, nil
+}
+
+// Start starts the caches of all the namespaces and blocks until stop is closed
+func (c *multiNamespaceCache) Start(stop <-chan struct{}) error {
+	for namespace, nsCache := range c.caches {
+		go func(namespace string, nsCache cache.Cache) {
+			if err := nsCache.Start(stop); err != nil {
+				logger := NewLogger()
+				if logger.IsEnabled(LogTypeError) {
+					logger.Log(CallerName(), LogTypeError, fmt.Sprintf("Cache of namespace %s failed, Error: %s", namespace, err), logName)
+				}
+			}
+		}(namespace, nsCache)
+	}
+	<-stop
+	return nil
+}
+
+// WaitForCacheSync waits until the caches of all the namespaces are synced
+func (c *multiNamespaceCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	synced := true
+	for _, nsCache := range c.caches {
+		if !nsCache.WaitForCacheSync(stop) {
+			synced = false
+		}
+	}
+	return synced
+}
+
+// IndexField adds an index to the caches of all the namespaces
+func (c *multiNamespaceCache) IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	for _, nsCache := range c.caches {
+		if err := nsCache.IndexField(obj, field, extractValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get reads a namespaced object from the cache of its namespace, and a cluster
+// scoped object from the API server
+func (c *multiNamespaceCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	clusterScoped, err := c.isClusterScoped(gvk)
+	if err != nil {
+		return err
+	}
+	if clusterScoped {
+		return c.clusterReader.Get(ctx, key, obj)
+	}
+	nsCache, ok := c.caches[key.Namespace]
+	if !ok {
+		return fmt.Errorf("namespace %s is not watched, the watched namespaces are %s", key.Namespace, strings.Join(c.namespaces, ","))
+	}
+	return nsCache.Get(ctx, key, obj)
+}
+
+// List lists the objects of a namespace from its cache, the namespaced objects of
+// all the namespaces from their caches, or cluster scoped objects from the API
+// server
+func (c *multiNamespaceCache) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(list, c.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	clusterScoped, err := c.isClusterScoped(gvk)
+	if err != nil {
+		return err
+	}
+	if clusterScoped {
+		return c.clusterReader.List(ctx, opts, list)
+	}
+	if opts != nil && len(opts.Namespace) > 0 {
+		nsCache, ok := c.caches[opts.Namespace]
+		if !ok {
+			return fmt.Errorf("namespace %s is not watched, the watched namespaces are %s", opts.Namespace, strings.Join(c.namespaces, ","))
+		}
+		return nsCache.List(ctx, opts, list)
+	}
+
+	var items []runtime.Object
+	for _, namespace := range c.namespaces {
+		nsList := list.DeepCopyObject()
+		if err := c.caches[namespace].List(ctx, opts, nsList); err != nil {
+			return err
+		}
+		nsItems, err := meta.ExtractList(nsList)
+		if err != nil {
+			return err
+		}
+		items = append(items, nsItems...)
+	}
+	return meta.SetList(list, items)
+}
+
+// isClusterScoped returns true if the kind is cluster scoped
+func (c *multiNamespaceCache) isClusterScoped(gvk schema.GroupVersionKind) (bool, error) {
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, err
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameRoot, nil
+}
+
+// multiNamespaceInformer delivers the events of the informers of all the
+// namespaces. Its store and indexer are those of the first namespace, objects
+// are read through the cache.
+type multiNamespaceInformer struct {
+	toolscache.SharedIndexInformer
+	informers map[string]toolscache.SharedIndexInformer
+}
+
+// AddEventHandler adds the handler to the informers of all the namespaces
+func (i *multiNamespaceInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	for _, informer := range i.informers {
+		informer.AddEventHandler(handler)
+	}
+}
+
+// AddEventHandlerWithResyncPeriod adds the handler to the informers of all the
+// namespaces
+func (i *multiNamespaceInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) {
+	for _, informer := range i.informers {
+		informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	}
+}
+
+// AddIndexers adds the indexers to the informers of all the namespaces
+func (i *multiNamespaceInformer) AddIndexers(indexers toolscache.Indexers) error {
+	for _, informer := range i.informers {
+		if err := informer.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasSynced returns true if the informers of all the namespaces have synced
+func (i *multiNamespaceInformer) HasSynced() bool {
+	for _, informer := range i.informers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// Run runs the informers of all the namespaces until stop is closed
+func (i *multiNamespaceInformer) Run(stop <-chan struct{}) {
+	for _, informer := range i.informers {
+		go informer.Run(stop)
+	}
+	<-stop
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	// watchNamespacesMutex guards watchNamespaces
+	watchNamespacesMutex sync.Mutex
+	// watchNamespaces are the namespaces watched by the operator, all namespaces
+	// when empty
+	watchNamespaces []string
+)
+
+// ParseWatchNamespaces splits the comma separated value of WATCH_NAMESPACE into
+// namespaces. Returns no namespaces when all namespaces are watched.
+func ParseWatchNamespaces(value string) []string {
+	var namespaces []string
+	seen := make(map[string]bool)
+	for _, namespace := range strings.Split(value, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if len(namespace) == 0 || seen[namespace] {
+			continue
+		}
+		seen[namespace] = true
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces
+}
+
+// SetWatchNamespaces records the namespaces watched by the operator
+func SetWatchNamespaces(namespaces []string) {
+	watchNamespacesMutex.Lock()
+	defer watchNamespacesMutex.Unlock()
+	watchNamespaces = append([]string(nil), namespaces...)
+}
+
+// GetWatchNamespaces returns the namespaces watched by the operator, or no
+// namespaces when all namespaces are watched
+func GetWatchNamespaces() []string {
+	watchNamespacesMutex.Lock()
+	defer watchNamespacesMutex.Unlock()
+	return append([]string(nil), watchNamespaces...)
+}
+
+// IsClusterWatched returns true if the operator watches all namespaces. Cluster
+// scoped resources can only be watched then.
+func IsClusterWatched() bool {
+	return len(GetWatchNamespaces()) == 0
+}
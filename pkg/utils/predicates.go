@@ -0,0 +1,69 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// IgnoreUnchangedUpdates returns a predicate ignoring the updates that only change
+// the resource version of an object, such as the periodic resyncs of the
+// informers
+func IgnoreUnchangedUpdates() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !isSameObject(e.ObjectOld, e.ObjectNew, false)
+		},
+	}
+}
+
+// IgnoreStatusUpdates returns a predicate ignoring the updates that only change
+// the resource version or the status of an object. It is used for the resources
+// whose status is not reflected in the status of the CR.
+func IgnoreStatusUpdates() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !isSameObject(e.ObjectOld, e.ObjectNew, true)
+		},
+	}
+}
+
+// isSameObject returns true if two versions of an object differ at most in their
+// resource version, and in their status when ignoreStatus is true. Objects that
+// cannot be compared are different.
+func isSameObject(oldObj runtime.Object, newObj runtime.Object, ignoreStatus bool) bool {
+	if oldObj == nil || newObj == nil {
+		return false
+	}
+	oldContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(oldObj)
+	if err != nil {
+		return false
+	}
+	newContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(newObj)
+	if err != nil {
+		return false
+	}
+	for _, content := range []map[string]interface{}{oldContent, newContent} {
+		unstructured.RemoveNestedField(content, "metadata", "resourceVersion")
+		if ignoreStatus {
+			delete(content, "status")
+		}
+	}
+	return reflect.DeepEqual(oldContent, newContent)
+}
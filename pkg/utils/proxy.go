@@ -0,0 +1,139 @@
+/*
+Copyright 2019 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// HTTPProxyEnvVarName ...
+	HTTPProxyEnvVarName string = "HTTP_PROXY"
+	// HTTPSProxyEnvVarName ...
+	HTTPSProxyEnvVarName string = "HTTPS_PROXY"
+	// NoProxyEnvVarName ...
+	NoProxyEnvVarName string = "NO_PROXY"
+	// ClusterProxyName is the name of the cluster-wide OpenShift Proxy resource
+	ClusterProxyName string = "cluster"
+)
+
+// SetProxyDefaults fills in the proxy settings that were not specified in the CR.
+// Values are taken from the OpenShift cluster Proxy resource (OCP only) and then
+// from the proxy environment variables of the operator itself, which are also
+// honoured by the operator's own outbound clients.
+func SetProxyDefaults(logger Logger, r *ReconcilerBase, instance *kappnavv1.Kappnav) {
+	proxy := instance.Spec.Proxy
+	if proxy == nil {
+		proxy = &kappnavv1.KappnavProxyConfiguration{}
+	}
+	if IsOCP(instance.Spec.Env.KubeEnv) {
+		clusterProxy := getClusterProxy(logger, r)
+		if clusterProxy != nil {
+			mergeProxyConfiguration(proxy, clusterProxy)
+		}
+	}
+	mergeProxyConfiguration(proxy, &kappnavv1.KappnavProxyConfiguration{
+		HTTPProxy:  getEnv(HTTPProxyEnvVarName),
+		HTTPSProxy: getEnv(HTTPSProxyEnvVarName),
+		NoProxy:    getEnv(NoProxyEnvVarName),
+	})
+	if len(proxy.HTTPProxy) > 0 || len(proxy.HTTPSProxy) > 0 || len(proxy.NoProxy) > 0 {
+		instance.Spec.Proxy = proxy
+	}
+}
+
+func mergeProxyConfiguration(proxy *kappnavv1.KappnavProxyConfiguration, defaultProxy *kappnavv1.KappnavProxyConfiguration) {
+	if len(proxy.HTTPProxy) == 0 {
+		proxy.HTTPProxy = defaultProxy.HTTPProxy
+	}
+	if len(proxy.HTTPSProxy) == 0 {
+		proxy.HTTPSProxy = defaultProxy.HTTPSProxy
+	}
+	if len(proxy.NoProxy) == 0 {
+		proxy.NoProxy = defaultProxy.NoProxy
+	}
+}
+
+// getEnv returns the value of the upper case environment variable, falling back
+// to the lower case spelling used by some tools.
+func getEnv(name string) string {
+	if value := os.Getenv(name); len(value) > 0 {
+		return value
+	}
+	return os.Getenv(strings.ToLower(name))
+}
+
+// getClusterProxy reads the effective proxy settings from the status of the
+// cluster-wide OpenShift Proxy resource. The Proxy is not created by the operator
+// and is read from the API server.
+func getClusterProxy(logger Logger, r *ReconcilerBase) *kappnavv1.KappnavProxyConfiguration {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "config.openshift.io",
+		Kind:    "Proxy",
+		Version: "v1",
+	})
+	reader, err := r.GetAPIReader()
+	if err == nil {
+		err = reader.Get(logger.Context(), client.ObjectKey{Name: ClusterProxyName}, u)
+	}
+	if err != nil {
+		// Clusters without a Proxy resource or without the Proxy kind have no
+		// cluster-wide proxy, any other error leaves the proxy of the cluster unused
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			if logger.IsEnabled(LogTypeDebug) {
+				logger.Log(CallerName(), LogTypeDebug, fmt.Sprintf("Could not retrieve the cluster Proxy, Error: %s ", err), logName)
+			}
+		} else if logger.IsEnabled(LogTypeWarning) {
+			logger.Log(CallerName(), LogTypeWarning, fmt.Sprintf("Could not retrieve the cluster Proxy, its settings are not applied, Error: %s ", err), logName)
+		}
+		return nil
+	}
+	httpProxy, _, _ := unstructured.NestedString(u.Object, "status", "httpProxy")
+	httpsProxy, _, _ := unstructured.NestedString(u.Object, "status", "httpsProxy")
+	noProxy, _, _ := unstructured.NestedString(u.Object, "status", "noProxy")
+	return &kappnavv1.KappnavProxyConfiguration{
+		HTTPProxy:  httpProxy,
+		HTTPSProxy: httpsProxy,
+		NoProxy:    noProxy,
+	}
+}
+
+// addProxyEnv sets the proxy environment variables on the container.
+func addProxyEnv(container *corev1.Container, instance *kappnavv1.Kappnav) {
+	proxy := instance.Spec.Proxy
+	if proxy == nil {
+		return
+	}
+	values := []corev1.EnvVar{
+		{Name: HTTPProxyEnvVarName, Value: proxy.HTTPProxy},
+		{Name: HTTPSProxyEnvVarName, Value: proxy.HTTPSProxy},
+		{Name: NoProxyEnvVarName, Value: proxy.NoProxy},
+	}
+	for _, envVar := range values {
+		if len(envVar.Value) > 0 {
+			container.Env = append(container.Env, envVar)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2019 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+var (
+	readinessMutex  sync.RWMutex
+	readinessChecks = make(map[string]func() error)
+)
+
+// AddReadinessCheck registers a check that must return nil before the operator
+// reports itself as ready.
+func AddReadinessCheck(name string, check func() error) {
+	readinessMutex.Lock()
+	defer readinessMutex.Unlock()
+	readinessChecks[name] = check
+}
+
+// ReadinessHandler serves the readiness endpoint. It responds with 200 when all
+// registered checks pass and with 503 listing the failed checks otherwise.
+func ReadinessHandler(w http.ResponseWriter, req *http.Request) {
+	readinessMutex.RLock()
+	var names []string
+	for name := range readinessChecks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var failures []string
+	for _, name := range names {
+		if err := readinessChecks[name](); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+	readinessMutex.RUnlock()
+
+	if len(failures) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		for _, failure := range failures {
+			fmt.Fprintln(w, failure)
+		}
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+// HealthHandler serves the liveness endpoint, which only reports that the
+// process is able to serve requests.
+func HealthHandler(w http.ResponseWriter, req *http.Request) {
+	fmt.Fprintln(w, "ok")
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	t.Cleanup(func() {
+		readinessMutex.Lock()
+		delete(readinessChecks, CRDReadinessCheckName)
+		readinessMutex.Unlock()
+	})
+
+	serve := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		ReadinessHandler(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return recorder
+	}
+
+	if recorder := serve(); recorder.Code != http.StatusOK {
+		t.Errorf("got status %d without checks, want %d", recorder.Code, http.StatusOK)
+	}
+
+	bootstrap := NewCRDBootstrap(NewLogger(), nil, nil, nil)
+	AddReadinessCheck(CRDReadinessCheckName, bootstrap.Check)
+	recorder := serve()
+	if recorder.Code != http.StatusServiceUnavailable || !strings.Contains(recorder.Body.String(), "crds: CRD bootstrap is in progress") {
+		t.Errorf("got status %d and body %q during the bootstrap, want %d naming the check", recorder.Code, recorder.Body.String(), http.StatusServiceUnavailable)
+	}
+
+	bootstrap.setResult(errors.New("forbidden"))
+	if recorder := serve(); recorder.Code != http.StatusServiceUnavailable || !strings.Contains(recorder.Body.String(), "crds: forbidden") {
+		t.Errorf("got status %d and body %q after a failure, want %d with the failure", recorder.Code, recorder.Body.String(), http.StatusServiceUnavailable)
+	}
+
+	bootstrap.setResult(nil)
+	if recorder := serve(); recorder.Code != http.StatusOK {
+		t.Errorf("got status %d once the CRDs are established, want %d", recorder.Code, http.StatusOK)
+	}
+}
@@ -17,9 +17,12 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sync"
 	"time"
 
 	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -45,6 +48,14 @@ type ReconcilerBase struct {
 	recorder   record.EventRecorder
 	restConfig *rest.Config
 	discovery  discovery.DiscoveryInterface
+	apiReader  *apiReader
+}
+
+// apiReader is the reader of a ReconcilerBase reading from the API server. It is
+// shared by the copies of the ReconcilerBase and created on first use.
+type apiReader struct {
+	mutex  sync.Mutex
+	reader client.Reader
 }
 
 // NewReconcilerBase creates a new ReconcilerBase
@@ -54,6 +65,7 @@ func NewReconcilerBase(client client.Client, scheme *runtime.Scheme, restConfig
 		scheme:     scheme,
 		recorder:   recorder,
 		restConfig: restConfig,
+		apiReader:  &apiReader{},
 	}
 }
 
@@ -88,9 +100,34 @@ func (r *ReconcilerBase) SetDiscoveryClient(discovery discovery.DiscoveryInterfa
 	r.discovery = discovery
 }
 
+// GetAPIReader returns a reader reading from the API server. It reads the objects
+// that must not or cannot be read from the cache, e.g. before the cache is started,
+// and the objects the cache does not hold, such as the config maps of users and
+// of other components, which do not have the managed-by label of the operator.
+func (r *ReconcilerBase) GetAPIReader() (client.Reader, error) {
+	if r.apiReader == nil {
+		return nil, fmt.Errorf("the reconciler was not created with NewReconcilerBase")
+	}
+	r.apiReader.mutex.Lock()
+	defer r.apiReader.mutex.Unlock()
+	if r.apiReader.reader == nil {
+		reader, err := client.New(r.restConfig, client.Options{Scheme: r.scheme})
+		if err != nil {
+			return nil, err
+		}
+		r.apiReader.reader = reader
+	}
+
+	return r.apiReader.reader, nil
+}
+
 // CreateOrUpdate ...
-func (r *ReconcilerBase) CreateOrUpdate(logger Logger, obj metav1.Object, owner metav1.Object, reconcile func() error) error {
+func (r *ReconcilerBase) CreateOrUpdate(logger Logger, obj metav1.Object, owner metav1.Object, reconcile func() error) (err error) {
+	// The resource version of the existing resource, which is only changed by an
+	// update that changes the resource
+	var resourceVersion string
 	mutate := func(o runtime.Object) error {
+		resourceVersion = obj.GetResourceVersion()
 		err := reconcile()
 		return err
 	}
@@ -104,25 +141,37 @@ func (r *ReconcilerBase) CreateOrUpdate(logger Logger, obj metav1.Object, owner
 		}
 		return err
 	}
-	result, err := controllerutil.CreateOrUpdate(context.TODO(), r.GetClient(), runtimeObj, mutate)
+	gvk, gvkErr := apiutil.GVKForObject(runtimeObj, r.scheme)
+	logger, span := StartSpan(logger, "CreateOrUpdate", ObjectAttributes(gvk.Kind, obj.GetNamespace(), obj.GetName())...)
+	defer func() {
+		EndSpan(span, err)
+	}()
+
+	result, err := controllerutil.CreateOrUpdate(logger.Context(), r.GetClient(), runtimeObj, mutate)
 	if err != nil {
 		return err
 	}
-
-	var gvk schema.GroupVersionKind
-	gvk, err = apiutil.GVKForObject(runtimeObj, r.scheme)
-	if err == nil {
+	span.SetAttributes(TraceAttributeResult.String(string(result)))
+
+	if gvkErr == nil {
+		// An update of an existing resource corrects a difference from the desired state.
+		// The desired state lacks the defaults set by the API server, so the update is
+		// made whenever the resource is reconciled, but the API server only stores it,
+		// with a new resource version, if it changes the resource.
+		if result == controllerutil.OperationResultUpdated && obj.GetResourceVersion() != resourceVersion {
+			recordDrift(gvk.Kind)
+		}
 		if (logger.IsEnabled(LogTypeInfo)) {
 			logger.Log(CallerName(), LogTypeInfo, fmt.Sprintf("Reconciled, Kind: %s, Name: %s, Status: %s ", gvk.Kind, obj.GetName(), result), logName)
 		}			
 	}
 
-	return err
+	return nil
 }
 
 // DeleteResource deletes kubernetes resource
 func (r *ReconcilerBase) DeleteResource(obj runtime.Object) error {
-	logger := NewLogger(true) //log in JSON format
+	logger := NewLogger()
 
 	err := r.client.Delete(context.TODO(), obj)
 	if err != nil {
@@ -166,7 +215,11 @@ func (r *ReconcilerBase) DeleteResources(resources []runtime.Object) error {
 }
 
 // GetOperatorConfigMap ...
-func (r *ReconcilerBase) GetOperatorConfigMap(logger Logger, name string, ns string) (*corev1.ConfigMap, error) {
+func (r *ReconcilerBase) GetOperatorConfigMap(logger Logger, name string, ns string) (configMap *corev1.ConfigMap, err error) {
+	logger, span := StartSpan(logger, "GetOperatorConfigMap", ObjectAttributes("ConfigMap", ns, name)...)
+	defer func() {
+		EndSpan(span, err)
+	}()
 	if (logger.IsEnabled(LogTypeInfo)) {
 		logger.Log(CallerName(), LogTypeInfo, fmt.Sprintf("Attempting to read ConfigMap, name: %s, namespace: %s", name, ns), logName)
 	}
@@ -176,7 +229,11 @@ func (r *ReconcilerBase) GetOperatorConfigMap(logger Logger, name string, ns str
 		Kind:    "ConfigMap",
 		Version: "v1",
 	})
-	err := r.GetClient().Get(context.TODO(), client.ObjectKey{
+	reader, err := r.GetAPIReader()
+	if err != nil {
+		return nil, err
+	}
+	err = reader.Get(logger.Context(), client.ObjectKey{
 		Namespace: ns,
 		Name:      name,
 	}, u)
@@ -191,7 +248,7 @@ func (r *ReconcilerBase) GetOperatorConfigMap(logger Logger, name string, ns str
 	if err != nil {
 		return nil, err
 	}
-	configMap := &corev1.ConfigMap{}
+	configMap = &corev1.ConfigMap{}
 	err = yaml.Unmarshal(b, configMap)
 	if err != nil {
 		return nil, err
@@ -202,6 +259,8 @@ func (r *ReconcilerBase) GetOperatorConfigMap(logger Logger, name string, ns str
 // ManageError ...
 func (r *ReconcilerBase) ManageError(logger Logger, issue error, conditionType kappnavv1.StatusConditionType, cr *kappnavv1.Kappnav) (reconcile.Result, error) {
 	r.GetRecorder().Event(cr, "Warning", "ProcessingError", issue.Error())
+	// The issue fails the current span, e.g. the span of the reconcile
+	RecordSpanError(trace.SpanFromContext(logger.Context()), issue)
 
 	oldCondition := GetCondition(conditionType, &cr.Status)
 	if oldCondition == nil {
@@ -228,8 +287,11 @@ func (r *ReconcilerBase) ManageError(logger Logger, issue error, conditionType k
 	}
 
 	SetCondition(newCondition, &cr.Status)
+	if conditionType == kappnavv1.StatusConditionTypeReconciled {
+		RecordReconcileResult(cr, issue)
+	}
 
-	err := r.GetClient().Status().Update(context.Background(), cr)
+	err := r.GetClient().Status().Update(logger.Context(), cr)
 	if err != nil {
 		if (logger.IsEnabled(LogTypeError)) {
 			logger.Log(CallerName(), LogTypeError, fmt.Sprintf("Unable to update status, Error: %s ", err), logName)
@@ -284,7 +346,10 @@ func (r *ReconcilerBase) ManageSuccess(logger Logger, conditionType kappnavv1.St
 	}
 
 	SetCondition(statusCondition, &cr.Status)
-	err := r.GetClient().Status().Update(context.Background(), cr)
+	if conditionType == kappnavv1.StatusConditionTypeReconciled {
+		RecordReconcileResult(cr, nil)
+	}
+	err := r.GetClient().Status().Update(logger.Context(), cr)
 	if err != nil {
 		if (logger.IsEnabled(LogTypeError)) {
 			logger.Log(CallerName(), LogTypeError, fmt.Sprintf("Unable to update status, Error: %s ", err), logName)
@@ -298,8 +363,12 @@ func (r *ReconcilerBase) ManageSuccess(logger Logger, conditionType kappnavv1.St
 }
 
 // IsGroupVersionSupported ...
-func (r *ReconcilerBase) IsGroupVersionSupported(groupVersion string) (bool, error) {
-	logger := NewLogger(true)  //log in JSON format
+func (r *ReconcilerBase) IsGroupVersionSupported(logger Logger, groupVersion string) (supported bool, err error) {
+	_, span := StartSpan(logger, "IsGroupVersionSupported", attribute.String("kappnav.groupVersion", groupVersion))
+	defer func() {
+		span.SetAttributes(attribute.Bool("kappnav.supported", supported))
+		EndSpan(span, err)
+	}()
 
 	cli, err := r.GetDiscoveryClient()
 	if err != nil {
@@ -0,0 +1,206 @@
+/*
+Copyright 2019 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// deploymentRevisionAnnotation holds the revision of a Deployment and of its
+// ReplicaSets
+const deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// SetComponentStatus records the desired image, the image IDs of the running pods
+// and the rollout progress of every container of the given Deployments in the
+// status of the CR. The caller is responsible for writing the status.
+func SetComponentStatus(logger Logger, r *ReconcilerBase, instance *kappnavv1.Kappnav, deployments ...*appsv1.Deployment) {
+	var components []kappnavv1.ComponentStatus
+	for _, deployment := range deployments {
+		components = append(components, getDeploymentComponentStatus(logger, r, deployment)...)
+	}
+	for i := range components {
+		old := GetComponentStatus(components[i].Deployment, components[i].Name, &instance.Status)
+		if old != nil && isComponentStatusUnchanged(old, &components[i]) {
+			components[i].LastUpdateTime = old.LastUpdateTime
+		}
+	}
+	instance.Status.Components = components
+	setComponentMetrics(instance, components)
+}
+
+// GetComponentStatus ...
+func GetComponentStatus(deployment string, name string, status *kappnavv1.KappnavStatus) *kappnavv1.ComponentStatus {
+	for i := range status.Components {
+		if status.Components[i].Deployment == deployment && status.Components[i].Name == name {
+			return &status.Components[i]
+		}
+	}
+	return nil
+}
+
+func getDeploymentComponentStatus(logger Logger, r *ReconcilerBase, deployment *appsv1.Deployment) []kappnavv1.ComponentStatus {
+	now := metav1.Now()
+	current := &appsv1.Deployment{}
+	err := r.GetClient().Get(context.TODO(), client.ObjectKey{
+		Namespace: deployment.GetNamespace(),
+		Name:      deployment.GetName(),
+	}, current)
+	if err != nil {
+		if logger.IsEnabled(LogTypeError) {
+			logger.Log(CallerName(), LogTypeError, fmt.Sprintf("Could not retrieve Deployment %s for status, Error: %s ", deployment.GetName(), err), logName)
+		}
+		var components []kappnavv1.ComponentStatus
+		for _, c := range deployment.Spec.Template.Spec.Containers {
+			components = append(components, kappnavv1.ComponentStatus{
+				Name:           c.Name,
+				Deployment:     deployment.GetName(),
+				DesiredImage:   c.Image,
+				RolloutState:   kappnavv1.RolloutStateUnknown,
+				LastUpdateTime: now,
+			})
+		}
+		return components
+	}
+
+	pods := &corev1.PodList{}
+	err = r.GetClient().List(context.TODO(), &client.ListOptions{
+		Namespace:     current.GetNamespace(),
+		LabelSelector: labels.SelectorFromSet(current.Spec.Selector.MatchLabels),
+	}, pods)
+	if err != nil {
+		if logger.IsEnabled(LogTypeError) {
+			logger.Log(CallerName(), LogTypeError, fmt.Sprintf("Could not list pods of Deployment %s for status, Error: %s ", current.GetName(), err), logName)
+		}
+		pods = &corev1.PodList{}
+	}
+	// The pods of the current ReplicaSet are up to date. Their images cannot be
+	// compared with the images of the template, as the kubelet reports normalized
+	// image names.
+	podTemplateHash, err := getCurrentPodTemplateHash(r, current)
+	if err != nil {
+		if logger.IsEnabled(LogTypeError) {
+			logger.Log(CallerName(), LogTypeError, fmt.Sprintf("Could not list ReplicaSets of Deployment %s for status, Error: %s ", current.GetName(), err), logName)
+		}
+	}
+
+	replicas := int32(0)
+	if current.Spec.Replicas != nil {
+		replicas = *current.Spec.Replicas
+	}
+	rolloutState := GetRolloutState(current)
+	var components []kappnavv1.ComponentStatus
+	for _, c := range current.Spec.Template.Spec.Containers {
+		component := kappnavv1.ComponentStatus{
+			Name:            c.Name,
+			Deployment:      current.GetName(),
+			DesiredImage:    c.Image,
+			Replicas:        replicas,
+			ReadyReplicas:   current.Status.ReadyReplicas,
+			UpdatedReplicas: current.Status.UpdatedReplicas,
+			RolloutState:    rolloutState,
+			LastUpdateTime:  now,
+		}
+		for _, pod := range pods.Items {
+			if pod.GetDeletionTimestamp() != nil {
+				continue
+			}
+			for _, cs := range pod.Status.ContainerStatuses {
+				if cs.Name != c.Name {
+					continue
+				}
+				if len(podTemplateHash) > 0 && pod.GetLabels()[appsv1.DefaultDeploymentUniqueLabelKey] == podTemplateHash {
+					component.UpToDatePods++
+				}
+				if len(cs.ImageID) > 0 && !ContainString(component.ImageIDs, cs.ImageID) {
+					component.ImageIDs = append(component.ImageIDs, cs.ImageID)
+				}
+			}
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// getCurrentPodTemplateHash returns the pod-template-hash label of the ReplicaSet
+// of the current revision of the Deployment, or "" if the Deployment controller
+// has not created it yet.
+func getCurrentPodTemplateHash(r *ReconcilerBase, deployment *appsv1.Deployment) (string, error) {
+	revision := deployment.GetAnnotations()[deploymentRevisionAnnotation]
+	if len(revision) == 0 {
+		return "", nil
+	}
+	replicaSets := &appsv1.ReplicaSetList{}
+	err := r.GetClient().List(context.TODO(), &client.ListOptions{
+		Namespace:     deployment.GetNamespace(),
+		LabelSelector: labels.SelectorFromSet(deployment.Spec.Selector.MatchLabels),
+	}, replicaSets)
+	if err != nil {
+		return "", err
+	}
+	for i := range replicaSets.Items {
+		rs := &replicaSets.Items[i]
+		if metav1.IsControlledBy(rs, deployment) && rs.GetAnnotations()[deploymentRevisionAnnotation] == revision {
+			return rs.GetLabels()[appsv1.DefaultDeploymentUniqueLabelKey], nil
+		}
+	}
+	return "", nil
+}
+
+// GetRolloutState derives the rollout state of a Deployment the same way
+// "kubectl rollout status" does.
+func GetRolloutState(deployment *appsv1.Deployment) kappnavv1.RolloutState {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing &&
+			condition.Reason == "ProgressDeadlineExceeded" {
+			return kappnavv1.RolloutStateFailed
+		}
+	}
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	if deployment.Status.ObservedGeneration < deployment.GetGeneration() ||
+		deployment.Status.UpdatedReplicas < replicas ||
+		deployment.Status.Replicas > deployment.Status.UpdatedReplicas ||
+		deployment.Status.AvailableReplicas < deployment.Status.UpdatedReplicas {
+		return kappnavv1.RolloutStateProgressing
+	}
+	return kappnavv1.RolloutStateComplete
+}
+
+func isComponentStatusUnchanged(old *kappnavv1.ComponentStatus, new *kappnavv1.ComponentStatus) bool {
+	if old.DesiredImage != new.DesiredImage ||
+		old.UpToDatePods != new.UpToDatePods ||
+		old.Replicas != new.Replicas ||
+		old.ReadyReplicas != new.ReadyReplicas ||
+		old.UpdatedReplicas != new.UpdatedReplicas ||
+		old.RolloutState != new.RolloutState ||
+		len(old.ImageIDs) != len(new.ImageIDs) {
+		return false
+	}
+	for _, imageID := range new.ImageIDs {
+		if !ContainString(old.ImageIDs, imageID) {
+			return false
+		}
+	}
+	return true
+}
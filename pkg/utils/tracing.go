@@ -0,0 +1,136 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracingExporterEnvVarName selects the exporter of the spans: otlp, stdout or
+	// none. Tracing is disabled when it is not set.
+	TracingExporterEnvVarName string = "KAPPNAV_TRACING_EXPORTER"
+	// TracingSampleRatioEnvVarName is the ratio of reconciles traced, 1 by default
+	TracingSampleRatioEnvVarName string = "KAPPNAV_TRACING_SAMPLE_RATIO"
+	// TracingExporterOTLP exports the spans over OTLP/gRPC to the endpoint set in
+	// OTEL_EXPORTER_OTLP_ENDPOINT, localhost:4317 by default
+	TracingExporterOTLP string = "otlp"
+	// TracingExporterStdout writes the spans to stdout
+	TracingExporterStdout string = "stdout"
+	// TracingExporterNone disables tracing
+	TracingExporterNone string = "none"
+	// tracerName is the name of the tracer of the operator
+	tracerName string = "github.com/kappnav/operator"
+)
+
+const (
+	// TraceAttributeKind is the kind of the object a span operates on
+	TraceAttributeKind = attribute.Key("kappnav.kind")
+	// TraceAttributeNamespace is the namespace of the object a span operates on
+	TraceAttributeNamespace = attribute.Key("kappnav.namespace")
+	// TraceAttributeName is the name of the object a span operates on
+	TraceAttributeName = attribute.Key("kappnav.name")
+	// TraceAttributeComponent is the component of the CR a span reconciles
+	TraceAttributeComponent = attribute.Key("kappnav.component")
+	// TraceAttributeResult is the outcome of an operation, e.g. created or unchanged
+	TraceAttributeResult = attribute.Key("kappnav.result")
+)
+
+// InitTracing installs the tracer provider selected by the environment of the
+// operator. The returned function flushes the spans and must be called before the
+// process exits. Spans are not recorded when tracing is disabled.
+func InitTracing(ctx context.Context, serviceName string, serviceVersion string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch name := os.Getenv(TracingExporterEnvVarName); name {
+	case "", TracingExporterNone:
+		return noop, nil
+	case TracingExporterOTLP:
+		exporter, err = otlptracegrpc.New(ctx)
+	case TracingExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return noop, fmt.Errorf("%s: unknown exporter %q, use %s, %s or %s", TracingExporterEnvVarName,
+			name, TracingExporterOTLP, TracingExporterStdout, TracingExporterNone)
+	}
+	if err != nil {
+		return noop, err
+	}
+
+	ratio := 1.0
+	if value := os.Getenv(TracingSampleRatioEnvVarName); len(value) > 0 {
+		ratio, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return noop, fmt.Errorf("%s: %v", TracingSampleRatioEnvVarName, err)
+		}
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(serviceVersion))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span as a child of the span carried by the logger. It
+// returns a logger carrying the new span, so that the spans started with it are
+// children of the new span and its messages carry the trace and span IDs.
+func StartSpan(logger Logger, name string, attributes ...attribute.KeyValue) (Logger, trace.Span) {
+	ctx, span := otel.Tracer(tracerName).Start(logger.Context(), name, trace.WithAttributes(attributes...))
+	return logger.WithContext(ctx), span
+}
+
+// EndSpan ends a span, recording the error if the operation failed
+func EndSpan(span trace.Span, err error) {
+	RecordSpanError(span, err)
+	span.End()
+}
+
+// RecordSpanError marks a span as failed with the error, if any
+func RecordSpanError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// ObjectAttributes returns the span attributes identifying an object
+func ObjectAttributes(kind string, namespace string, name string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		TraceAttributeKind.String(kind),
+		TraceAttributeNamespace.String(namespace),
+		TraceAttributeName.String(name),
+	}
+}
@@ -38,6 +38,8 @@ import (
 )
 
 // KappnavExtension extends the reconciler to manage additional resources.
+// Extensions are registered with RegisterKappnavExtension. The status of the CR
+// is written by the caller.
 type KappnavExtension interface {
 	ReconcileAdditionalResources(logger Logger, request reconcile.Request, r *ReconcilerBase, instance *kappnavv1.Kappnav) (reconcile.Result, error)
 }
@@ -66,13 +68,41 @@ const (
 	OAuthVolumeMountPath string = "/etc/tls/private"
 )
 
+const (
+	// TrustedCAVolumeName ...
+	TrustedCAVolumeName string = "trusted-ca"
+	// TrustedCAVolumeMountPath is the standard location of the extracted CA bundle on UBI/RHEL images
+	TrustedCAVolumeMountPath string = "/etc/pki/ca-trust/extracted/pem"
+	// TrustedCABundleFileName ...
+	TrustedCABundleFileName string = "tls-ca-bundle.pem"
+	// TrustedCAConfigMapKey is the default key of the CA bundle in the trusted CA ConfigMap
+	TrustedCAConfigMapKey string = "ca-bundle.crt"
+	// TrustedCAInjectLabelName requests OpenShift to inject the cluster CA bundle into a ConfigMap
+	TrustedCAInjectLabelName string = "config.openshift.io/inject-trusted-cabundle"
+)
+
+// reservedEnvVarNames are the environment variables owned by the operator.
+// Values found for these names on existing containers are not preserved.
+var reservedEnvVarNames = []string{
+	"KAPPNAV_CR_NAME",
+	"KAPPNAV_CONFIG_NAMESPACE",
+	"KUBE_ENV",
+	HTTPProxyEnvVarName,
+	HTTPSProxyEnvVarName,
+	NoProxyEnvVarName,
+	LoggingDirEnvVarName,
+	LoggingComponentEnvVarName,
+	LogLevelEnvVarName,
+	LogPackagesEnvVarName,
+}
+
 // GetLabels ...
 func GetLabels(instance *kappnavv1.Kappnav,
 	existingLabels map[string]string, component *metav1.ObjectMeta, mapType string) map[string]string {
 	labels := make(map[string]string)
 	labels["app.kubernetes.io/name"] = instance.Name
 	labels["app.kubernetes.io/instance"] = instance.Name
-	labels["app.kubernetes.io/managed-by"] = "kappnav-operator"
+	labels[ManagedByLabel] = ManagedByOperator
 	if existingLabels["kappnav.io/map-type"] == ""  {
 		if strings.HasSuffix(mapType, "action") {
 			labels["kappnav.io/map-type"] = "action"
@@ -93,7 +123,7 @@ func GetLabels(instance *kappnavv1.Kappnav,
 	for key, value := range instance.Labels {
 		if key != "app.kubernetes.io/instance" &&
 			key != "app.kubernetes.io/component" &&
-			key != "app.kubernetes.io/managed-by" {
+			key != ManagedByLabel {
 			labels[key] = value
 		}
 	}
@@ -134,6 +164,8 @@ func CustomizeServiceAccount(logger Logger, sa *corev1.ServiceAccount, uiService
 func CustomizeClusterRoleBinding(crb *rbacv1.ClusterRoleBinding,
 	sa *corev1.ServiceAccount, instance *kappnavv1.Kappnav) {
 	crb.Labels = GetLabels(instance, crb.Labels, &crb.ObjectMeta, "")
+	// The namespace of the CR identifies the owner of the cluster scoped binding
+	crb.Labels[InstanceNamespaceLabel] = instance.GetNamespace()
 	crb.Subjects = []rbacv1.Subject{
 		{
 			Kind:      "ServiceAccount",
@@ -153,6 +185,13 @@ func CustomizeConfigMap(configMap *corev1.ConfigMap, instance *kappnavv1.Kappnav
 	configMap.Labels = GetLabels(instance, configMap.Labels, &configMap.ObjectMeta, mapType)
 }
 
+// CustomizeTrustedCAConfigMap ...
+func CustomizeTrustedCAConfigMap(configMap *corev1.ConfigMap, instance *kappnavv1.Kappnav) {
+	configMap.Labels = GetLabels(instance, configMap.Labels, &configMap.ObjectMeta, "")
+	// OpenShift fills in the data section of the map when this label is present.
+	configMap.Labels[TrustedCAInjectLabelName] = "true"
+}
+
 // CustomizeSecret ...
 func CustomizeSecret(secret *corev1.Secret, instance *kappnavv1.Kappnav) {
 	secret.Labels = GetLabels(instance, secret.Labels, &secret.ObjectMeta, "")
@@ -219,6 +258,14 @@ func CustomizeUIServiceSpec(serviceSpec *corev1.ServiceSpec, instance *kappnavv1
 // CustomizeIngress ...
 func CustomizeIngress(ingress *extensionsv1beta1.Ingress, instance *kappnavv1.Kappnav) {
 	ingress.Labels = GetLabels(instance, ingress.Labels, &ingress.ObjectMeta, "")
+	if instance.Spec.Ingress != nil && len(instance.Spec.Ingress.Annotations) > 0 {
+		if ingress.Annotations == nil {
+			ingress.Annotations = make(map[string]string)
+		}
+		for key, value := range instance.Spec.Ingress.Annotations {
+			ingress.Annotations[key] = value
+		}
+	}
 }
 
 // CustomizeUIIngressSpec ...
@@ -250,6 +297,9 @@ func CustomizeUIIngressSpec(ingressSpec *extensionsv1beta1.IngressSpec,
 			},
 		}
 	}
+	if instance.Spec.Ingress != nil {
+		ingressSpec.Rules[0].Host = instance.Spec.Ingress.Host
+	}
 }
 
 // CustomizeRoute ...
@@ -264,6 +314,14 @@ func CustomizeUIRouteSpec(routeSpec *routev1.RouteSpec,
 		routeSpec.TLS = &routev1.TLSConfig{}
 	}
 	routeSpec.TLS.Termination = routev1.TLSTerminationReencrypt
+	if instance.Spec.Route != nil {
+		if len(instance.Spec.Route.Termination) > 0 {
+			routeSpec.TLS.Termination = routev1.TLSTerminationType(instance.Spec.Route.Termination)
+		}
+		if len(instance.Spec.Route.Host) > 0 {
+			routeSpec.Host = instance.Spec.Route.Host
+		}
+	}
 	routeSpec.To.Kind = "Service"
 	routeSpec.To.Name = routeName.GetName()
 }
@@ -297,20 +355,17 @@ func CustomizePodSpec(pts *corev1.PodTemplateSpec, parentComponent *metav1.Objec
 // CustomizeBuiltinConfigMap ...
 func CustomizeBuiltinConfigMap(logger Logger, builtinConfig *corev1.ConfigMap, r *ReconcilerBase, instance *kappnavv1.Kappnav) {
 	// Initialize the config map or restore values if they have been deleted.
+	// Keys with the AlwaysSync policy are overwritten with the current values.
 	if builtinConfig.Data == nil {
 		builtinConfig.Data = make(map[string]string)
 	}
 	kubeEnv := instance.Spec.Env.KubeEnv
 	if IsMinikubeEnv(kubeEnv) {
-		value, _ := builtinConfig.Data["openshift-console-url"]
-		if len(value) == 0 {
-			builtinConfig.Data["openshift-console-url"] =
-				"http://127.0.0.1:8001/api/v1/namespaces/kube-system/services/http:kubernetes-dashboard:/proxy/#!"
-		}
+		setBuiltinValue(logger, builtinConfig, instance, BuiltinConsoleURLKey,
+			"http://127.0.0.1:8001/api/v1/namespaces/kube-system/services/http:kubernetes-dashboard:/proxy/#!")
 	} else if IsOpenShift(kubeEnv) {
-		value, _ := builtinConfig.Data["openshift-console-url"]
-		adminValue, _ := builtinConfig.Data["openshift-admin-console-url"]
-		if len(value) == 0 || len(adminValue) == 0 {
+		if isBuiltinValueNeeded(builtinConfig, instance, BuiltinConsoleURLKey) ||
+			isBuiltinValueNeeded(builtinConfig, instance, BuiltinAdminConsoleURLKey) {
 			var publicURL string
 			var adminPublicURL string
 			if IsOCP(kubeEnv) {
@@ -327,30 +382,14 @@ func CustomizeBuiltinConfigMap(logger Logger, builtinConfig *corev1.ConfigMap, r
 					adminPublicURL = clusterInfo.AdminConsolePublicURL
 				}
 			}
-			if len(value) == 0 && len(publicURL) > 0 {
-				builtinConfig.Data["openshift-console-url"] = publicURL
-			}
-			if len(adminValue) == 0 && len(adminPublicURL) > 0 {
-				builtinConfig.Data["openshift-admin-console-url"] = adminPublicURL
-			}
+			setBuiltinValue(logger, builtinConfig, instance, BuiltinConsoleURLKey, publicURL)
+			setBuiltinValue(logger, builtinConfig, instance, BuiltinAdminConsoleURLKey, adminPublicURL)
 		}
 	}
-	value, _ := builtinConfig.Data["liberty-problems-dashboard"]
-	if len(value) == 0 {
-		builtinConfig.Data["liberty-problems-dashboard"] = "Liberty-Problems-K5-20190909"
-	}
-	value, _ = builtinConfig.Data["liberty-traffic-dashboard"]
-	if len(value) == 0 {
-		builtinConfig.Data["liberty-traffic-dashboard"] = "Liberty-Traffic-K5-20190909"
-	}
-	value, _ = builtinConfig.Data["grafana-dashboard"]
-	if len(value) == 0 {
-		builtinConfig.Data["grafana-dashboard"] = "Liberty-Metrics-G5-20190521"
-	}
-	value, _ = builtinConfig.Data["grafana-m2-dashboard"]
-	if len(value) == 0 {
-		builtinConfig.Data["grafana-m2-dashboard"] = "Liberty-Metrics-M2-G5-20190521"
-	}
+	setBuiltinValue(logger, builtinConfig, instance, "liberty-problems-dashboard", "Liberty-Problems-K5-20190909")
+	setBuiltinValue(logger, builtinConfig, instance, "liberty-traffic-dashboard", "Liberty-Traffic-K5-20190909")
+	setBuiltinValue(logger, builtinConfig, instance, "grafana-dashboard", "Liberty-Metrics-G5-20190521")
+	setBuiltinValue(logger, builtinConfig, instance, "grafana-m2-dashboard", "Liberty-Metrics-M2-G5-20190521")
 }
 
 // CustomizeKappnavConfigMap ...
@@ -422,7 +461,8 @@ func CreateUIDeploymentContainers(existingContainers []corev1.Container, instanc
 			instance.Spec.ExtensionContainers[OAuthProxyContainerConfigKey], oauthProxyEnv, nil, nil,
 			createOAuthProxyPorts(instance), createOAuthProxyArgs(instance), createOAuthProxyVolumeMount(instance)))
 	}
-	return containers
+	containers = addTargetedExtensionContainers(containers, existingContainers, instance, kappnavv1.ExtensionContainerTargetUI)
+	return addExtensionContainers(containers, instance, ExtensionTargetUI)
 }
 
 // CreateControllerDeploymentContainers ...
@@ -440,18 +480,20 @@ func CreateControllerDeploymentContainers(existingContainers []corev1.Container,
 			}
 		}
 	}
-	return []corev1.Container{
+	containers := []corev1.Container{
 		*createContainer(APIContainerName, instance, instance.Spec.AppNavAPI, apiEnv,
 			createAPIReadinessProbe(), createAPILivenessProbe(), nil, nil, nil),
 		*createContainer(ControllerContainerName, instance, instance.Spec.AppNavController, controllerEnv,
 			createControllerReadinessProbe(), createControllerLivenessProbe(), nil, nil, nil),
 	}
+	containers = addTargetedExtensionContainers(containers, existingContainers, instance, kappnavv1.ExtensionContainerTargetController)
+	return addExtensionContainers(containers, instance, ExtensionTargetController)
 }
 
 // CreateUIVolumes ...
 func CreateUIVolumes(instance *kappnavv1.Kappnav) []corev1.Volume {
 	name := instance.Name + "-" + OAuthVolumeName
-	return []corev1.Volume{
+	volumes := []corev1.Volume{
 		{
 			Name: name,
 			VolumeSource: corev1.VolumeSource{
@@ -461,6 +503,121 @@ func CreateUIVolumes(instance *kappnavv1.Kappnav) []corev1.Volume {
 			},
 		},
 	}
+	if trustedCAVolume := createTrustedCAVolume(instance); trustedCAVolume != nil {
+		volumes = append(volumes, *trustedCAVolume)
+	}
+	if loggingVolume := createLoggingVolume(instance); loggingVolume != nil {
+		volumes = append(volumes, *loggingVolume)
+	}
+	return append(volumes, getExtensionPodContribution(instance, ExtensionTargetUI).Volumes...)
+}
+
+// CreateControllerVolumes ...
+func CreateControllerVolumes(instance *kappnavv1.Kappnav) []corev1.Volume {
+	var volumes []corev1.Volume
+	if trustedCAVolume := createTrustedCAVolume(instance); trustedCAVolume != nil {
+		volumes = append(volumes, *trustedCAVolume)
+	}
+	if loggingVolume := createLoggingVolume(instance); loggingVolume != nil {
+		volumes = append(volumes, *loggingVolume)
+	}
+	return append(volumes, getExtensionPodContribution(instance, ExtensionTargetController).Volumes...)
+}
+
+// IsTrustedCAInjectionRequested returns true if the operator should create a ConfigMap
+// that OpenShift populates with the cluster's trusted CA bundle.
+func IsTrustedCAInjectionRequested(instance *kappnavv1.Kappnav) bool {
+	trustedCA := instance.Spec.TrustedCA
+	return trustedCA != nil && len(trustedCA.ConfigMapName) == 0 &&
+		trustedCA.InjectOpenShiftBundle && IsOpenShift(instance.Spec.Env.KubeEnv)
+}
+
+// GetTrustedCAConfigMapName returns the name of the ConfigMap holding the trusted CA
+// bundle, or an empty string if no trusted CA bundle is configured.
+func GetTrustedCAConfigMapName(instance *kappnavv1.Kappnav) string {
+	trustedCA := instance.Spec.TrustedCA
+	if trustedCA == nil {
+		return ""
+	}
+	if len(trustedCA.ConfigMapName) > 0 {
+		return trustedCA.ConfigMapName
+	}
+	if IsTrustedCAInjectionRequested(instance) {
+		return instance.GetName() + "-" + TrustedCAVolumeName
+	}
+	return ""
+}
+
+func createTrustedCAVolume(instance *kappnavv1.Kappnav) *corev1.Volume {
+	configMapName := GetTrustedCAConfigMapName(instance)
+	if len(configMapName) == 0 {
+		return nil
+	}
+	key := instance.Spec.TrustedCA.Key
+	if len(key) == 0 {
+		key = TrustedCAConfigMapKey
+	}
+	return &corev1.Volume{
+		Name: instance.Name + "-" + TrustedCAVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: configMapName,
+				},
+				Items: []corev1.KeyToPath{
+					{
+						Key:  key,
+						Path: TrustedCABundleFileName,
+					},
+				},
+			},
+		},
+	}
+}
+
+// trustedCAEnvVarNames are the environment variables pointing the OpenSSL/Go and
+// Node.js runtimes at the trusted CA bundle
+var trustedCAEnvVarNames = []string{"SSL_CERT_FILE", "NODE_EXTRA_CA_CERTS"}
+
+// addTrustedCA mounts the trusted CA bundle into the container and points the
+// OpenSSL/Go and Node.js runtimes at it.
+func addTrustedCA(container *corev1.Container, instance *kappnavv1.Kappnav) {
+	if len(GetTrustedCAConfigMapName(instance)) == 0 {
+		return
+	}
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      instance.Name + "-" + TrustedCAVolumeName,
+		MountPath: TrustedCAVolumeMountPath,
+		ReadOnly:  true,
+	})
+	for _, name := range trustedCAEnvVarNames {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  name,
+			Value: getTrustedCABundlePath(),
+		})
+	}
+}
+
+func getTrustedCABundlePath() string {
+	return TrustedCAVolumeMountPath + "/" + TrustedCABundleFileName
+}
+
+// isReservedEnvVar returns true if the variable of an existing container is owned
+// by the operator. The trusted CA variables are only owned by the operator when
+// they point at the bundle it mounts, so that the values set by users are kept
+// when no trusted CA is configured.
+func isReservedEnvVar(envVar corev1.EnvVar) bool {
+	for _, reserved := range reservedEnvVarNames {
+		if envVar.Name == reserved {
+			return true
+		}
+	}
+	for _, name := range trustedCAEnvVarNames {
+		if envVar.Name == name && envVar.Value == getTrustedCABundlePath() {
+			return true
+		}
+	}
+	return false
 }
 
 func createContainer(name string, instance *kappnavv1.Kappnav,
@@ -473,7 +630,7 @@ func createContainer(name string, instance *kappnavv1.Kappnav,
 	volumeMount *corev1.VolumeMount) *corev1.Container {
 	container := &corev1.Container{
 		Name:            name,
-		Image:           string(containerConfig.Repository) + ":" + string(containerConfig.Tag),
+		Image:           GetImageReference(instance, containerConfig),
 		ImagePullPolicy: instance.Spec.Image.PullPolicy,
 		Env: []corev1.EnvVar{
 			{
@@ -494,20 +651,26 @@ func createContainer(name string, instance *kappnavv1.Kappnav,
 		Ports:          ports,
 		Args:           args,
 	}
+	// Add volume mount if specified.
+	if volumeMount != nil {
+		container.VolumeMounts = []corev1.VolumeMount{*volumeMount}
+	}
+	// Mount the trusted CA bundle if configured.
+	addTrustedCA(container, instance)
+	// Pass the names of the resources of the CR.
+	addInstanceNamesEnv(container, instance)
+	// Set the proxy environment variables if configured.
+	addProxyEnv(container, instance)
+	// Pass the log levels of the component.
+	addLogging(container, instance)
 	// Copy custom environment variable settings.
 	if existingEnv != nil {
 		for _, envVar := range existingEnv {
-			if envVar.Name != "KAPPNAV_CR_NAME" &&
-				envVar.Name != "KAPPNAV_CONFIG_NAMESPACE" &&
-				envVar.Name != "KUBE_ENV" {
+			if !isReservedEnvVar(envVar) && !containEnvVar(container.Env, envVar.Name) {
 				container.Env = append(container.Env, envVar)
 			}
 		}
 	}
-	// Add volume mount if specified.
-	if volumeMount != nil {
-		container.VolumeMounts = []corev1.VolumeMount{*volumeMount}
-	}
 	// Apply resource constraints if enabled.
 	if containerConfig.Resources.Enabled {
 		container.Resources = corev1.ResourceRequirements{
@@ -698,7 +861,7 @@ type OCPClusterInfo struct {
 }
 
 func getOCPClusterInfo(logger Logger, r *ReconcilerBase) *OCPClusterInfo {
-	config, err := r.GetOperatorConfigMap(logger, "console-config", "openshift-console")
+	config, err := r.GetOperatorConfigMap(logger, OCPConsoleConfigMap.Name, OCPConsoleConfigMap.Namespace)
 	if err != nil {
 		if logger.IsEnabled(LogTypeError) {
 			logger.Log(CallerName(), LogTypeError, fmt.Sprintf("Could not retrieve console-config ConfigMap for OCP, Error: %s ", err), logName)
@@ -743,7 +906,7 @@ type OKDClusterInfo struct {
 }
 
 func getOKDClusterInfo(logger Logger, r *ReconcilerBase) *OKDClusterInfo {
-	config, err := r.GetOperatorConfigMap(logger, "webconsole-config", "openshift-web-console")
+	config, err := r.GetOperatorConfigMap(logger, OKDConsoleConfigMap.Name, OKDConsoleConfigMap.Namespace)
 	if err != nil {
 		if logger.IsEnabled(LogTypeError) {
 			logger.Log(CallerName(), LogTypeError, fmt.Sprintf("Could not retrieve webconsole-config ConfigMap for OKD, Error: %s ", err), logName)
@@ -842,6 +1005,16 @@ func FormatTimestamp(t time.Time) float64 {
 	return ts
 }
 
+//containEnvVar check if the environment variable is already set in env
+func containEnvVar(env []corev1.EnvVar, name string) bool {
+	for _, envVar := range env {
+		if envVar.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 //containsSecret check if sa.ImagePullSecrets contains secret added in kappnav CR
 func containSecret(array []corev1.LocalObjectReference, secretName string) bool {
 	if array != nil {
@@ -854,3 +1027,23 @@ func containSecret(array []corev1.LocalObjectReference, secretName string) bool
 	return false
 }
 
+//ContainString check if array contains value
+func ContainString(array []string, value string) bool {
+	for _, a := range array {
+		if a == value {
+			return true
+		}
+	}
+	return false
+}
+
+//RemoveString return a copy of array without value
+func RemoveString(array []string, value string) []string {
+	var result []string
+	for _, a := range array {
+		if a != value {
+			result = append(result, a)
+		}
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getEnvVarValue(env []corev1.EnvVar, name string) (string, bool) {
+	for _, envVar := range env {
+		if envVar.Name == name {
+			return envVar.Value, true
+		}
+	}
+	return "", false
+}
+
+func TestTrustedCAEnvVars(t *testing.T) {
+	userBundle := "/etc/ssl/user/bundle.pem"
+	tests := []struct {
+		name        string
+		trustedCA   *kappnavv1.KappnavTrustedCAConfiguration
+		existingEnv []corev1.EnvVar
+		want        string
+		wantSet     bool
+	}{
+		{
+			name:        "user value kept without trusted CA",
+			existingEnv: []corev1.EnvVar{{Name: "SSL_CERT_FILE", Value: userBundle}},
+			want:        userBundle,
+			wantSet:     true,
+		},
+		{
+			name:        "operator value dropped when the trusted CA is removed",
+			existingEnv: []corev1.EnvVar{{Name: "SSL_CERT_FILE", Value: getTrustedCABundlePath()}},
+		},
+		{
+			name:        "operator value replaces the user value",
+			trustedCA:   &kappnavv1.KappnavTrustedCAConfiguration{ConfigMapName: "ca"},
+			existingEnv: []corev1.EnvVar{{Name: "SSL_CERT_FILE", Value: userBundle}},
+			want:        getTrustedCABundlePath(),
+			wantSet:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			instance := &kappnavv1.Kappnav{
+				ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "kappnav"},
+				Spec: kappnavv1.KappnavSpec{
+					Env:       &kappnavv1.Environment{KubeEnv: "minikube"},
+					Image:     &kappnavv1.KappnavImageConfiguration{},
+					TrustedCA: test.trustedCA,
+				},
+			}
+			containerConfig := &kappnavv1.KappnavContainerConfiguration{
+				Repository: "kappnav/ui",
+				Tag:        "0.1.0",
+				Resources:  &kappnavv1.KappnavResourceConstraints{},
+			}
+			container := createContainer("kappnav-ui", instance, containerConfig, test.existingEnv, nil, nil, nil, nil, nil)
+			value, set := getEnvVarValue(container.Env, "SSL_CERT_FILE")
+			if set != test.wantSet || value != test.want {
+				t.Errorf("got SSL_CERT_FILE %q (set %t), want %q (set %t)", value, set, test.want, test.wantSet)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	kappnavutils "github.com/kappnav/operator/pkg/utils"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var logName = "webhook"
+
+// ConvertPath is the path the conversion webhook is served on
+const ConvertPath string = "/convert"
+
+// Converter converts the JSON of a custom resource to the desired apiVersion
+type Converter func(object []byte, fromAPIVersion string, toAPIVersion string) ([]byte, error)
+
+var (
+	convertersMutex sync.RWMutex
+	converters      = make(map[schema.GroupKind]Converter)
+)
+
+// AddConverter registers the converter used for the custom resources of a kind.
+func AddConverter(gk schema.GroupKind, converter Converter) {
+	convertersMutex.Lock()
+	defer convertersMutex.Unlock()
+	converters[gk] = converter
+}
+
+// ConversionHandler serves the CRD conversion webhook. It accepts ConversionReviews
+// of apiextensions.k8s.io/v1 and v1beta1, which share the same layout, and responds
+// with the apiVersion of the request.
+func ConversionHandler(w http.ResponseWriter, req *http.Request) {
+	logger := kappnavutils.NewLogger()
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	review := &apiextensionsv1beta1.ConversionReview{}
+	if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+		http.Error(w, fmt.Sprintf("invalid ConversionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	response := &apiextensionsv1beta1.ConversionResponse{
+		UID:    review.Request.UID,
+		Result: metav1.Status{Status: metav1.StatusSuccess},
+	}
+	for _, object := range review.Request.Objects {
+		converted, err := convert(object.Raw, review.Request.DesiredAPIVersion)
+		if err != nil {
+			if logger.IsEnabled(kappnavutils.LogTypeError) {
+				logger.Log(kappnavutils.CallerName(), kappnavutils.LogTypeError, "Conversion failed: "+err.Error(), logName)
+			}
+			response.ConvertedObjects = nil
+			response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			break
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+	review.Request = nil
+	review.Response = response
+
+	data, err := json.Marshal(review)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// convert converts a single object to the desired apiVersion
+func convert(object []byte, toAPIVersion string) ([]byte, error) {
+	typeMeta := &metav1.TypeMeta{}
+	if err := json.Unmarshal(object, typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.APIVersion == toAPIVersion {
+		return object, nil
+	}
+	from, err := schema.ParseGroupVersion(typeMeta.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := schema.ParseGroupVersion(toAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	if from.Group != to.Group {
+		return nil, fmt.Errorf("cannot convert %s from %s to %s", typeMeta.Kind, typeMeta.APIVersion, toAPIVersion)
+	}
+	convertersMutex.RLock()
+	converter, ok := converters[schema.GroupKind{Group: from.Group, Kind: typeMeta.Kind}]
+	convertersMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no converter for %s.%s", typeMeta.Kind, from.Group)
+	}
+	return converter(object, typeMeta.APIVersion, toAPIVersion)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+
+	kappnavv1 "github.com/kappnav/operator/pkg/apis/kappnav/v1"
+	kappnavv2 "github.com/kappnav/operator/pkg/apis/kappnav/v2"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func init() {
+	AddConverter(schema.GroupKind{Group: kappnavv1.SchemeGroupVersion.Group, Kind: "Kappnav"}, convertKappnav)
+}
+
+// convertKappnav converts a Kappnav between v1 and v2
+func convertKappnav(object []byte, fromAPIVersion string, toAPIVersion string) ([]byte, error) {
+	v1 := kappnavv1.SchemeGroupVersion.String()
+	v2 := kappnavv2.SchemeGroupVersion.String()
+	switch {
+	case fromAPIVersion == v1 && toAPIVersion == v2:
+		src := &kappnavv1.Kappnav{}
+		if err := json.Unmarshal(object, src); err != nil {
+			return nil, err
+		}
+		dst := &kappnavv2.Kappnav{}
+		if err := kappnavv2.ConvertFromV1(src, dst); err != nil {
+			return nil, err
+		}
+		return json.Marshal(dst)
+	case fromAPIVersion == v2 && toAPIVersion == v1:
+		src := &kappnavv2.Kappnav{}
+		if err := json.Unmarshal(object, src); err != nil {
+			return nil, err
+		}
+		dst := &kappnavv1.Kappnav{}
+		if err := kappnavv2.ConvertToV1(src, dst); err != nil {
+			return nil, err
+		}
+		return json.Marshal(dst)
+	}
+	return nil, fmt.Errorf("cannot convert Kappnav from %s to %s", fromAPIVersion, toAPIVersion)
+}
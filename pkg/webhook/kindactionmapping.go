@@ -0,0 +1,56 @@
+/*
+Copyright 2020 IBM Corporation
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+
+	kamv1 "github.com/kappnav/operator/pkg/apis/actions/v1"
+	kamv2 "github.com/kappnav/operator/pkg/apis/actions/v2"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func init() {
+	AddConverter(schema.GroupKind{Group: kamv1.SchemeGroupVersion.Group, Kind: "KindActionMapping"}, convertKindActionMapping)
+}
+
+// convertKindActionMapping converts a KindActionMapping between v1 and v2
+func convertKindActionMapping(object []byte, fromAPIVersion string, toAPIVersion string) ([]byte, error) {
+	v1 := kamv1.SchemeGroupVersion.String()
+	v2 := kamv2.SchemeGroupVersion.String()
+	switch {
+	case fromAPIVersion == v1 && toAPIVersion == v2:
+		src := &kamv1.KindActionMapping{}
+		if err := json.Unmarshal(object, src); err != nil {
+			return nil, err
+		}
+		dst := &kamv2.KindActionMapping{}
+		if err := kamv2.ConvertFromV1(src, dst); err != nil {
+			return nil, err
+		}
+		return json.Marshal(dst)
+	case fromAPIVersion == v2 && toAPIVersion == v1:
+		src := &kamv2.KindActionMapping{}
+		if err := json.Unmarshal(object, src); err != nil {
+			return nil, err
+		}
+		dst := &kamv1.KindActionMapping{}
+		if err := kamv2.ConvertToV1(src, dst); err != nil {
+			return nil, err
+		}
+		return json.Marshal(dst)
+	}
+	return nil, fmt.Errorf("cannot convert KindActionMapping from %s to %s", fromAPIVersion, toAPIVersion)
+}
@@ -12,6 +12,7 @@ mv kappnav-delete.yaml updatedYaml
 mv kappnav-delete-CR.yaml updatedYaml
 mv deploy/default_values.yaml updatedYaml
 mv deploy/operator.yaml updatedYaml
+mv version/version.go updatedYaml
 
 # Now that we've built the image and saved the updated files we can restore the original files
 mv backup/kappnav.yaml kappnav.yaml
@@ -19,4 +20,5 @@ mv backup/kappnav-delete.yaml kappnav-delete.yaml
 mv backup/kappnav-delete-CR.yaml kappnav-delete-CR.yaml
 mv backup/default_values.yaml deploy/default_values.yaml
 mv backup/operator.yaml deploy/operator.yaml
+mv backup/version.go version/version.go
 rmdir backup
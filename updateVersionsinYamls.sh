@@ -11,6 +11,7 @@ cp kappnav-delete.yaml backup/kappnav-delete.yaml
 cp kappnav-delete-CR.yaml backup/kappnav-delete-CR.yaml
 cp deploy/default_values.yaml backup/default_values.yaml
 cp deploy/operator.yaml backup/operator.yaml
+cp version/version.go backup/version.go
 
 # update the version numbers in the kappnav install yaml files
 . ../build/version.sh
@@ -34,3 +35,7 @@ cat backup/default_values.yaml \
 cat backup/operator.yaml \
 | sed "s|KAPPNAV_VERSION|$VERSION|g" \
 > deploy/operator.yaml
+
+cat backup/version.go \
+| sed "s|KAPPNAV_VERSION|$VERSION|g" \
+> version/version.go
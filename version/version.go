@@ -1,6 +1,6 @@
 package version
 
 var (
-	// Version ...
-	Version = "0.0.1"
+	// Version is the kAppNav version, set by updateVersionsinYamls.sh at build time
+	Version = "KAPPNAV_VERSION"
 )